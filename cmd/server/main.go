@@ -5,7 +5,10 @@ import (
 	"flag"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/indexer"
 	"github.com/cloudnationhq/az-cn-go-wammcp/pkg/mcp"
 )
 
@@ -13,6 +16,12 @@ func main() {
 	org := flag.String("org", "cloudnationhq", "GitHub organization name")
 	token := flag.String("token", "", "GitHub personal access token (optional, for higher rate limits)")
 	dbPath := flag.String("db", "index.db", "Path to SQLite database file")
+	source := flag.String("source", "github", "Module source: github or local")
+	localPath := flag.String("path", "", "Directory of cloned module repositories named with -prefix (with -source local)")
+	workers := flag.Int("workers", 4, "Number of repositories synced in parallel")
+	maxFileSize := flag.Int64("max-file-size", 1<<20, "Largest file in bytes stored during sync; .tf, .md, .yml and .json files are always kept up to 8MB")
+	prefix := flag.String("prefix", indexer.DefaultRepoPrefix, "Name prefix of the repositories to sync; empty matches every repository name (archived repositories still need -include-archived)")
+	includeArchived := flag.Bool("include-archived", false, "Also sync archived repositories; their modules are marked as archived")
 	flag.Parse()
 
 	log.SetOutput(os.Stderr)
@@ -20,7 +29,33 @@ func main() {
 	log.Printf("Database will be initialized at: %s (on first sync)", *dbPath)
 
 	server := mcp.NewServer(*dbPath, *token, *org)
-	if err := server.Run(context.Background(), os.Stdin, os.Stdout); err != nil {
+	if *workers < 1 {
+		log.Fatal("-workers must be at least 1")
+	}
+	server.SetSyncWorkers(*workers)
+	if *maxFileSize < 1 {
+		log.Fatal("-max-file-size must be at least 1")
+	}
+	server.SetMaxFileSize(*maxFileSize)
+	server.SetRepoPrefix(*prefix)
+	server.SetIncludeArchived(*includeArchived)
+	switch *source {
+	case "github":
+	case "local":
+		if *localPath == "" {
+			log.Fatal("-source local requires -path")
+		}
+		log.Printf("Indexing modules from local mirror: %s", *localPath)
+		server.UseLocalSource(*localPath)
+	default:
+		log.Fatalf("unknown source %q (expected github or local)", *source)
+	}
+
+	// Interrupts cancel any running sync before the process exits.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := server.Run(ctx, os.Stdin, os.Stdout); err != nil {
 		log.Printf("Server stopped: %v", err)
 	}
 }
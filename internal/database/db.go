@@ -2,20 +2,29 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/util"
 	_ "github.com/mattn/go-sqlite3"
 )
 
 type DB struct {
-	conn *sql.DB
+	conn dbConn
+	pool *sql.DB // nil when the DB is bound to a transaction
+	fts  bool    // FTS5 indexes are available
 }
 
-type execer interface {
+// dbConn is the query surface shared by *sql.DB and *sql.Tx, so the same
+// methods run inside and outside a transaction.
+type dbConn interface {
 	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
 }
 
 func minInt(a, b int) int {
@@ -35,6 +44,10 @@ type Module struct {
 	SyncedAt      time.Time
 	ReadmeContent string
 	HasExamples   bool
+	Org           string
+	CommitSHA     string
+	Archived      bool // the repository is archived on GitHub
+	NoTerraform   bool // the last sync found no .tf files to index
 }
 
 type ModuleFile struct {
@@ -56,6 +69,28 @@ type ModuleVariable struct {
 	DefaultValue string
 	Required     bool
 	Sensitive    bool
+	Nullable     bool // false only when declared with nullable = false
+	SourceFile   string
+	Block        string // source text of the whole variable block
+	Validations  []VariableValidation
+	// OptionalAttributes are the optional(...) attributes of an object type
+	// constraint, at any nesting depth, in source order.
+	OptionalAttributes []OptionalAttribute
+}
+
+// OptionalAttribute is an object attribute declared with optional(type,
+// default). Path is dotted, with [*] marking collection elements, e.g.
+// subnets[*].service_endpoints. Default is empty when none is given.
+type OptionalAttribute struct {
+	Path    string
+	Type    string
+	Default string
+}
+
+// VariableValidation is one validation block of a variable, in source order.
+type VariableValidation struct {
+	Condition    string
+	ErrorMessage string
 }
 
 type ModuleOutput struct {
@@ -65,6 +100,7 @@ type ModuleOutput struct {
 	Description string
 	Value       string
 	Sensitive   bool
+	SourceFile  string
 }
 
 type ModuleResource struct {
@@ -74,6 +110,14 @@ type ModuleResource struct {
 	ResourceName string
 	Provider     string
 	SourceFile   string
+	Attributes   []ResourceAttribute
+}
+
+// ResourceAttribute is a top-level argument or nested block type set on a
+// resource, in source order. Kind is attribute, block or dynamic.
+type ResourceAttribute struct {
+	Name string
+	Kind string
 }
 
 type ModuleDataSource struct {
@@ -85,6 +129,41 @@ type ModuleDataSource struct {
 	SourceFile string
 }
 
+// ModuleDependency is a module block calling another module. SourceKind is
+// local, registry, git or other.
+type ModuleDependency struct {
+	ID         int64
+	ModuleID   int64
+	Name       string
+	Source     string
+	SourceKind string
+	Version    string
+	FilePath   string
+}
+
+// ModuleStateMigration is a moved, removed or import block. Moved blocks fill
+// From and To, removed blocks only From, import blocks ImportID and To.
+type ModuleStateMigration struct {
+	ID          int64
+	ModuleID    int64
+	BlockType   string
+	FromAddress string
+	ToAddress   string
+	ImportID    string
+	FilePath    string
+	Line        int
+}
+
+// ModuleLocal is one named value of a locals block with its raw expression.
+type ModuleLocal struct {
+	ID         int64
+	ModuleID   int64
+	Name       string
+	Expression string
+	FilePath   string
+	Line       int
+}
+
 type ModuleExample struct {
 	ID       int64
 	ModuleID int64
@@ -93,6 +172,13 @@ type ModuleExample struct {
 	Content  string
 }
 
+type ModuleTerraformVersion struct {
+	ID              int64
+	ModuleID        int64
+	RequiredVersion string
+	SourceFile      string
+}
+
 type ModuleAlias struct {
 	ID       int64
 	ModuleID int64
@@ -101,6 +187,10 @@ type ModuleAlias struct {
 	Source   sql.NullString
 }
 
+// TagSourceTopic marks module tags that come from GitHub repository topics
+// rather than being derived from the module's contents.
+const TagSourceTopic = "topic"
+
 type ModuleTag struct {
 	ID       int64
 	ModuleID int64
@@ -109,6 +199,24 @@ type ModuleTag struct {
 	Source   sql.NullString
 }
 
+type ModuleProvider struct {
+	ID         int64
+	ModuleID   int64
+	Name       string
+	Source     string
+	Version    string
+	SourceFile string
+	IsPrimary  bool
+}
+
+type ModuleParseError struct {
+	ID         int64
+	ModuleID   int64
+	ModuleName string
+	FilePath   string
+	Message    string
+}
+
 type ModuleRelease struct {
 	ID                int64
 	ModuleID          int64
@@ -120,6 +228,7 @@ type ModuleRelease struct {
 	PreviousCommitSHA sql.NullString
 	ReleaseDate       sql.NullString
 	ComparisonURL     sql.NullString
+	Contributors      sql.NullString // comma-separated GitHub logins or author names
 	CreatedAt         time.Time
 }
 
@@ -141,6 +250,7 @@ type HCLBlock struct {
 	FilePath  string
 	BlockType string
 	TypeLabel sql.NullString
+	NameLabel sql.NullString
 	StartByte int64
 	EndByte   int64
 	AttrPaths sql.NullString
@@ -160,7 +270,7 @@ type HCLRelationship struct {
 }
 
 func New(dbPath string) (*DB, error) {
-	conn, err := sql.Open("sqlite3", dbPath)
+	conn, err := sql.Open("sqlite3", sqliteDSN(dbPath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -180,11 +290,61 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
 
-	return &DB{conn: conn}, nil
+	if err := migrateSchema(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	fts := true
+	if _, err := conn.Exec(FTSSchema); err != nil {
+		if !strings.Contains(err.Error(), "no such module: fts5") {
+			conn.Close()
+			return nil, fmt.Errorf("failed to initialize full-text indexes: %w", err)
+		}
+		log.Printf("Warning: SQLite was built without FTS5; searches fall back to LIKE scans")
+		fts = false
+	}
+
+	return &DB{conn: conn, pool: conn, fts: fts}, nil
+}
+
+// sqliteDSN opens transactions with BEGIN IMMEDIATE so concurrent writers
+// wait for the write lock up front instead of failing with SQLITE_BUSY when
+// a read lock is upgraded, and gives them enough time to wait out a module
+// being indexed in another transaction.
+func sqliteDSN(dbPath string) string {
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	return dbPath + sep + "_txlock=immediate&_busy_timeout=30000"
+}
+
+// WithTx runs fn against a DB bound to a single transaction, committing when
+// fn returns nil and rolling back otherwise. Every method called on tx joins
+// the transaction, including nested WithTx calls.
+func (db *DB) WithTx(fn func(tx *DB) error) error {
+	if db.pool == nil {
+		return fn(db)
+	}
+
+	sqlTx, err := db.pool.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer sqlTx.Rollback()
+
+	if err := fn(&DB{conn: sqlTx, fts: db.fts}); err != nil {
+		return err
+	}
+	return sqlTx.Commit()
 }
 
 func (db *DB) Close() error {
-	return db.conn.Close()
+	if db.pool == nil {
+		return fmt.Errorf("cannot close a transaction-bound database")
+	}
+	return db.pool.Close()
 }
 
 func enableIncrementalAutoVacuum(conn *sql.DB) error {
@@ -204,6 +364,55 @@ func enableIncrementalAutoVacuum(conn *sql.DB) error {
 	return nil
 }
 
+// migrateSchema adds columns introduced after a table was first created;
+// CREATE TABLE IF NOT EXISTS leaves existing databases untouched.
+func migrateSchema(conn *sql.DB) error {
+	for _, c := range schemaColumns {
+		if err := addColumnIfMissing(conn, c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addColumnIfMissing(conn *sql.DB, table, column, definition string) error {
+	rows, err := conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+			return fmt.Errorf("failed to inspect table %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
 func escapeFTS5(query string) string {
 	query = strings.ReplaceAll(query, `"`, `""`)
 	return `"` + query + `"`
@@ -211,8 +420,8 @@ func escapeFTS5(query string) string {
 
 func (db *DB) InsertModule(m *Module) (int64, error) {
 	_, err := db.conn.Exec(`
-		INSERT INTO modules (name, full_name, description, repo_url, last_updated, readme_content, has_examples)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO modules (name, full_name, description, repo_url, last_updated, readme_content, has_examples, org, archived)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(name) DO UPDATE SET
 			full_name = excluded.full_name,
 			description = excluded.description,
@@ -220,8 +429,10 @@ func (db *DB) InsertModule(m *Module) (int64, error) {
 			last_updated = excluded.last_updated,
 			readme_content = excluded.readme_content,
 			has_examples = excluded.has_examples,
+			org = excluded.org,
+			archived = excluded.archived,
 			synced_at = CURRENT_TIMESTAMP
-	`, m.Name, m.FullName, m.Description, m.RepoURL, m.LastUpdated, m.ReadmeContent, m.HasExamples)
+	`, m.Name, m.FullName, m.Description, m.RepoURL, m.LastUpdated, normalizeLineEndings(m.ReadmeContent), m.HasExamples, m.Org, m.Archived)
 	if err != nil {
 		return 0, err
 	}
@@ -234,12 +445,40 @@ func (db *DB) InsertModule(m *Module) (int64, error) {
 	return id, nil
 }
 
-func (db *DB) GetModule(name string) (*Module, error) {
+// moduleColumns is the column list scanModule reads, for queries that alias
+// the modules table as m.
+const moduleColumns = `m.id, m.name, m.full_name, m.description, m.repo_url, m.last_updated, m.synced_at, m.readme_content, m.has_examples, m.org, COALESCE(m.commit_sha, ''), m.archived, m.no_terraform`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanModule reads a row selected with moduleColumns. extra receives any
+// columns the query selects after them.
+func scanModule(row rowScanner, extra ...any) (Module, error) {
 	var m Module
-	err := db.conn.QueryRow(`
-		SELECT id, name, full_name, description, repo_url, last_updated, synced_at, readme_content, has_examples
-		FROM modules WHERE name = ?
-	`, name).Scan(&m.ID, &m.Name, &m.FullName, &m.Description, &m.RepoURL, &m.LastUpdated, &m.SyncedAt, &m.ReadmeContent, &m.HasExamples)
+	dest := append([]any{&m.ID, &m.Name, &m.FullName, &m.Description, &m.RepoURL, &m.LastUpdated, &m.SyncedAt, &m.ReadmeContent, &m.HasExamples, &m.Org, &m.CommitSHA, &m.Archived, &m.NoTerraform}, extra...)
+	err := row.Scan(dest...)
+	return m, err
+}
+
+func scanModules(rows *sql.Rows) ([]Module, error) {
+	var modules []Module
+	for rows.Next() {
+		m, err := scanModule(rows)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, m)
+	}
+	return modules, rows.Err()
+}
+
+func (db *DB) GetModule(name string) (*Module, error) {
+	m, err := scanModule(db.conn.QueryRow(`
+		SELECT `+moduleColumns+`
+		FROM modules m WHERE name = ?
+	`, name))
 	if err != nil {
 		return nil, err
 	}
@@ -247,11 +486,10 @@ func (db *DB) GetModule(name string) (*Module, error) {
 }
 
 func (db *DB) GetModuleByID(id int64) (*Module, error) {
-	var m Module
-	err := db.conn.QueryRow(`
-		SELECT id, name, full_name, description, repo_url, last_updated, synced_at, readme_content, has_examples
-		FROM modules WHERE id = ?
-	`, id).Scan(&m.ID, &m.Name, &m.FullName, &m.Description, &m.RepoURL, &m.LastUpdated, &m.SyncedAt, &m.ReadmeContent, &m.HasExamples)
+	m, err := scanModule(db.conn.QueryRow(`
+		SELECT `+moduleColumns+`
+		FROM modules m WHERE id = ?
+	`, id))
 	if err != nil {
 		return nil, err
 	}
@@ -260,50 +498,223 @@ func (db *DB) GetModuleByID(id int64) (*Module, error) {
 
 func (db *DB) ListModules() ([]Module, error) {
 	rows, err := db.conn.Query(`
-		SELECT id, name, full_name, description, repo_url, last_updated, synced_at, readme_content, has_examples
-		FROM modules ORDER BY name
+		SELECT ` + moduleColumns + `
+		FROM modules m ORDER BY name
 	`)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	return scanModules(rows)
+}
+
+// ListModuleNames returns every module with only its ID and name set, for
+// callers that rank names without needing the rest of the record.
+func (db *DB) ListModuleNames() ([]Module, error) {
+	rows, err := db.conn.Query(`SELECT id, name FROM modules ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
 	var modules []Module
 	for rows.Next() {
 		var m Module
-		if err := rows.Scan(&m.ID, &m.Name, &m.FullName, &m.Description, &m.RepoURL, &m.LastUpdated, &m.SyncedAt, &m.ReadmeContent, &m.HasExamples); err != nil {
+		if err := rows.Scan(&m.ID, &m.Name); err != nil {
 			return nil, err
 		}
 		modules = append(modules, m)
 	}
-
 	return modules, rows.Err()
 }
 
-func (db *DB) SearchModules(query string, limit int) ([]Module, error) {
+func (db *DB) ListModulesByOrg(org string) ([]Module, error) {
+	rows, err := db.conn.Query(`
+		SELECT `+moduleColumns+`
+		FROM modules m WHERE lower(org) = lower(?) ORDER BY name
+	`, org)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanModules(rows)
+}
+
+// ListModulesByProvider returns the modules whose indexed providers include
+// the given one, e.g. azurerm.
+func (db *DB) ListModulesByProvider(provider string) ([]Module, error) {
+	rows, err := db.conn.Query(`
+		SELECT `+moduleColumns+`
+		FROM modules m
+		WHERE m.id IN (SELECT module_id FROM module_providers WHERE lower(name) = lower(?))
+		ORDER BY name
+	`, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanModules(rows)
+}
+
+// ListRecentlyUpdatedModules returns up to limit modules ordered by their
+// repository's last update, newest first. last_updated holds RFC 3339 UTC
+// timestamps, so ordering the text orders the times.
+func (db *DB) ListRecentlyUpdatedModules(limit int) ([]Module, error) {
 	rows, err := db.conn.Query(`
-		SELECT m.id, m.name, m.full_name, m.description, m.repo_url, m.last_updated, m.synced_at, m.readme_content, m.has_examples
+		SELECT `+moduleColumns+`
 		FROM modules m
-		JOIN modules_fts ON modules_fts.rowid = m.id
-		WHERE modules_fts MATCH ?
-		ORDER BY rank
+		WHERE COALESCE(last_updated, '') != ''
+		ORDER BY last_updated DESC, name
 		LIMIT ?
-	`, escapeFTS5(query), limit)
+	`, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var modules []Module
+	return scanModules(rows)
+}
+
+// GetProvidersByModule maps each module ID to its indexed providers, the
+// primary provider first and the rest sorted by name.
+func (db *DB) GetProvidersByModule() (map[int64][]string, error) {
+	rows, err := db.conn.Query(`
+		SELECT module_id, name
+		FROM module_providers
+		WHERE name != ''
+		ORDER BY module_id, is_primary DESC, name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	providers := make(map[int64][]string)
 	for rows.Next() {
-		var m Module
-		if err := rows.Scan(&m.ID, &m.Name, &m.FullName, &m.Description, &m.RepoURL, &m.LastUpdated, &m.SyncedAt, &m.ReadmeContent, &m.HasExamples); err != nil {
+		var (
+			moduleID int64
+			provider string
+		)
+		if err := rows.Scan(&moduleID, &provider); err != nil {
 			return nil, err
 		}
-		modules = append(modules, m)
+		providers[moduleID] = append(providers[moduleID], provider)
 	}
+	return providers, rows.Err()
+}
 
-	return modules, rows.Err()
+// Submodule is a nested module record (parent//modules/name) together with
+// the number of files and resources indexed for it.
+type Submodule struct {
+	Module
+	FileCount     int
+	ResourceCount int
+}
+
+func (db *DB) ListSubmodules(parentName string) ([]Submodule, error) {
+	rows, err := db.conn.Query(`
+		SELECT `+moduleColumns+`,
+		       (SELECT COUNT(*) FROM module_files f WHERE f.module_id = m.id),
+		       (SELECT COUNT(*) FROM module_resources r WHERE r.module_id = m.id)
+		FROM modules m
+		WHERE m.name LIKE ? ESCAPE '\'
+		ORDER BY m.name
+	`, escapeLike(parentName)+"//%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var submodules []Submodule
+	for rows.Next() {
+		var sm Submodule
+		m, err := scanModule(rows, &sm.FileCount, &sm.ResourceCount)
+		if err != nil {
+			return nil, err
+		}
+		sm.Module = m
+		submodules = append(submodules, sm)
+	}
+
+	return submodules, rows.Err()
+}
+
+// SearchModules returns modules whose name, description or README match
+// query, followed by modules tagged with a matching GitHub topic.
+func (db *DB) SearchModules(query string, limit int) ([]Module, error) {
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if db.fts {
+		rows, err = db.conn.Query(`
+			SELECT `+moduleColumns+`
+			FROM modules m
+			JOIN modules_fts ON modules_fts.rowid = m.id
+			WHERE modules_fts MATCH ?
+			ORDER BY rank
+			LIMIT ?
+		`, escapeFTS5(query), limit)
+	} else {
+		pattern := "%" + escapeLike(query) + "%"
+		rows, err = db.conn.Query(`
+			SELECT `+moduleColumns+`
+			FROM modules m
+			WHERE name LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\' OR readme_content LIKE ? ESCAPE '\'
+			ORDER BY CASE WHEN name LIKE ? ESCAPE '\' THEN 0 ELSE 1 END, name
+			LIMIT ?
+		`, pattern, pattern, pattern, pattern, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	modules, err := scanModules(rows)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int64]struct{}, len(modules))
+	for _, m := range modules {
+		seen[m.ID] = struct{}{}
+	}
+
+	tagged, err := db.searchModulesByTopic(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range tagged {
+		if limit > 0 && len(modules) >= limit {
+			break
+		}
+		if _, ok := seen[m.ID]; !ok {
+			modules = append(modules, m)
+		}
+	}
+	return modules, nil
+}
+
+func (db *DB) searchModulesByTopic(query string, limit int) ([]Module, error) {
+	topic := util.NormalizeQuery(query)
+	if topic == "" {
+		return nil, nil
+	}
+	rows, err := db.conn.Query(`
+		SELECT `+moduleColumns+`
+		FROM modules m
+		WHERE id IN (SELECT module_id FROM module_tags WHERE source = ? AND tag LIKE ? ESCAPE '\')
+		ORDER BY name
+		LIMIT ?
+	`, TagSourceTopic, "%"+escapeLike(topic)+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanModules(rows)
 }
 
 func (db *DB) InsertFile(f *ModuleFile) error {
@@ -315,11 +726,17 @@ func (db *DB) InsertFile(f *ModuleFile) error {
 			file_type = excluded.file_type,
 			content = excluded.content,
 			size_bytes = excluded.size_bytes
-	`, f.ModuleID, f.FileName, f.FilePath, f.FileType, f.Content, f.SizeBytes)
+	`, f.ModuleID, f.FileName, f.FilePath, f.FileType, normalizeLineEndings(f.Content), f.SizeBytes)
 
 	return err
 }
 
+// normalizeLineEndings converts CRLF line endings to LF so files authored on
+// Windows don't leak stray carriage returns into line-based tools.
+func normalizeLineEndings(content string) string {
+	return strings.ReplaceAll(content, "\r\n", "\n")
+}
+
 func (db *DB) GetModuleFiles(moduleID int64) ([]ModuleFile, error) {
 	rows, err := db.conn.Query(`
 		SELECT id, module_id, file_name, file_path, file_type, content, size_bytes
@@ -342,15 +759,14 @@ func (db *DB) GetModuleFiles(moduleID int64) ([]ModuleFile, error) {
 	return files, rows.Err()
 }
 
-func (db *DB) SearchFiles(query string, limit int) ([]ModuleFile, error) {
+// ListAllFiles returns every indexed file, ordered by module and path. It is
+// used by searches the FTS index cannot answer, such as regular expressions.
+func (db *DB) ListAllFiles() ([]ModuleFile, error) {
 	rows, err := db.conn.Query(`
-		SELECT mf.id, mf.module_id, mf.file_name, mf.file_path, mf.file_type, mf.content, mf.size_bytes
-		FROM module_files mf
-		JOIN files_fts ON files_fts.rowid = mf.id
-		WHERE files_fts MATCH ?
-		ORDER BY rank
-		LIMIT ?
-	`, escapeFTS5(query), limit)
+		SELECT id, module_id, file_name, file_path, file_type, content, size_bytes
+		FROM module_files
+		ORDER BY module_id, file_path
+	`)
 	if err != nil {
 		return nil, err
 	}
@@ -368,39 +784,124 @@ func (db *DB) SearchFiles(query string, limit int) ([]ModuleFile, error) {
 	return files, rows.Err()
 }
 
-func (db *DB) SearchFilesFTS(match string, limit int) ([]ModuleFile, error) {
+// FileSearchHit is a file matched by SearchFiles. Score is the BM25
+// relevance (higher is better) and Snippet the best-matching fragment with
+// matched terms wrapped in « »; both are empty on the LIKE fallback.
+type FileSearchHit struct {
+	ModuleFile
+	Score   float64
+	Snippet string
+}
+
+// SearchFiles finds files containing any of the given terms, most relevant
+// first. File names and paths weigh more than content. A non-zero moduleID
+// restricts the search to that module and a non-empty fileType to files with
+// that name (variables.tf) or extension (.tf). Without FTS5 it falls back to
+// a LIKE scan ordered by path.
+func (db *DB) SearchFiles(terms []string, moduleID int64, fileType string, limit int) ([]FileSearchHit, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	namePattern := fileNamePattern(fileType)
+	if !db.fts {
+		return db.searchFilesLike(terms, moduleID, namePattern, limit)
+	}
+
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, escapeFTS5(term))
+	}
+
 	rows, err := db.conn.Query(`
-        SELECT mf.id, mf.module_id, mf.file_name, mf.file_path, mf.file_type, mf.content, mf.size_bytes
-        FROM module_files mf
-        JOIN files_fts ON files_fts.rowid = mf.id
-        WHERE files_fts MATCH ?
-        ORDER BY rank
-        LIMIT ?
-    `, match, limit)
+		SELECT mf.id, mf.module_id, mf.file_name, mf.file_path, mf.file_type, mf.content, mf.size_bytes,
+		       -bm25(files_fts, 5.0, 3.0, 1.0) AS score,
+		       snippet(files_fts, 2, '«', '»', '…', 12)
+		FROM module_files mf
+		JOIN files_fts ON files_fts.rowid = mf.id
+		WHERE files_fts MATCH ? AND (? = 0 OR mf.module_id = ?) AND mf.file_name LIKE ? ESCAPE '\'
+		ORDER BY score DESC
+		LIMIT ?
+	`, strings.Join(parts, " OR "), moduleID, moduleID, namePattern, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var files []ModuleFile
+	var hits []FileSearchHit
 	for rows.Next() {
-		var f ModuleFile
-		if err := rows.Scan(&f.ID, &f.ModuleID, &f.FileName, &f.FilePath, &f.FileType, &f.Content, &f.SizeBytes); err != nil {
+		var h FileSearchHit
+		if err := rows.Scan(&h.ID, &h.ModuleID, &h.FileName, &h.FilePath, &h.FileType, &h.Content, &h.SizeBytes, &h.Score, &h.Snippet); err != nil {
 			return nil, err
 		}
-		files = append(files, f)
+		hits = append(hits, h)
 	}
-	return files, rows.Err()
+	return hits, rows.Err()
+}
+
+func (db *DB) searchFilesLike(terms []string, moduleID int64, namePattern string, limit int) ([]FileSearchHit, error) {
+	conditions := make([]string, 0, len(terms))
+	args := make([]any, 0, len(terms)+4)
+	for _, term := range terms {
+		conditions = append(conditions, `content LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(term)+"%")
+	}
+	args = append(args, moduleID, moduleID, namePattern, limit)
+
+	rows, err := db.conn.Query(`
+		SELECT id, module_id, file_name, file_path, file_type, content, size_bytes
+		FROM module_files
+		WHERE (`+strings.Join(conditions, " OR ")+`) AND (? = 0 OR module_id = ?) AND file_name LIKE ? ESCAPE '\'
+		ORDER BY module_id, file_path
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []FileSearchHit
+	for rows.Next() {
+		var h FileSearchHit
+		if err := rows.Scan(&h.ID, &h.ModuleID, &h.FileName, &h.FilePath, &h.FileType, &h.Content, &h.SizeBytes); err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
+
+// fileNamePattern turns a file name or extension filter into a LIKE
+// pattern; an empty filter matches every file.
+func fileNamePattern(fileType string) string {
+	fileType = strings.TrimSpace(fileType)
+	if fileType == "" {
+		return "%"
+	}
+	if strings.HasPrefix(fileType, ".") {
+		return "%" + escapeLike(fileType)
+	}
+	return escapeLike(fileType)
 }
 
+// GetFile looks up a file by its repository-relative path. Submodules
+// (repo//modules/name) store their files under modules/name/, so for them a
+// path relative to the submodule directory is accepted as well.
 func (db *DB) GetFile(moduleName string, filePath string) (*ModuleFile, error) {
+	filePath = strings.TrimPrefix(filePath, "/")
+	nestedPath := filePath
+	if _, subPath, ok := strings.Cut(moduleName, "//"); ok && !strings.HasPrefix(filePath, subPath+"/") {
+		nestedPath = subPath + "/" + filePath
+	}
+
 	var f ModuleFile
 	err := db.conn.QueryRow(`
 		SELECT mf.id, mf.module_id, mf.file_name, mf.file_path, mf.file_type, mf.content, mf.size_bytes
 		FROM module_files mf
 		JOIN modules m ON m.id = mf.module_id
-		WHERE m.name = ? AND mf.file_path = ?
-	`, moduleName, filePath).Scan(&f.ID, &f.ModuleID, &f.FileName, &f.FilePath, &f.FileType, &f.Content, &f.SizeBytes)
+		WHERE m.name = ? AND mf.file_path IN (?, ?)
+		ORDER BY mf.file_path = ? DESC
+		LIMIT 1
+	`, moduleName, filePath, nestedPath, nestedPath).Scan(&f.ID, &f.ModuleID, &f.FileName, &f.FilePath, &f.FileType, &f.Content, &f.SizeBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -408,17 +909,45 @@ func (db *DB) GetFile(moduleName string, filePath string) (*ModuleFile, error) {
 }
 
 func (db *DB) InsertVariable(v *ModuleVariable) error {
-	_, err := db.conn.Exec(`
-		INSERT INTO module_variables (module_id, name, type, description, default_value, required, sensitive)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, v.ModuleID, v.Name, v.Type, v.Description, v.DefaultValue, v.Required, v.Sensitive)
-	return err
+	result, err := db.conn.Exec(`
+		INSERT INTO module_variables (module_id, name, type, description, default_value, required, sensitive, nullable, source_file, block_source)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, v.ModuleID, v.Name, v.Type, v.Description, v.DefaultValue, v.Required, v.Sensitive, v.Nullable, v.SourceFile, v.Block)
+	if err != nil {
+		return err
+	}
+
+	variableID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	v.ID = variableID
+
+	for i, rule := range v.Validations {
+		if _, err := db.conn.Exec(`
+			INSERT INTO variable_validations (variable_id, module_id, condition, error_message, order_index)
+			VALUES (?, ?, ?, ?, ?)
+		`, variableID, v.ModuleID, rule.Condition, rule.ErrorMessage, i); err != nil {
+			return err
+		}
+	}
+	for i, attr := range v.OptionalAttributes {
+		if _, err := db.conn.Exec(`
+			INSERT INTO variable_optional_attributes (variable_id, module_id, path, type, default_value, order_index)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, variableID, v.ModuleID, attr.Path, attr.Type, attr.Default, i); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (db *DB) GetModuleVariables(moduleID int64) ([]ModuleVariable, error) {
 	rows, err := db.conn.Query(`
-		SELECT id, module_id, name, type, description, default_value, required, sensitive
+		SELECT id, module_id, name, type, description, default_value, required, sensitive, nullable,
+		       COALESCE(source_file, ''), COALESCE(block_source, '')
 		FROM module_variables WHERE module_id = ?
+		ORDER BY id
 	`, moduleID)
 	if err != nil {
 		return nil, err
@@ -428,27 +957,229 @@ func (db *DB) GetModuleVariables(moduleID int64) ([]ModuleVariable, error) {
 	var vars []ModuleVariable
 	for rows.Next() {
 		var v ModuleVariable
-		if err := rows.Scan(&v.ID, &v.ModuleID, &v.Name, &v.Type, &v.Description, &v.DefaultValue, &v.Required, &v.Sensitive); err != nil {
+		if err := rows.Scan(&v.ID, &v.ModuleID, &v.Name, &v.Type, &v.Description, &v.DefaultValue, &v.Required, &v.Sensitive, &v.Nullable, &v.SourceFile, &v.Block); err != nil {
 			return nil, err
 		}
 		vars = append(vars, v)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return vars, rows.Err()
-}
-
-func (db *DB) InsertOutput(o *ModuleOutput) error {
-	_, err := db.conn.Exec(`
-		INSERT INTO module_outputs (module_id, name, description, value, sensitive)
-		VALUES (?, ?, ?, ?, ?)
-	`, o.ModuleID, o.Name, o.Description, o.Value, o.Sensitive)
+	validations, err := db.getVariableValidations(moduleID)
+	if err != nil {
+		return nil, err
+	}
+	optional, err := db.getVariableOptionalAttributes(moduleID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range vars {
+		vars[i].Validations = validations[vars[i].ID]
+		vars[i].OptionalAttributes = optional[vars[i].ID]
+	}
+
+	return vars, nil
+}
+
+// getVariableOptionalAttributes loads a module's optional object attributes
+// keyed by variable ID.
+func (db *DB) getVariableOptionalAttributes(moduleID int64) (map[int64][]OptionalAttribute, error) {
+	rows, err := db.conn.Query(`
+		SELECT variable_id, path, COALESCE(type, ''), COALESCE(default_value, '')
+		FROM variable_optional_attributes WHERE module_id = ?
+		ORDER BY variable_id, order_index
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attributes := make(map[int64][]OptionalAttribute)
+	for rows.Next() {
+		var variableID int64
+		var attr OptionalAttribute
+		if err := rows.Scan(&variableID, &attr.Path, &attr.Type, &attr.Default); err != nil {
+			return nil, err
+		}
+		attributes[variableID] = append(attributes[variableID], attr)
+	}
+	return attributes, rows.Err()
+}
+
+// getVariableValidations loads a module's validation blocks keyed by
+// variable ID.
+func (db *DB) getVariableValidations(moduleID int64) (map[int64][]VariableValidation, error) {
+	rows, err := db.conn.Query(`
+		SELECT variable_id, condition, COALESCE(error_message, '')
+		FROM variable_validations WHERE module_id = ?
+		ORDER BY variable_id, order_index
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	validations := make(map[int64][]VariableValidation)
+	for rows.Next() {
+		var variableID int64
+		var rule VariableValidation
+		if err := rows.Scan(&variableID, &rule.Condition, &rule.ErrorMessage); err != nil {
+			return nil, err
+		}
+		validations[variableID] = append(validations[variableID], rule)
+	}
+	return validations, rows.Err()
+}
+
+// VariableUsage is one variable declaration together with its module.
+type VariableUsage struct {
+	ModuleName string
+	Name       string
+	Type       string
+	Required   bool
+}
+
+// ownSourceFile is an SQL condition that holds when the source_file of the
+// row aliased alias lies directly in the directory of the module aliased m:
+// the repository root for root modules, modules/<name>/ for submodules. It
+// leaves out declarations indexed from examples and nested modules. Rows
+// without a source file, or with a bare file name, predate full paths being
+// indexed and count as own.
+func ownSourceFile(alias string) string {
+	return strings.ReplaceAll(`(COALESCE(@.source_file, '') = ''
+		OR instr(@.source_file, '/') = 0
+		OR (instr(m.name, '//') > 0
+			AND substr(@.source_file, 1, length(m.name) - instr(m.name, '//')) = substr(m.name, instr(m.name, '//') + 2) || '/'
+			AND instr(substr(@.source_file, length(m.name) - instr(m.name, '//') + 1), '/') = 0))`, "@", alias)
+}
+
+// ListAllVariables returns every variable declaration across the catalog,
+// ordered by variable name and module. Declarations from examples and nested
+// modules are left out.
+func (db *DB) ListAllVariables(requiredOnly bool) ([]VariableUsage, error) {
+	query := `
+		SELECT m.name, v.name, COALESCE(v.type, ''), v.required
+		FROM module_variables v
+		JOIN modules m ON m.id = v.module_id
+		WHERE ` + ownSourceFile("v")
+	if requiredOnly {
+		query += ` AND v.required = 1`
+	}
+	query += ` ORDER BY v.name, m.name`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usages []VariableUsage
+	for rows.Next() {
+		var u VariableUsage
+		if err := rows.Scan(&u.ModuleName, &u.Name, &u.Type, &u.Required); err != nil {
+			return nil, err
+		}
+		usages = append(usages, u)
+	}
+
+	return usages, rows.Err()
+}
+
+// ModuleVariableRef pairs a variable declaration with the module declaring it.
+type ModuleVariableRef struct {
+	ModuleName string
+	ModuleVariable
+}
+
+// ListVariableDefinitions returns the declaration of the named variable in
+// each module that declares it, ordered by module name. Declarations from
+// examples and nested modules are left out.
+func (db *DB) ListVariableDefinitions(name string) ([]ModuleVariableRef, error) {
+	rows, err := db.conn.Query(`
+		SELECT m.name, v.id, v.module_id, v.name, COALESCE(v.type, ''), COALESCE(v.description, ''), COALESCE(v.default_value, ''), v.required, v.sensitive, v.nullable
+		FROM module_variables v
+		JOIN modules m ON m.id = v.module_id
+		WHERE v.name = ? AND `+ownSourceFile("v")+`
+		ORDER BY m.name, v.id
+	`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []ModuleVariableRef
+	for rows.Next() {
+		var r ModuleVariableRef
+		if err := rows.Scan(&r.ModuleName, &r.ID, &r.ModuleID, &r.Name, &r.Type, &r.Description, &r.DefaultValue, &r.Required, &r.Sensitive, &r.Nullable); err != nil {
+			return nil, err
+		}
+		if n := len(refs); n > 0 && refs[n-1].ModuleID == r.ModuleID {
+			continue
+		}
+		refs = append(refs, r)
+	}
+
+	return refs, rows.Err()
+}
+
+// ModuleOutputRef pairs an output declaration with the module declaring it.
+type ModuleOutputRef struct {
+	ModuleName string
+	ModuleOutput
+}
+
+// SearchOutputs finds outputs whose name or description contains query,
+// case-insensitively. Exact name matches rank first, then name matches, then
+// description matches. Outputs declared in examples and nested modules are
+// left out.
+func (db *DB) SearchOutputs(query string, limit int) ([]ModuleOutputRef, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	name := strings.ReplaceAll(q, " ", "_")
+	namePattern := "%" + escapeLike(name) + "%"
+	rows, err := db.conn.Query(`
+		SELECT m.name, o.id, o.module_id, o.name, COALESCE(o.description, ''), COALESCE(o.value, ''), o.sensitive
+		FROM module_outputs o
+		JOIN modules m ON m.id = o.module_id
+		WHERE (LOWER(o.name) LIKE ? ESCAPE '\' OR LOWER(o.description) LIKE ? ESCAPE '\')
+			AND `+ownSourceFile("o")+`
+		ORDER BY CASE
+			WHEN LOWER(o.name) = ? THEN 0
+			WHEN LOWER(o.name) LIKE ? ESCAPE '\' THEN 1
+			ELSE 2
+		END, m.name, o.name
+		LIMIT ?
+	`, namePattern, "%"+escapeLike(q)+"%", name, namePattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []ModuleOutputRef
+	for rows.Next() {
+		var r ModuleOutputRef
+		if err := rows.Scan(&r.ModuleName, &r.ID, &r.ModuleID, &r.Name, &r.Description, &r.Value, &r.Sensitive); err != nil {
+			return nil, err
+		}
+		refs = append(refs, r)
+	}
+
+	return refs, rows.Err()
+}
+
+func (db *DB) InsertOutput(o *ModuleOutput) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO module_outputs (module_id, name, description, value, sensitive, source_file)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, o.ModuleID, o.Name, o.Description, o.Value, o.Sensitive, o.SourceFile)
 	return err
 }
 
 func (db *DB) GetModuleOutputs(moduleID int64) ([]ModuleOutput, error) {
 	rows, err := db.conn.Query(`
-		SELECT id, module_id, name, description, value, sensitive
+		SELECT id, module_id, name, description, value, sensitive, COALESCE(source_file, '')
 		FROM module_outputs WHERE module_id = ?
+		ORDER BY id
 	`, moduleID)
 	if err != nil {
 		return nil, err
@@ -458,7 +1189,7 @@ func (db *DB) GetModuleOutputs(moduleID int64) ([]ModuleOutput, error) {
 	var outputs []ModuleOutput
 	for rows.Next() {
 		var o ModuleOutput
-		if err := rows.Scan(&o.ID, &o.ModuleID, &o.Name, &o.Description, &o.Value, &o.Sensitive); err != nil {
+		if err := rows.Scan(&o.ID, &o.ModuleID, &o.Name, &o.Description, &o.Value, &o.Sensitive, &o.SourceFile); err != nil {
 			return nil, err
 		}
 		outputs = append(outputs, o)
@@ -468,93 +1199,633 @@ func (db *DB) GetModuleOutputs(moduleID int64) ([]ModuleOutput, error) {
 }
 
 func (db *DB) InsertResource(r *ModuleResource) error {
-	_, err := db.conn.Exec(`
+	result, err := db.conn.Exec(`
 		INSERT INTO module_resources (module_id, resource_type, resource_name, provider, source_file)
 		VALUES (?, ?, ?, ?, ?)
 	`, r.ModuleID, r.ResourceType, r.ResourceName, r.Provider, r.SourceFile)
+	if err != nil {
+		return err
+	}
+
+	resourceID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	r.ID = resourceID
+
+	for i, attr := range r.Attributes {
+		if _, err := db.conn.Exec(`
+			INSERT INTO resource_attributes (resource_id, module_id, name, kind, order_index)
+			VALUES (?, ?, ?, ?, ?)
+		`, resourceID, r.ModuleID, attr.Name, attr.Kind, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *DB) GetModuleResources(moduleID int64) ([]ModuleResource, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, module_id, resource_type, resource_name, provider, source_file
+		FROM module_resources WHERE module_id = ?
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var resources []ModuleResource
+	for rows.Next() {
+		var r ModuleResource
+		if err := rows.Scan(&r.ID, &r.ModuleID, &r.ResourceType, &r.ResourceName, &r.Provider, &r.SourceFile); err != nil {
+			return nil, err
+		}
+		resources = append(resources, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	attributes, err := db.getResourceAttributes(moduleID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range resources {
+		resources[i].Attributes = attributes[resources[i].ID]
+	}
+
+	return resources, nil
+}
+
+// getResourceAttributes loads a module's resource arguments and block types
+// keyed by resource ID.
+func (db *DB) getResourceAttributes(moduleID int64) (map[int64][]ResourceAttribute, error) {
+	rows, err := db.conn.Query(`
+		SELECT resource_id, name, kind
+		FROM resource_attributes WHERE module_id = ?
+		ORDER BY resource_id, order_index
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attributes := make(map[int64][]ResourceAttribute)
+	for rows.Next() {
+		var resourceID int64
+		var attr ResourceAttribute
+		if err := rows.Scan(&resourceID, &attr.Name, &attr.Kind); err != nil {
+			return nil, err
+		}
+		attributes[resourceID] = append(attributes[resourceID], attr)
+	}
+	return attributes, rows.Err()
+}
+
+type ResourceFilter struct {
+	ModuleID   int64
+	Provider   string
+	TypePrefix string
+	Limit      int
+	Offset     int
+}
+
+type ResourceInventoryItem struct {
+	ModuleName   string
+	ResourceType string
+	ResourceName string
+	Provider     string
+	SourceFile   string
+}
+
+// ListResources returns one page of resources matching filter together with
+// the total number of matches. A zero ModuleID searches the whole catalog.
+func (db *DB) ListResources(filter ResourceFilter) ([]ResourceInventoryItem, int, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.ModuleID > 0 {
+		conditions = append(conditions, "r.module_id = ?")
+		args = append(args, filter.ModuleID)
+	}
+	if filter.Provider != "" {
+		conditions = append(conditions, "r.provider = ?")
+		args = append(args, filter.Provider)
+	}
+	if filter.TypePrefix != "" {
+		conditions = append(conditions, `r.resource_type LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(filter.TypePrefix)+"%")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := db.conn.QueryRow(`
+		SELECT COUNT(*) FROM module_resources r `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := db.conn.Query(`
+		SELECT m.name, r.resource_type, r.resource_name, COALESCE(r.provider, ''), COALESCE(r.source_file, '')
+		FROM module_resources r
+		JOIN modules m ON m.id = r.module_id
+		`+where+`
+		ORDER BY m.name, r.resource_type, r.resource_name
+		LIMIT ? OFFSET ?
+	`, append(args, limit, max(0, filter.Offset))...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []ResourceInventoryItem
+	for rows.Next() {
+		var item ResourceInventoryItem
+		if err := rows.Scan(&item.ModuleName, &item.ResourceType, &item.ResourceName, &item.Provider, &item.SourceFile); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+
+	return items, total, rows.Err()
+}
+
+// ResourceTypeUser is a module declaring a given resource type and how many
+// instances of it the module declares.
+type ResourceTypeUser struct {
+	ModuleName string
+	Count      int
+	Files      []string
+}
+
+// ListModulesUsingResourceType returns the modules declaring resources of
+// exactly resourceType in their own files; resources of examples are left
+// out. The lookup is served by idx_module_resources_type.
+func (db *DB) ListModulesUsingResourceType(resourceType string) ([]ResourceTypeUser, error) {
+	rows, err := db.conn.Query(`
+		SELECT m.name, COUNT(*), GROUP_CONCAT(DISTINCT COALESCE(r.source_file, ''))
+		FROM module_resources r
+		JOIN modules m ON m.id = r.module_id
+		WHERE r.resource_type = ? AND `+ownSourceFile("r")+`
+		GROUP BY m.id
+		ORDER BY m.name
+	`, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []ResourceTypeUser
+	for rows.Next() {
+		var (
+			u     ResourceTypeUser
+			files string
+		)
+		if err := rows.Scan(&u.ModuleName, &u.Count, &files); err != nil {
+			return nil, err
+		}
+		for _, f := range strings.Split(files, ",") {
+			if f != "" {
+				u.Files = append(u.Files, f)
+			}
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (db *DB) InsertDataSource(d *ModuleDataSource) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO module_data_sources (module_id, data_type, data_name, provider, source_file)
+		VALUES (?, ?, ?, ?, ?)
+	`, d.ModuleID, d.DataType, d.DataName, d.Provider, d.SourceFile)
+	return err
+}
+
+func (db *DB) GetModuleDataSources(moduleID int64) ([]ModuleDataSource, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, module_id, data_type, data_name, provider, source_file
+		FROM module_data_sources WHERE module_id = ?
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dataSources []ModuleDataSource
+	for rows.Next() {
+		var d ModuleDataSource
+		if err := rows.Scan(&d.ID, &d.ModuleID, &d.DataType, &d.DataName, &d.Provider, &d.SourceFile); err != nil {
+			return nil, err
+		}
+		dataSources = append(dataSources, d)
+	}
+
+	return dataSources, rows.Err()
+}
+
+// TypeUsage aggregates how often a resource or data source type appears across modules.
+type TypeUsage struct {
+	Type        string
+	Provider    string
+	ModuleCount int
+	Occurrences int
+}
+
+func (db *DB) ListDataSourceTypes(provider string, minModules int) ([]TypeUsage, error) {
+	return db.listTypeUsage("module_data_sources", "data_type", provider, minModules)
+}
+
+// ListResourceTypes ranks resource types by how many modules declare them in
+// their own files; resources of examples and nested modules are not counted.
+func (db *DB) ListResourceTypes(provider string, minModules int) ([]TypeUsage, error) {
+	return db.listTypeUsage("module_resources", "resource_type", provider, minModules)
+}
+
+func (db *DB) listTypeUsage(table, typeColumn, provider string, minModules int) ([]TypeUsage, error) {
+	query := fmt.Sprintf(`
+		SELECT t.%[2]s, IFNULL(t.provider, ''), COUNT(DISTINCT t.module_id) AS module_count, COUNT(*) AS occurrences
+		FROM %[1]s t
+		JOIN modules m ON m.id = t.module_id
+		WHERE `, table, typeColumn) + ownSourceFile("t")
+	args := []any{}
+	if provider != "" {
+		query += ` AND t.provider = ?`
+		args = append(args, provider)
+	}
+	query += fmt.Sprintf(`
+		GROUP BY t.%[1]s, t.provider
+		HAVING module_count >= ?
+		ORDER BY module_count DESC, occurrences DESC, t.%[1]s ASC`, typeColumn)
+	args = append(args, minModules)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usages []TypeUsage
+	for rows.Next() {
+		var u TypeUsage
+		if err := rows.Scan(&u.Type, &u.Provider, &u.ModuleCount, &u.Occurrences); err != nil {
+			return nil, err
+		}
+		usages = append(usages, u)
+	}
+
+	return usages, rows.Err()
+}
+
+func (db *DB) InsertDependency(d *ModuleDependency) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO module_dependencies (module_id, name, source, source_kind, version, file_path)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, d.ModuleID, d.Name, d.Source, d.SourceKind, d.Version, d.FilePath)
+	return err
+}
+
+func (db *DB) GetModuleDependencies(moduleID int64) ([]ModuleDependency, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, module_id, name, source, source_kind, COALESCE(version, ''), file_path
+		FROM module_dependencies WHERE module_id = ?
+		ORDER BY name
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dependencies []ModuleDependency
+	for rows.Next() {
+		var d ModuleDependency
+		if err := rows.Scan(&d.ID, &d.ModuleID, &d.Name, &d.Source, &d.SourceKind, &d.Version, &d.FilePath); err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, d)
+	}
+
+	return dependencies, rows.Err()
+}
+
+func (db *DB) InsertStateMigration(m *ModuleStateMigration) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO module_state_migrations (module_id, block_type, from_address, to_address, import_id, file_path, line)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, m.ModuleID, m.BlockType, m.FromAddress, m.ToAddress, m.ImportID, m.FilePath, m.Line)
+	return err
+}
+
+func (db *DB) GetModuleStateMigrations(moduleID int64) ([]ModuleStateMigration, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, module_id, block_type, COALESCE(from_address, ''), COALESCE(to_address, ''), COALESCE(import_id, ''), file_path, line
+		FROM module_state_migrations WHERE module_id = ?
+		ORDER BY file_path, line
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var migrations []ModuleStateMigration
+	for rows.Next() {
+		var m ModuleStateMigration
+		if err := rows.Scan(&m.ID, &m.ModuleID, &m.BlockType, &m.FromAddress, &m.ToAddress, &m.ImportID, &m.FilePath, &m.Line); err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+
+	return migrations, rows.Err()
+}
+
+func (db *DB) InsertLocal(l *ModuleLocal) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO module_locals (module_id, name, expression, file_path, line)
+		VALUES (?, ?, ?, ?, ?)
+	`, l.ModuleID, l.Name, l.Expression, l.FilePath, l.Line)
+	return err
+}
+
+func (db *DB) GetModuleLocals(moduleID int64) ([]ModuleLocal, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, module_id, name, expression, file_path, line
+		FROM module_locals WHERE module_id = ?
+		ORDER BY file_path, line
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var locals []ModuleLocal
+	for rows.Next() {
+		var l ModuleLocal
+		if err := rows.Scan(&l.ID, &l.ModuleID, &l.Name, &l.Expression, &l.FilePath, &l.Line); err != nil {
+			return nil, err
+		}
+		locals = append(locals, l)
+	}
+
+	return locals, rows.Err()
+}
+
+func (db *DB) InsertExample(e *ModuleExample) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO module_examples (module_id, name, path, content)
+		VALUES (?, ?, ?, ?)
+	`, e.ModuleID, e.Name, e.Path, e.Content)
 	return err
 }
 
-func (db *DB) GetModuleResources(moduleID int64) ([]ModuleResource, error) {
-	rows, err := db.conn.Query(`
-		SELECT id, module_id, resource_type, resource_name, provider, source_file
-		FROM module_resources WHERE module_id = ?
-	`, moduleID)
+func (db *DB) GetModuleExamples(moduleID int64) ([]ModuleExample, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, module_id, name, path, content
+		FROM module_examples WHERE module_id = ?
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var examples []ModuleExample
+	for rows.Next() {
+		var e ModuleExample
+		if err := rows.Scan(&e.ID, &e.ModuleID, &e.Name, &e.Path, &e.Content); err != nil {
+			return nil, err
+		}
+		examples = append(examples, e)
+	}
+
+	return examples, rows.Err()
+}
+
+// ExampleSummary is one examples/<name> directory of a module.
+type ExampleSummary struct {
+	ModuleName string
+	Name       string
+	FileCount  int
+}
+
+// ListAllExamples returns every example directory across the catalog,
+// ordered by module and example name.
+func (db *DB) ListAllExamples() ([]ExampleSummary, error) {
+	rows, err := db.conn.Query(`
+		SELECT m.name, f.file_path
+		FROM module_files f
+		JOIN modules m ON m.id = f.module_id
+		WHERE f.file_path LIKE 'examples/%/%'
+		ORDER BY m.name, f.file_path
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var examples []ExampleSummary
+	for rows.Next() {
+		var moduleName, filePath string
+		if err := rows.Scan(&moduleName, &filePath); err != nil {
+			return nil, err
+		}
+		name := strings.Split(filePath, "/")[1]
+		if n := len(examples); n > 0 && examples[n-1].ModuleName == moduleName && examples[n-1].Name == name {
+			examples[n-1].FileCount++
+			continue
+		}
+		examples = append(examples, ExampleSummary{ModuleName: moduleName, Name: name, FileCount: 1})
+	}
+
+	return examples, rows.Err()
+}
+
+func (db *DB) InsertTerraformVersion(v *ModuleTerraformVersion) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO module_terraform_versions (module_id, required_version, source_file)
+		VALUES (?, ?, ?)
+	`, v.ModuleID, v.RequiredVersion, v.SourceFile)
+	return err
+}
+
+func (db *DB) GetModuleTerraformVersions(moduleID int64) ([]ModuleTerraformVersion, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, module_id, required_version, source_file
+		FROM module_terraform_versions WHERE module_id = ?
+		ORDER BY source_file
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []ModuleTerraformVersion
+	for rows.Next() {
+		var v ModuleTerraformVersion
+		if err := rows.Scan(&v.ID, &v.ModuleID, &v.RequiredVersion, &v.SourceFile); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// InsertProvider records a provider once per module; the first declaration
+// with a source wins over later ones without.
+func (db *DB) InsertProvider(p *ModuleProvider) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO module_providers (module_id, name, source, version, source_file, is_primary)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(module_id, name) DO UPDATE SET
+			source = CASE WHEN module_providers.source IS NULL OR module_providers.source = '' THEN excluded.source ELSE module_providers.source END,
+			version = CASE WHEN module_providers.version IS NULL OR module_providers.version = '' THEN excluded.version ELSE module_providers.version END,
+			source_file = CASE WHEN module_providers.source_file IS NULL OR module_providers.source_file = '' THEN excluded.source_file ELSE module_providers.source_file END
+	`, p.ModuleID, p.Name, p.Source, p.Version, p.SourceFile, p.IsPrimary)
+	return err
+}
+
+func (db *DB) GetModuleProviders(moduleID int64) ([]ModuleProvider, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, module_id, name, COALESCE(source, ''), COALESCE(version, ''), COALESCE(source_file, ''), is_primary
+		FROM module_providers WHERE module_id = ?
+		ORDER BY is_primary DESC, name
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var providers []ModuleProvider
+	for rows.Next() {
+		var p ModuleProvider
+		if err := rows.Scan(&p.ID, &p.ModuleID, &p.Name, &p.Source, &p.Version, &p.SourceFile, &p.IsPrimary); err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+
+	return providers, rows.Err()
+}
+
+func (db *DB) InsertParseError(e *ModuleParseError) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO module_parse_errors (module_id, file_path, message)
+		VALUES (?, ?, ?)
+	`, e.ModuleID, e.FilePath, e.Message)
+	return err
+}
+
+// ListParseErrors returns recorded parse failures, optionally limited to one
+// module (moduleID 0 lists all).
+func (db *DB) ListParseErrors(moduleID int64) ([]ModuleParseError, error) {
+	query := `
+		SELECT e.id, e.module_id, m.name, e.file_path, e.message
+		FROM module_parse_errors e
+		JOIN modules m ON m.id = e.module_id`
+	var args []any
+	if moduleID != 0 {
+		query += ` WHERE e.module_id = ?`
+		args = append(args, moduleID)
+	}
+	query += ` ORDER BY m.name, e.file_path`
+
+	rows, err := db.conn.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var resources []ModuleResource
+	var parseErrors []ModuleParseError
 	for rows.Next() {
-		var r ModuleResource
-		if err := rows.Scan(&r.ID, &r.ModuleID, &r.ResourceType, &r.ResourceName, &r.Provider, &r.SourceFile); err != nil {
+		var e ModuleParseError
+		if err := rows.Scan(&e.ID, &e.ModuleID, &e.ModuleName, &e.FilePath, &e.Message); err != nil {
 			return nil, err
 		}
-		resources = append(resources, r)
+		parseErrors = append(parseErrors, e)
 	}
 
-	return resources, rows.Err()
+	return parseErrors, rows.Err()
 }
 
-func (db *DB) InsertDataSource(d *ModuleDataSource) error {
-	_, err := db.conn.Exec(`
-		INSERT INTO module_data_sources (module_id, data_type, data_name, provider, source_file)
-		VALUES (?, ?, ?, ?, ?)
-	`, d.ModuleID, d.DataType, d.DataName, d.Provider, d.SourceFile)
-	return err
+// ModuleProviderRef pairs a provider requirement with the module declaring it.
+type ModuleProviderRef struct {
+	ModuleName string
+	ModuleProvider
 }
 
-func (db *DB) GetModuleDataSources(moduleID int64) ([]ModuleDataSource, error) {
+func (db *DB) ListModuleProviders() ([]ModuleProviderRef, error) {
 	rows, err := db.conn.Query(`
-		SELECT id, module_id, data_type, data_name, provider, source_file
-		FROM module_data_sources WHERE module_id = ?
-	`, moduleID)
+		SELECT m.name, p.id, p.module_id, p.name, COALESCE(p.source, ''), COALESCE(p.version, ''), COALESCE(p.source_file, ''), p.is_primary
+		FROM module_providers p
+		JOIN modules m ON m.id = p.module_id
+		ORDER BY m.name, p.is_primary DESC, p.name
+	`)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var dataSources []ModuleDataSource
+	var providers []ModuleProviderRef
 	for rows.Next() {
-		var d ModuleDataSource
-		if err := rows.Scan(&d.ID, &d.ModuleID, &d.DataType, &d.DataName, &d.Provider, &d.SourceFile); err != nil {
+		var p ModuleProviderRef
+		if err := rows.Scan(&p.ModuleName, &p.ID, &p.ModuleID, &p.Name, &p.Source, &p.Version, &p.SourceFile, &p.IsPrimary); err != nil {
 			return nil, err
 		}
-		dataSources = append(dataSources, d)
+		providers = append(providers, p)
 	}
 
-	return dataSources, rows.Err()
+	return providers, rows.Err()
 }
 
-func (db *DB) InsertExample(e *ModuleExample) error {
-	_, err := db.conn.Exec(`
-		INSERT INTO module_examples (module_id, name, path, content)
-		VALUES (?, ?, ?, ?)
-	`, e.ModuleID, e.Name, e.Path, e.Content)
-	return err
+// ModuleHealth holds the documentation signals checked by the catalog
+// health report for a root module.
+type ModuleHealth struct {
+	ModuleName    string
+	HasExamples   bool
+	HasReadme     bool
+	VariableCount int
+	OutputCount   int
 }
 
-func (db *DB) GetModuleExamples(moduleID int64) ([]ModuleExample, error) {
+func (db *DB) ListModuleHealth() ([]ModuleHealth, error) {
 	rows, err := db.conn.Query(`
-		SELECT id, module_id, name, path, content
-		FROM module_examples WHERE module_id = ?
-	`, moduleID)
+		SELECT m.name, m.has_examples, TRIM(COALESCE(m.readme_content, '')) <> '',
+		       (SELECT COUNT(*) FROM module_variables v WHERE v.module_id = m.id AND ` + ownSourceFile("v") + `),
+		       (SELECT COUNT(*) FROM module_outputs o WHERE o.module_id = m.id AND ` + ownSourceFile("o") + `)
+		FROM modules m
+		WHERE instr(m.name, '//') = 0
+		ORDER BY m.name
+	`)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var examples []ModuleExample
+	var health []ModuleHealth
 	for rows.Next() {
-		var e ModuleExample
-		if err := rows.Scan(&e.ID, &e.ModuleID, &e.Name, &e.Path, &e.Content); err != nil {
+		var h ModuleHealth
+		if err := rows.Scan(&h.ModuleName, &h.HasExamples, &h.HasReadme, &h.VariableCount, &h.OutputCount); err != nil {
 			return nil, err
 		}
-		examples = append(examples, e)
+		health = append(health, h)
 	}
 
-	return examples, rows.Err()
+	return health, rows.Err()
+}
+
+func (db *DB) SetPrimaryProvider(moduleID int64, name string) error {
+	_, err := db.conn.Exec(`
+		UPDATE module_providers SET is_primary = (name = ?) WHERE module_id = ?
+	`, name, moduleID)
+	return err
 }
 
 func (db *DB) UpsertModuleRelease(r *ModuleRelease) (int64, error) {
@@ -587,52 +1858,133 @@ func (db *DB) UpsertModuleRelease(r *ModuleRelease) (int64, error) {
 	return id, nil
 }
 
+func (db *DB) SetReleaseContributors(releaseID int64, contributors []string) error {
+	_, err := db.conn.Exec(`
+		UPDATE module_releases SET contributors = ? WHERE id = ?
+	`, strings.Join(contributors, ","), releaseID)
+	return err
+}
+
 func (db *DB) ReplaceModuleReleaseEntries(releaseID int64, entries []ModuleReleaseEntry) error {
-	tx, err := db.conn.Begin()
+	return db.WithTx(func(tx *DB) error {
+		if _, err := tx.conn.Exec(`DELETE FROM module_release_entries WHERE release_id = ?`, releaseID); err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if _, err := tx.conn.Exec(`
+				INSERT INTO module_release_entries (
+					release_id, section, entry_key, title, details, identifier, change_type, order_index
+				)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			`, releaseID, entry.Section, entry.EntryKey, entry.Title, entry.Details, entry.Identifier, entry.ChangeType, entry.OrderIndex); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetModuleReleases returns all releases of a module, newest first by
+// semantic version with the release date breaking ties.
+func (db *DB) GetModuleReleases(moduleID int64) ([]ModuleRelease, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, module_id, version, tag, previous_version, previous_tag,
+		       commit_sha, previous_commit_sha, release_date, comparison_url, contributors, created_at
+		FROM module_releases WHERE module_id = ?
+		ORDER BY release_date DESC, id ASC
+	`, moduleID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer tx.Rollback()
+	defer rows.Close()
 
-	if _, err := tx.Exec(`DELETE FROM module_release_entries WHERE release_id = ?`, releaseID); err != nil {
-		return err
+	var releases []ModuleRelease
+	for rows.Next() {
+		var r ModuleRelease
+		if err := rows.Scan(&r.ID, &r.ModuleID, &r.Version, &r.Tag, &r.PreviousVersion, &r.PreviousTag, &r.CommitSHA, &r.PreviousCommitSHA, &r.ReleaseDate, &r.ComparisonURL, &r.Contributors, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	for _, entry := range entries {
-		if _, err := tx.Exec(`
-			INSERT INTO module_release_entries (
-				release_id, section, entry_key, title, details, identifier, change_type, order_index
-			)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-		`, releaseID, entry.Section, entry.EntryKey, entry.Title, entry.Details, entry.Identifier, entry.ChangeType, entry.OrderIndex); err != nil {
-			return err
+	util.SortNewestFirst(releases, func(r ModuleRelease) string { return r.Version })
+	return releases, nil
+}
+
+// CountReleaseEntries returns the number of changelog entries per release of
+// a module, keyed by release ID.
+func (db *DB) CountReleaseEntries(moduleID int64) (map[int64]int, error) {
+	rows, err := db.conn.Query(`
+		SELECT e.release_id, COUNT(*)
+		FROM module_release_entries e
+		JOIN module_releases r ON r.id = e.release_id
+		WHERE r.module_id = ?
+		GROUP BY e.release_id
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var releaseID int64
+		var count int
+		if err := rows.Scan(&releaseID, &count); err != nil {
+			return nil, err
 		}
+		counts[releaseID] = count
 	}
+	return counts, rows.Err()
+}
 
-	return tx.Commit()
+type ModuleReleaseActivity struct {
+	ModuleID          int64
+	ModuleName        string
+	ReleaseCount      int
+	LatestReleaseDate string
+	LatestVersion     string // filled in by callers
 }
 
-func (db *DB) GetLatestModuleRelease(moduleID int64) (*ModuleRelease, error) {
-	var r ModuleRelease
-	err := db.conn.QueryRow(`
-		SELECT id, module_id, version, tag, previous_version, previous_tag,
-		       commit_sha, previous_commit_sha, release_date, comparison_url, created_at
-		FROM module_releases WHERE module_id = ?
-		ORDER BY release_date DESC, created_at DESC LIMIT 1
-	`, moduleID).Scan(&r.ID, &r.ModuleID, &r.Version, &r.Tag, &r.PreviousVersion, &r.PreviousTag, &r.CommitSHA, &r.PreviousCommitSHA, &r.ReleaseDate, &r.ComparisonURL, &r.CreatedAt)
+// ListModuleReleaseActivity ranks modules by their number of indexed
+// releases, most active first.
+func (db *DB) ListModuleReleaseActivity(limit int) ([]ModuleReleaseActivity, error) {
+	rows, err := db.conn.Query(`
+		SELECT m.id, m.name, COUNT(r.id), COALESCE(MAX(r.release_date), '')
+		FROM modules m
+		JOIN module_releases r ON r.module_id = m.id
+		GROUP BY m.id
+		ORDER BY COUNT(r.id) DESC, MAX(r.release_date) DESC, m.name
+		LIMIT ?
+	`, limit)
 	if err != nil {
 		return nil, err
 	}
-	return &r, nil
+	defer rows.Close()
+
+	var activity []ModuleReleaseActivity
+	for rows.Next() {
+		var a ModuleReleaseActivity
+		if err := rows.Scan(&a.ModuleID, &a.ModuleName, &a.ReleaseCount, &a.LatestReleaseDate); err != nil {
+			return nil, err
+		}
+		activity = append(activity, a)
+	}
+
+	return activity, rows.Err()
 }
 
 func (db *DB) GetModuleReleaseByVersion(moduleID int64, version string) (*ModuleRelease, error) {
 	var r ModuleRelease
 	err := db.conn.QueryRow(`
 		SELECT id, module_id, version, tag, previous_version, previous_tag,
-		       commit_sha, previous_commit_sha, release_date, comparison_url, created_at
+		       commit_sha, previous_commit_sha, release_date, comparison_url, contributors, created_at
 		FROM module_releases WHERE module_id = ? AND version = ?
-	`, moduleID, version).Scan(&r.ID, &r.ModuleID, &r.Version, &r.Tag, &r.PreviousVersion, &r.PreviousTag, &r.CommitSHA, &r.PreviousCommitSHA, &r.ReleaseDate, &r.ComparisonURL, &r.CreatedAt)
+	`, moduleID, version).Scan(&r.ID, &r.ModuleID, &r.Version, &r.Tag, &r.PreviousVersion, &r.PreviousTag, &r.CommitSHA, &r.PreviousCommitSHA, &r.ReleaseDate, &r.ComparisonURL, &r.Contributors, &r.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -643,9 +1995,9 @@ func (db *DB) GetModuleReleaseByTag(moduleID int64, tag string) (*ModuleRelease,
 	var r ModuleRelease
 	err := db.conn.QueryRow(`
 		SELECT id, module_id, version, tag, previous_version, previous_tag,
-		       commit_sha, previous_commit_sha, release_date, comparison_url, created_at
+		       commit_sha, previous_commit_sha, release_date, comparison_url, contributors, created_at
 		FROM module_releases WHERE module_id = ? AND LOWER(tag) = LOWER(?)
-	`, moduleID, tag).Scan(&r.ID, &r.ModuleID, &r.Version, &r.Tag, &r.PreviousVersion, &r.PreviousTag, &r.CommitSHA, &r.PreviousCommitSHA, &r.ReleaseDate, &r.ComparisonURL, &r.CreatedAt)
+	`, moduleID, tag).Scan(&r.ID, &r.ModuleID, &r.Version, &r.Tag, &r.PreviousVersion, &r.PreviousTag, &r.CommitSHA, &r.PreviousCommitSHA, &r.ReleaseDate, &r.ComparisonURL, &r.Contributors, &r.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -676,18 +2028,6 @@ func (db *DB) GetModuleReleaseEntries(releaseID int64) ([]ModuleReleaseEntry, er
 	return entries, rows.Err()
 }
 
-func (db *DB) GetLatestModuleReleaseWithEntries(moduleID int64) (*ModuleRelease, []ModuleReleaseEntry, error) {
-	release, err := db.GetLatestModuleRelease(moduleID)
-	if err != nil {
-		return nil, nil, err
-	}
-	entries, err := db.GetModuleReleaseEntries(release.ID)
-	if err != nil {
-		return nil, nil, err
-	}
-	return release, entries, nil
-}
-
 func (db *DB) GetModuleReleaseWithEntriesByVersion(moduleID int64, version string) (*ModuleRelease, []ModuleReleaseEntry, error) {
 	release, err := db.GetModuleReleaseByVersion(moduleID, version)
 	if err != nil {
@@ -713,91 +2053,152 @@ func (db *DB) GetModuleReleaseWithEntriesByTag(moduleID int64, tag string) (*Mod
 }
 
 func (db *DB) ClearModuleData(moduleID int64) error {
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
 	tables := []string{
 		"module_files",
+		"variable_validations",
+		"variable_optional_attributes",
 		"module_variables",
 		"module_outputs",
+		"resource_attributes",
 		"module_resources",
 		"module_data_sources",
+		"module_dependencies",
+		"module_state_migrations",
+		"module_locals",
 		"module_examples",
+		"module_terraform_versions",
+		"module_providers",
+		"module_parse_errors",
 		"hcl_blocks",
 		"hcl_relationships",
 	}
 
-	for _, table := range tables {
-		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE module_id = ?", table), moduleID); err != nil {
+	return db.WithTx(func(tx *DB) error {
+		for _, table := range tables {
+			if _, err := tx.conn.Exec(fmt.Sprintf("DELETE FROM %s WHERE module_id = ?", table), moduleID); err != nil {
+				return err
+			}
+		}
+		return tx.rebuildFTSTables()
+	})
+}
+
+func (db *DB) DeleteModuleByID(moduleID int64) error {
+	return db.WithTx(func(tx *DB) error {
+		result, err := tx.conn.Exec(`DELETE FROM modules WHERE id = ?`, moduleID)
+		if err != nil {
 			return err
 		}
-	}
 
-	if err := db.rebuildFTSTables(tx); err != nil {
-		return err
-	}
+		rows, err := result.RowsAffected()
+		if err == nil && rows > 0 {
+			return tx.rebuildFTSTables()
+		}
+		return nil
+	})
+}
+
+func (db *DB) DeleteChildModules(parentName string) error {
+	pattern := parentName + "//%"
+	return db.WithTx(func(tx *DB) error {
+		result, err := tx.conn.Exec(`DELETE FROM modules WHERE name LIKE ? ESCAPE '\'`, pattern)
+		if err != nil {
+			return err
+		}
 
-	return tx.Commit()
+		rows, err := result.RowsAffected()
+		if err == nil && rows > 0 {
+			return tx.rebuildFTSTables()
+		}
+		return nil
+	})
 }
 
-func (db *DB) DeleteModuleByID(moduleID int64) error {
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return err
+func (db *DB) rebuildFTSTables() error {
+	if !db.fts {
+		return nil
 	}
-	defer tx.Rollback()
 
-	result, err := tx.Exec(`DELETE FROM modules WHERE id = ?`, moduleID)
-	if err != nil {
-		return err
+	if _, err := db.conn.Exec(`INSERT INTO modules_fts(modules_fts) VALUES('rebuild')`); err != nil {
+		return fmt.Errorf("failed to rebuild modules_fts: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
-	if err == nil && rows > 0 {
-		if err := db.rebuildFTSTables(tx); err != nil {
-			return err
-		}
+	if _, err := db.conn.Exec(`INSERT INTO files_fts(files_fts) VALUES('rebuild')`); err != nil {
+		return fmt.Errorf("failed to rebuild files_fts: %w", err)
 	}
 
-	return tx.Commit()
+	return nil
 }
 
-func (db *DB) DeleteChildModules(parentName string) error {
-	pattern := parentName + "//%"
-	tx, err := db.conn.Begin()
+// SyncRun records the outcome of one full or incremental sync.
+type SyncRun struct {
+	ID             int64
+	SyncType       string
+	StartedAt      time.Time
+	CompletedAt    time.Time
+	TotalRepos     int
+	ProcessedRepos int
+	SkippedRepos   int
+	UpdatedRepos   int
+	ErrorCount     int
+}
+
+func (db *DB) InsertSyncRun(r *SyncRun) error {
+	result, err := db.conn.Exec(`
+		INSERT INTO sync_runs (sync_type, started_at, completed_at, total_repos, processed_repos, skipped_repos, updated_repos, error_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, r.SyncType, r.StartedAt.UTC(), r.CompletedAt.UTC(), r.TotalRepos, r.ProcessedRepos, r.SkippedRepos, r.UpdatedRepos, r.ErrorCount)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
+	r.ID, err = result.LastInsertId()
+	return err
+}
 
-	result, err := tx.Exec(`DELETE FROM modules WHERE name LIKE ? ESCAPE '\'`, pattern)
+// ListSyncRuns returns the most recent sync runs, newest first.
+func (db *DB) ListSyncRuns(limit int) ([]SyncRun, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, sync_type, started_at, completed_at, total_repos, processed_repos, skipped_repos, updated_repos, error_count
+		FROM sync_runs
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?
+	`, limit)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer rows.Close()
 
-	rows, err := result.RowsAffected()
-	if err == nil && rows > 0 {
-		if err := db.rebuildFTSTables(tx); err != nil {
-			return err
+	var runs []SyncRun
+	for rows.Next() {
+		var r SyncRun
+		if err := rows.Scan(&r.ID, &r.SyncType, &r.StartedAt, &r.CompletedAt, &r.TotalRepos, &r.ProcessedRepos, &r.SkippedRepos, &r.UpdatedRepos, &r.ErrorCount); err != nil {
+			return nil, err
 		}
+		runs = append(runs, r)
 	}
+	return runs, rows.Err()
+}
 
-	return tx.Commit()
+// IndexStats summarizes what the index currently holds.
+type IndexStats struct {
+	ModuleCount  int
+	FileCount    int
+	LastSyncedAt time.Time // zero when no module has been synced
 }
 
-func (db *DB) rebuildFTSTables(exec execer) error {
-	if _, err := exec.Exec(`INSERT INTO modules_fts(modules_fts) VALUES('rebuild')`); err != nil {
-		return fmt.Errorf("failed to rebuild modules_fts: %w", err)
+func (db *DB) GetIndexStats() (*IndexStats, error) {
+	stats := &IndexStats{}
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM modules`).Scan(&stats.ModuleCount); err != nil {
+		return nil, err
 	}
-
-	if _, err := exec.Exec(`INSERT INTO files_fts(files_fts) VALUES('rebuild')`); err != nil {
-		return fmt.Errorf("failed to rebuild files_fts: %w", err)
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM module_files`).Scan(&stats.FileCount); err != nil {
+		return nil, err
 	}
-
-	return nil
+	err := db.conn.QueryRow(`SELECT synced_at FROM modules ORDER BY synced_at DESC LIMIT 1`).Scan(&stats.LastSyncedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return stats, nil
 }
 
 func (db *DB) ReclaimFreePages() error {
@@ -824,6 +2225,11 @@ func (db *DB) ReclaimFreePages() error {
 	return nil
 }
 
+func (db *DB) SetModuleCommitSHA(moduleID int64, sha string) error {
+	_, err := db.conn.Exec(`UPDATE modules SET commit_sha = ? WHERE id = ?`, sha, moduleID)
+	return err
+}
+
 func (db *DB) SetModuleHasExamples(moduleID int64, hasExamples bool) error {
 	_, err := db.conn.Exec(`
         UPDATE modules
@@ -833,11 +2239,18 @@ func (db *DB) SetModuleHasExamples(moduleID int64, hasExamples bool) error {
 	return err
 }
 
-func (db *DB) InsertHCLBlock(moduleID int64, filePath, blockType, typeLabel string, startByte, endByte int, attrPaths string) (int64, error) {
+// SetModuleNoTerraform records whether the module's last sync found no
+// Terraform files, e.g. a repository that only holds documentation.
+func (db *DB) SetModuleNoTerraform(moduleID int64, noTerraform bool) error {
+	_, err := db.conn.Exec(`UPDATE modules SET no_terraform = ? WHERE id = ?`, noTerraform, moduleID)
+	return err
+}
+
+func (db *DB) InsertHCLBlock(moduleID int64, filePath, blockType, typeLabel, nameLabel string, startByte, endByte int, attrPaths string) (int64, error) {
 	res, err := db.conn.Exec(`
-        INSERT INTO hcl_blocks (module_id, file_path, block_type, type_label, start_byte, end_byte, attr_paths)
-        VALUES (?, ?, ?, ?, ?, ?, ?)
-    `, moduleID, filePath, blockType, nullIfEmpty(typeLabel), startByte, endByte, nullIfEmpty(attrPaths))
+        INSERT INTO hcl_blocks (module_id, file_path, block_type, type_label, name_label, start_byte, end_byte, attr_paths)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+    `, moduleID, filePath, blockType, nullIfEmpty(typeLabel), nullIfEmpty(nameLabel), startByte, endByte, nullIfEmpty(attrPaths))
 	if err != nil {
 		return 0, err
 	}
@@ -889,6 +2302,41 @@ func (db *DB) QueryHCLBlocks(blockType, typeLabel string, prefix bool) ([]HCLBlo
 	return out, rows.Err()
 }
 
+// FindModuleBlocks returns the blocks of one module with the given type,
+// optionally narrowed by type and name label, in file and source order.
+func (db *DB) FindModuleBlocks(moduleID int64, blockType, typeLabel, nameLabel string) ([]HCLBlock, error) {
+	query := `
+        SELECT id, module_id, file_path, block_type, type_label, name_label, start_byte, end_byte, attr_paths
+        FROM hcl_blocks
+        WHERE module_id = ? AND block_type = ?`
+	args := []any{moduleID, blockType}
+	if typeLabel != "" {
+		query += ` AND type_label = ?`
+		args = append(args, typeLabel)
+	}
+	if nameLabel != "" {
+		query += ` AND name_label = ?`
+		args = append(args, nameLabel)
+	}
+	query += ` ORDER BY file_path, start_byte`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HCLBlock
+	for rows.Next() {
+		var b HCLBlock
+		if err := rows.Scan(&b.ID, &b.ModuleID, &b.FilePath, &b.BlockType, &b.TypeLabel, &b.NameLabel, &b.StartByte, &b.EndByte, &b.AttrPaths); err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
 func (db *DB) InsertRelationship(r *HCLRelationship) error {
 	_, err := db.conn.Exec(`
         INSERT INTO hcl_relationships (
@@ -1026,6 +2474,55 @@ func (db *DB) QueryRelationshipsAny(term string, limit int) ([]HCLRelationship,
 	return results, rows.Err()
 }
 
+func (db *DB) GetModuleRelationshipsByBlockType(moduleID int64, blockType string) ([]HCLRelationship, error) {
+	rows, err := db.conn.Query(`
+        SELECT
+            id,
+            module_id,
+            file_path,
+            block_type,
+            block_labels,
+            attribute_path,
+            reference_type,
+            reference_name,
+            start_byte,
+            end_byte
+        FROM hcl_relationships
+        WHERE module_id = ? AND block_type = ?
+        ORDER BY file_path, start_byte
+    `, moduleID, blockType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []HCLRelationship
+	for rows.Next() {
+		var rel HCLRelationship
+		var blockLabels sql.NullString
+		if err := rows.Scan(
+			&rel.ID,
+			&rel.ModuleID,
+			&rel.FilePath,
+			&rel.BlockType,
+			&blockLabels,
+			&rel.AttributePath,
+			&rel.ReferenceType,
+			&rel.ReferenceName,
+			&rel.StartByte,
+			&rel.EndByte,
+		); err != nil {
+			return nil, err
+		}
+		if blockLabels.Valid {
+			rel.BlockLabels = blockLabels.String
+		}
+		results = append(results, rel)
+	}
+
+	return results, rows.Err()
+}
+
 func nullIfEmpty(s string) any {
 	if s == "" {
 		return nil
@@ -1139,9 +2636,13 @@ func (db *DB) CountResourceBlocks(moduleID int64) (int, error) {
 	return total, err
 }
 
+// GetModuleResourceTypes returns the types of the resources declared in the
+// module's own files; resources of examples and nested modules are left out.
 func (db *DB) GetModuleResourceTypes(moduleID int64) ([]string, error) {
 	rows, err := db.conn.Query(`
-        SELECT resource_type FROM module_resources WHERE module_id = ?
+        SELECT r.resource_type FROM module_resources r
+        JOIN modules m ON m.id = r.module_id
+        WHERE r.module_id = ? AND `+ownSourceFile("r")+`
     `, moduleID)
 	if err != nil {
 		return nil, err
@@ -1196,6 +2697,29 @@ func (db *DB) GetModuleTags(moduleID int64) ([]ModuleTag, error) {
 	return tags, rows.Err()
 }
 
+// GetModuleTopics returns the GitHub topics stored for a module.
+func (db *DB) GetModuleTopics(moduleID int64) ([]string, error) {
+	rows, err := db.conn.Query(`
+        SELECT tag FROM module_tags
+        WHERE module_id = ? AND source = ?
+        ORDER BY tag ASC
+    `, moduleID, TagSourceTopic)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var topics []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		topics = append(topics, t)
+	}
+	return topics, rows.Err()
+}
+
 func (db *DB) ClearModuleAliases(moduleID int64) error {
 	_, err := db.conn.Exec(`DELETE FROM module_aliases WHERE module_id = ?`, moduleID)
 	return err
@@ -1213,9 +2737,8 @@ func (db *DB) InsertModuleAlias(moduleID int64, alias string, weight int, source
 }
 
 func (db *DB) ResolveModuleByAlias(alias string) (*Module, error) {
-	var m Module
-	err := db.conn.QueryRow(`
-        SELECT m.id, m.name, m.full_name, m.description, m.repo_url, m.last_updated, m.synced_at, m.readme_content, m.has_examples
+	m, err := scanModule(db.conn.QueryRow(`
+        SELECT `+moduleColumns+`
         FROM module_aliases a
         JOIN modules m ON m.id = a.module_id
         WHERE a.alias = ?
@@ -1223,7 +2746,7 @@ func (db *DB) ResolveModuleByAlias(alias string) (*Module, error) {
                  (CASE WHEN instr(m.name, '//') > 0 THEN 1 ELSE 0 END) ASC,
                  m.name ASC
         LIMIT 1
-    `, strings.ToLower(alias)).Scan(&m.ID, &m.Name, &m.FullName, &m.Description, &m.RepoURL, &m.LastUpdated, &m.SyncedAt, &m.ReadmeContent, &m.HasExamples)
+    `, strings.ToLower(alias)))
 	if err != nil {
 		return nil, err
 	}
@@ -1232,9 +2755,8 @@ func (db *DB) ResolveModuleByAlias(alias string) (*Module, error) {
 
 func (db *DB) ResolveModuleByAliasPrefix(prefix string) (*Module, error) {
 	like := strings.ToLower(prefix) + "%"
-	var m Module
-	err := db.conn.QueryRow(`
-        SELECT m.id, m.name, m.full_name, m.description, m.repo_url, m.last_updated, m.synced_at, m.readme_content, m.has_examples
+	m, err := scanModule(db.conn.QueryRow(`
+        SELECT `+moduleColumns+`
         FROM module_aliases a
         JOIN modules m ON m.id = a.module_id
         WHERE a.alias LIKE ?
@@ -1243,7 +2765,7 @@ func (db *DB) ResolveModuleByAliasPrefix(prefix string) (*Module, error) {
                  (CASE WHEN instr(m.name, '//') > 0 THEN 1 ELSE 0 END) ASC,
                  m.name ASC
         LIMIT 1
-    `, like).Scan(&m.ID, &m.Name, &m.FullName, &m.Description, &m.RepoURL, &m.LastUpdated, &m.SyncedAt, &m.ReadmeContent, &m.HasExamples)
+    `, like))
 	if err != nil {
 		return nil, err
 	}
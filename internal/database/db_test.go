@@ -0,0 +1,85 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestListDataSourceTypesOwnFiles(t *testing.T) {
+	db := newTestDB(t)
+
+	dataSources := map[string][]ModuleDataSource{
+		"terraform-azure-demo": {
+			{DataType: "azurerm_client_config", DataName: "current", Provider: "azurerm", SourceFile: "main.tf"},
+			{DataType: "azurerm_subscription", DataName: "current", Provider: "azurerm", SourceFile: "examples/complete/main.tf"},
+		},
+		"terraform-azure-demo//modules/sub": {
+			{DataType: "azurerm_client_config", DataName: "current", Provider: "azurerm", SourceFile: "modules/sub/main.tf"},
+			{DataType: "azurerm_resource_group", DataName: "rg", Provider: "azurerm", SourceFile: "modules/sub/modules/inner/main.tf"},
+		},
+		"terraform-azure-vnet": {
+			{DataType: "azurerm_subscription", DataName: "current", Provider: "azurerm", SourceFile: "examples/default/main.tf"},
+		},
+	}
+	for name, sources := range dataSources {
+		moduleID, err := db.InsertModule(&Module{Name: name})
+		if err != nil {
+			t.Fatalf("InsertModule(%s): %v", name, err)
+		}
+		for _, d := range sources {
+			d.ModuleID = moduleID
+			if err := db.InsertDataSource(&d); err != nil {
+				t.Fatalf("InsertDataSource(%s): %v", d.DataType, err)
+			}
+		}
+	}
+
+	usages, err := db.ListDataSourceTypes("", 1)
+	if err != nil {
+		t.Fatalf("ListDataSourceTypes: %v", err)
+	}
+	if len(usages) != 1 {
+		t.Fatalf("usages = %+v, want only azurerm_client_config", usages)
+	}
+	if got := usages[0]; got.Type != "azurerm_client_config" || got.ModuleCount != 2 || got.Occurrences != 2 {
+		t.Errorf("usage = %+v, want azurerm_client_config in 2 modules", got)
+	}
+}
+
+func TestGetModuleResourceTypesOwnFiles(t *testing.T) {
+	db := newTestDB(t)
+
+	moduleID, err := db.InsertModule(&Module{Name: "terraform-azure-sa"})
+	if err != nil {
+		t.Fatalf("InsertModule: %v", err)
+	}
+	resources := []ModuleResource{
+		{ResourceType: "azurerm_storage_account", ResourceName: "sa", SourceFile: "main.tf"},
+		{ResourceType: "azurerm_resource_group", ResourceName: "rg", SourceFile: "examples/default/main.tf"},
+		{ResourceType: "random_string", ResourceName: "suffix", SourceFile: "examples/default/main.tf"},
+	}
+	for _, r := range resources {
+		r.ModuleID = moduleID
+		if err := db.InsertResource(&r); err != nil {
+			t.Fatalf("InsertResource(%s): %v", r.ResourceType, err)
+		}
+	}
+
+	types, err := db.GetModuleResourceTypes(moduleID)
+	if err != nil {
+		t.Fatalf("GetModuleResourceTypes: %v", err)
+	}
+	if len(types) != 1 || types[0] != "azurerm_storage_account" {
+		t.Errorf("types = %v, want [azurerm_storage_account]", types)
+	}
+}
@@ -10,7 +10,11 @@ CREATE TABLE IF NOT EXISTS modules (
     last_updated TEXT,
     synced_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     readme_content TEXT,
-    has_examples BOOLEAN DEFAULT 0
+    has_examples BOOLEAN DEFAULT 0,
+    org TEXT NOT NULL DEFAULT '',
+    commit_sha TEXT,
+    archived BOOLEAN NOT NULL DEFAULT 0,
+    no_terraform BOOLEAN NOT NULL DEFAULT 0
 );
 
 CREATE TABLE IF NOT EXISTS module_files (
@@ -34,6 +38,9 @@ CREATE TABLE IF NOT EXISTS module_variables (
     default_value TEXT,
     required BOOLEAN DEFAULT 1,
     sensitive BOOLEAN DEFAULT 0,
+    nullable BOOLEAN NOT NULL DEFAULT 1,
+    source_file TEXT,
+    block_source TEXT,
     FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
 );
 
@@ -44,6 +51,7 @@ CREATE TABLE IF NOT EXISTS module_outputs (
     description TEXT,
     value TEXT,
     sensitive BOOLEAN DEFAULT 0,
+    source_file TEXT,
     FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
 );
 
@@ -76,25 +84,132 @@ CREATE TABLE IF NOT EXISTS module_examples (
     FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
 );
 
+CREATE TABLE IF NOT EXISTS module_terraform_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    module_id INTEGER NOT NULL,
+    required_version TEXT NOT NULL,
+    source_file TEXT,
+    FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS module_providers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    module_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    source TEXT,
+    version TEXT,
+    source_file TEXT,
+    is_primary BOOLEAN DEFAULT 0,
+    FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE,
+    UNIQUE(module_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS module_parse_errors (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    module_id INTEGER NOT NULL,
+    file_path TEXT NOT NULL,
+    message TEXT NOT NULL,
+    FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS variable_optional_attributes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    variable_id INTEGER NOT NULL,
+    module_id INTEGER NOT NULL,
+    path TEXT NOT NULL,
+    type TEXT,
+    default_value TEXT,
+    order_index INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (variable_id) REFERENCES module_variables(id) ON DELETE CASCADE,
+    FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS variable_validations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    variable_id INTEGER NOT NULL,
+    module_id INTEGER NOT NULL,
+    condition TEXT NOT NULL,
+    error_message TEXT,
+    order_index INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (variable_id) REFERENCES module_variables(id) ON DELETE CASCADE,
+    FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS resource_attributes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    resource_id INTEGER NOT NULL,
+    module_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL, -- attribute|block|dynamic
+    order_index INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (resource_id) REFERENCES module_resources(id) ON DELETE CASCADE,
+    FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS module_dependencies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    module_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    source TEXT NOT NULL,
+    source_kind TEXT NOT NULL, -- local|registry|git|other
+    version TEXT,
+    file_path TEXT NOT NULL,
+    FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS module_state_migrations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    module_id INTEGER NOT NULL,
+    block_type TEXT NOT NULL, -- moved|removed|import
+    from_address TEXT,
+    to_address TEXT,
+    import_id TEXT,
+    file_path TEXT NOT NULL,
+    line INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS module_locals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    module_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    expression TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    line INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE
+);
+
 -- Indexes for performance
 CREATE INDEX IF NOT EXISTS idx_modules_name ON modules(name);
 CREATE INDEX IF NOT EXISTS idx_modules_full_name ON modules(full_name);
 CREATE INDEX IF NOT EXISTS idx_module_files_module_id ON module_files(module_id);
 CREATE INDEX IF NOT EXISTS idx_module_files_type ON module_files(file_type);
 CREATE INDEX IF NOT EXISTS idx_module_variables_module_id ON module_variables(module_id);
+CREATE INDEX IF NOT EXISTS idx_module_variables_name ON module_variables(name);
 CREATE INDEX IF NOT EXISTS idx_module_outputs_module_id ON module_outputs(module_id);
 CREATE INDEX IF NOT EXISTS idx_module_resources_module_id ON module_resources(module_id);
 CREATE INDEX IF NOT EXISTS idx_module_resources_type ON module_resources(resource_type);
+CREATE INDEX IF NOT EXISTS idx_module_resources_provider ON module_resources(provider);
 CREATE INDEX IF NOT EXISTS idx_module_data_sources_module_id ON module_data_sources(module_id);
 CREATE INDEX IF NOT EXISTS idx_module_examples_module_id ON module_examples(module_id);
+CREATE INDEX IF NOT EXISTS idx_module_terraform_versions_module_id ON module_terraform_versions(module_id);
+CREATE INDEX IF NOT EXISTS idx_module_providers_module_id ON module_providers(module_id);
+CREATE INDEX IF NOT EXISTS idx_module_parse_errors_module_id ON module_parse_errors(module_id);
+CREATE INDEX IF NOT EXISTS idx_variable_validations_module_id ON variable_validations(module_id);
+CREATE INDEX IF NOT EXISTS idx_variable_optional_attributes_module_id ON variable_optional_attributes(module_id);
+CREATE INDEX IF NOT EXISTS idx_resource_attributes_module_id ON resource_attributes(module_id);
+CREATE INDEX IF NOT EXISTS idx_module_dependencies_module_id ON module_dependencies(module_id);
+CREATE INDEX IF NOT EXISTS idx_module_state_migrations_module_id ON module_state_migrations(module_id);
+CREATE INDEX IF NOT EXISTS idx_module_locals_module_id ON module_locals(module_id);
 
 -- HCL block index for fast AST-based queries
 CREATE TABLE IF NOT EXISTS hcl_blocks (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     module_id INTEGER NOT NULL,
     file_path TEXT NOT NULL,
-    block_type TEXT NOT NULL, -- resource|dynamic|lifecycle
+    block_type TEXT NOT NULL, -- resource|data|variable|output|locals|dynamic|lifecycle
     type_label TEXT,          -- e.g., azurerm_storage_account for resource, or label for dynamic
+    name_label TEXT,          -- e.g., sa for resource "azurerm_storage_account" "sa", or the variable/output name
     start_byte INTEGER NOT NULL,
     end_byte INTEGER NOT NULL,
     attr_paths TEXT,          -- newline-separated flattened attribute paths within this block (e.g., "for_each\nlifecycle.ignore_changes")
@@ -125,57 +240,6 @@ CREATE INDEX IF NOT EXISTS idx_hcl_relationships_attr ON hcl_relationships(modul
 CREATE INDEX IF NOT EXISTS idx_hcl_relationships_ref_only ON hcl_relationships(reference_name);
 CREATE INDEX IF NOT EXISTS idx_hcl_relationships_attr_only ON hcl_relationships(attribute_path);
 
-CREATE VIRTUAL TABLE IF NOT EXISTS modules_fts USING fts5(
-    name,
-    description,
-    readme_content,
-    content='modules',
-    content_rowid='id'
-);
-
-CREATE VIRTUAL TABLE IF NOT EXISTS files_fts USING fts5(
-    file_name,
-    file_path,
-    content,
-    content='module_files',
-    content_rowid='id'
-);
-
-CREATE TRIGGER IF NOT EXISTS modules_fts_insert AFTER INSERT ON modules BEGIN
-    INSERT INTO modules_fts(rowid, name, description, readme_content)
-    VALUES (new.id, new.name, new.description, new.readme_content);
-END;
-
-CREATE TRIGGER IF NOT EXISTS modules_fts_update AFTER UPDATE ON modules BEGIN
-    UPDATE modules_fts
-    SET name = new.name,
-        description = new.description,
-        readme_content = new.readme_content
-    WHERE rowid = new.id;
-END;
-
-CREATE TRIGGER IF NOT EXISTS modules_fts_delete AFTER DELETE ON modules BEGIN
-    DELETE FROM modules_fts WHERE rowid = old.id;
-END;
-
--- Triggers to keep files FTS in sync
-CREATE TRIGGER IF NOT EXISTS files_fts_insert AFTER INSERT ON module_files BEGIN
-    INSERT INTO files_fts(rowid, file_name, file_path, content)
-    VALUES (new.id, new.file_name, new.file_path, new.content);
-END;
-
-CREATE TRIGGER IF NOT EXISTS files_fts_update AFTER UPDATE ON module_files BEGIN
-    UPDATE files_fts
-    SET file_name = new.file_name,
-        file_path = new.file_path,
-        content = new.content
-    WHERE rowid = new.id;
-END;
-
-CREATE TRIGGER IF NOT EXISTS files_fts_delete AFTER DELETE ON module_files BEGIN
-    DELETE FROM files_fts WHERE rowid = old.id;
-END;
-
 -- Auto-generated and user-defined aliases for modules
 CREATE TABLE IF NOT EXISTS module_aliases (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -214,6 +278,7 @@ CREATE TABLE IF NOT EXISTS module_releases (
     previous_commit_sha TEXT,
     release_date TEXT,
     comparison_url TEXT,
+    contributors TEXT,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE,
     UNIQUE(module_id, version)
@@ -238,4 +303,100 @@ CREATE TABLE IF NOT EXISTS module_release_entries (
 
 CREATE INDEX IF NOT EXISTS idx_module_release_entries_release ON module_release_entries(release_id);
 CREATE INDEX IF NOT EXISTS idx_module_release_entries_identifier ON module_release_entries(identifier);
+
+-- One row per full or incremental sync, kept across resyncs for history.
+CREATE TABLE IF NOT EXISTS sync_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    sync_type TEXT NOT NULL, -- full|incremental
+    started_at DATETIME NOT NULL,
+    completed_at DATETIME NOT NULL,
+    total_repos INTEGER NOT NULL DEFAULT 0,
+    processed_repos INTEGER NOT NULL DEFAULT 0,
+    skipped_repos INTEGER NOT NULL DEFAULT 0,
+    updated_repos INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0
+);
+`
+
+type schemaColumn struct {
+	table      string
+	column     string
+	definition string
+}
+
+// schemaColumns lists columns added after the initial schema so databases
+// created by older versions are migrated in place.
+var schemaColumns = []schemaColumn{
+	{"modules", "org", "TEXT NOT NULL DEFAULT ''"},
+	{"module_providers", "version", "TEXT"},
+	{"module_releases", "contributors", "TEXT"},
+	{"module_variables", "nullable", "BOOLEAN NOT NULL DEFAULT 1"},
+	{"modules", "commit_sha", "TEXT"},
+	{"module_variables", "source_file", "TEXT"},
+	{"module_variables", "block_source", "TEXT"},
+	{"hcl_blocks", "name_label", "TEXT"},
+	{"modules", "archived", "BOOLEAN NOT NULL DEFAULT 0"},
+	{"modules", "no_terraform", "BOOLEAN NOT NULL DEFAULT 0"},
+	{"module_outputs", "source_file", "TEXT"},
+}
+
+// FTSSchema holds the full-text indexes. It is applied separately so the
+// server still starts against SQLite builds compiled without FTS5.
+const FTSSchema = `
+CREATE VIRTUAL TABLE IF NOT EXISTS modules_fts USING fts5(
+    name,
+    description,
+    readme_content,
+    content='modules',
+    content_rowid='id'
+);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS files_fts USING fts5(
+    file_name,
+    file_path,
+    content,
+    content='module_files',
+    content_rowid='id'
+);
+
+CREATE TRIGGER IF NOT EXISTS modules_fts_insert AFTER INSERT ON modules BEGIN
+    INSERT INTO modules_fts(rowid, name, description, readme_content)
+    VALUES (new.id, new.name, new.description, new.readme_content);
+END;
+
+-- External-content tables must be told the old row explicitly; a plain
+-- UPDATE or DELETE on the index corrupts it.
+DROP TRIGGER IF EXISTS modules_fts_update;
+CREATE TRIGGER modules_fts_update AFTER UPDATE ON modules BEGIN
+    INSERT INTO modules_fts(modules_fts, rowid, name, description, readme_content)
+    VALUES ('delete', old.id, old.name, old.description, old.readme_content);
+    INSERT INTO modules_fts(rowid, name, description, readme_content)
+    VALUES (new.id, new.name, new.description, new.readme_content);
+END;
+
+DROP TRIGGER IF EXISTS modules_fts_delete;
+CREATE TRIGGER modules_fts_delete AFTER DELETE ON modules BEGIN
+    INSERT INTO modules_fts(modules_fts, rowid, name, description, readme_content)
+    VALUES ('delete', old.id, old.name, old.description, old.readme_content);
+END;
+
+-- Triggers to keep files FTS in sync
+CREATE TRIGGER IF NOT EXISTS files_fts_insert AFTER INSERT ON module_files BEGIN
+    INSERT INTO files_fts(rowid, file_name, file_path, content)
+    VALUES (new.id, new.file_name, new.file_path, new.content);
+END;
+
+DROP TRIGGER IF EXISTS files_fts_update;
+CREATE TRIGGER files_fts_update AFTER UPDATE ON module_files BEGIN
+    INSERT INTO files_fts(files_fts, rowid, file_name, file_path, content)
+    VALUES ('delete', old.id, old.file_name, old.file_path, old.content);
+    INSERT INTO files_fts(rowid, file_name, file_path, content)
+    VALUES (new.id, new.file_name, new.file_path, new.content);
+END;
+
+DROP TRIGGER IF EXISTS files_fts_delete;
+CREATE TRIGGER files_fts_delete AFTER DELETE ON module_files BEGIN
+    INSERT INTO files_fts(files_fts, rowid, file_name, file_path, content)
+    VALUES ('delete', old.id, old.file_name, old.file_path, old.content);
+END;
 `
@@ -0,0 +1,436 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+)
+
+func TypeUsageTable(title string, usages []database.TypeUsage, provider string, minCount int) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# %s (%d types)\n\n", title, len(usages)))
+
+	var filters []string
+	if provider != "" {
+		filters = append(filters, fmt.Sprintf("provider `%s`", provider))
+	}
+	if minCount > 1 {
+		filters = append(filters, fmt.Sprintf("used by at least %d modules", minCount))
+	}
+	if len(filters) > 0 {
+		text.WriteString(fmt.Sprintf("Filtered by %s.\n\n", strings.Join(filters, ", ")))
+	}
+
+	if len(usages) == 0 {
+		text.WriteString("No types found.\n")
+		return text.String()
+	}
+
+	text.WriteString("| Type | Provider | Modules | Occurrences |\n")
+	text.WriteString("|------|----------|---------|-------------|\n")
+	for _, u := range usages {
+		text.WriteString(fmt.Sprintf("| %s | %s | %d | %d |\n", u.Type, u.Provider, u.ModuleCount, u.Occurrences))
+	}
+
+	return text.String()
+}
+
+// ResourceTypeUsers lists the modules declaring resourceType. suggestion is
+// a close existing type offered when no module matches.
+func ResourceTypeUsers(resourceType string, users []database.ResourceTypeUser, suggestion string) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Modules using %s (%d)\n\n", resourceType, len(users)))
+
+	if len(users) == 0 {
+		text.WriteString("No module declares this resource type.\n")
+		if suggestion != "" {
+			text.WriteString(fmt.Sprintf("\nDid you mean `%s`?\n", suggestion))
+		}
+		return text.String()
+	}
+
+	text.WriteString("| Module | Instances | Files |\n")
+	text.WriteString("|--------|-----------|-------|\n")
+	for _, u := range users {
+		text.WriteString(fmt.Sprintf("| %s | %d | %s |\n", u.ModuleName, u.Count, strings.Join(u.Files, ", ")))
+	}
+	return text.String()
+}
+
+type CategorizedModule struct {
+	Name        string
+	Description string
+}
+
+func CatalogOverview(order []string, groups map[string][]CategorizedModule) string {
+	var text strings.Builder
+
+	total := 0
+	for _, members := range groups {
+		total += len(members)
+	}
+
+	text.WriteString(fmt.Sprintf("# Catalog Overview (%d modules)\n\n", total))
+	text.WriteString("| Category | Modules |\n")
+	text.WriteString("|----------|---------|\n")
+	for _, category := range order {
+		if len(groups[category]) == 0 {
+			continue
+		}
+		text.WriteString(fmt.Sprintf("| %s | %d |\n", category, len(groups[category])))
+	}
+	text.WriteString("\n")
+
+	for _, category := range order {
+		members := groups[category]
+		if len(members) == 0 {
+			continue
+		}
+		text.WriteString(fmt.Sprintf("## %s (%d)\n\n", category, len(members)))
+		for _, m := range members {
+			text.WriteString(fmt.Sprintf("- **%s**", m.Name))
+			if m.Description != "" {
+				text.WriteString(fmt.Sprintf(" — %s", m.Description))
+			}
+			text.WriteString("\n")
+		}
+		text.WriteString("\n")
+	}
+
+	return text.String()
+}
+
+func ModulesByOrg(orgs []string, groups map[string][]string) string {
+	var text strings.Builder
+	text.WriteString("# Modules by Org\n\n")
+
+	for _, org := range orgs {
+		names := groups[org]
+		text.WriteString(fmt.Sprintf("## %s (%d module%s)\n\n", org, len(names), pluralSuffix(len(names))))
+		for _, name := range names {
+			text.WriteString(fmt.Sprintf("- %s\n", name))
+		}
+		text.WriteString("\n")
+	}
+
+	return text.String()
+}
+
+type SimilarityOverlap struct {
+	Name   string
+	Shared []string
+	Union  int
+	Score  float64
+}
+
+func ModuleSimilarity(moduleA, moduleB string, score float64, overlaps []SimilarityOverlap) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Similarity: %s vs %s\n\n", moduleA, moduleB))
+	text.WriteString(fmt.Sprintf("**Score:** %.2f (0 = unrelated, 1 = identical)\n\n", score))
+
+	text.WriteString("| Dimension | Shared | Total | Jaccard |\n")
+	text.WriteString("|-----------|--------|-------|---------|\n")
+	for _, o := range overlaps {
+		text.WriteString(fmt.Sprintf("| %s | %d | %d | %.2f |\n", o.Name, len(o.Shared), o.Union, o.Score))
+	}
+	text.WriteString("\n")
+
+	for _, o := range overlaps {
+		if len(o.Shared) == 0 {
+			continue
+		}
+		text.WriteString(fmt.Sprintf("## Shared %s\n\n", o.Name))
+		for _, item := range o.Shared {
+			text.WriteString(fmt.Sprintf("- %s\n", item))
+		}
+		text.WriteString("\n")
+	}
+
+	return text.String()
+}
+
+func ResourceInventory(scope string, items []database.ResourceInventoryItem, offset, limit, total int) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Resource Inventory (%s)\n\n", scope))
+	text.WriteString(fmt.Sprintf("Found %d resource%s", total, pluralSuffix(total)))
+	if len(items) > 0 {
+		text.WriteString(fmt.Sprintf(" (showing %d-%d)", offset+1, offset+len(items)))
+	}
+	text.WriteString("\n\n")
+
+	if len(items) == 0 {
+		if offset >= total && total > 0 {
+			text.WriteString(fmt.Sprintf("No results in this range. Total results: %d\n", total))
+		} else {
+			text.WriteString("No resources match the given filters.\n")
+		}
+		return text.String()
+	}
+
+	text.WriteString("| Module | Address | Provider | File |\n")
+	text.WriteString("|--------|---------|----------|------|\n")
+	for _, item := range items {
+		text.WriteString(fmt.Sprintf("| %s | `%s.%s` | %s | %s |\n", item.ModuleName, item.ResourceType, item.ResourceName, item.Provider, item.SourceFile))
+	}
+
+	if offset+len(items) < total {
+		remaining := total - (offset + len(items))
+		text.WriteString(fmt.Sprintf("\n**Pagination:** %d more results available. Use `offset: %d` to see next page.\n", remaining, offset+len(items)))
+	}
+
+	return text.String()
+}
+
+type VariableGroupModule struct {
+	ModuleName string
+	Type       string
+	Shape      string
+}
+
+type VariableGroup struct {
+	Name         string
+	Modules      []VariableGroupModule
+	TypesDiverge bool
+}
+
+func AllVariables(typeFilter string, requiredOnly bool, groups []VariableGroup) string {
+	var text strings.Builder
+	text.WriteString("# Variables Across Modules\n\n")
+
+	var filters []string
+	if typeFilter != "" {
+		filters = append(filters, fmt.Sprintf("type `%s`", typeFilter))
+	}
+	if requiredOnly {
+		filters = append(filters, "required only")
+	}
+	if len(filters) > 0 {
+		text.WriteString(fmt.Sprintf("Filters: %s\n\n", strings.Join(filters, ", ")))
+	}
+
+	if len(groups) == 0 {
+		text.WriteString("No variables match the given filters.\n")
+		return text.String()
+	}
+
+	diverging := 0
+	for _, g := range groups {
+		if g.TypesDiverge {
+			diverging++
+		}
+	}
+	text.WriteString(fmt.Sprintf("Found %d variable name%s, %d with diverging types.\n\n", len(groups), pluralSuffix(len(groups)), diverging))
+
+	text.WriteString("| Variable | Modules | Types diverge | Definitions |\n")
+	text.WriteString("|----------|---------|---------------|-------------|\n")
+	for _, g := range groups {
+		diverge := "no"
+		if g.TypesDiverge {
+			diverge = "**yes**"
+		}
+		defs := make([]string, 0, len(g.Modules))
+		for _, m := range g.Modules {
+			defs = append(defs, fmt.Sprintf("%s: `%s`", m.ModuleName, strings.ReplaceAll(m.Shape, "|", "\\|")))
+		}
+		text.WriteString(fmt.Sprintf("| `%s` | %d | %s | %s |\n", g.Name, len(g.Modules), diverge, strings.Join(defs, "<br>")))
+	}
+
+	return text.String()
+}
+
+func VariableComparison(variableName string, definitions []database.ModuleVariableRef, missing []string) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Variable `%s` Across Modules\n\n", variableName))
+
+	if len(definitions) == 0 {
+		text.WriteString("No module defines this variable.\n")
+		return text.String()
+	}
+
+	typeCounts := make(map[string]int)
+	var types []string
+	for _, d := range definitions {
+		typ := strings.Join(strings.Fields(d.Type), " ")
+		if typ == "" {
+			typ = "any"
+		}
+		if typeCounts[typ] == 0 {
+			types = append(types, typ)
+		}
+		typeCounts[typ]++
+	}
+	text.WriteString(fmt.Sprintf("Defined by %d module%s", len(definitions), pluralSuffix(len(definitions))))
+	switch {
+	case len(definitions) == 1:
+		text.WriteString(fmt.Sprintf(" as `%s`.\n\n", types[0]))
+	case len(types) == 1:
+		text.WriteString(fmt.Sprintf(", all as `%s`.\n\n", types[0]))
+	default:
+		parts := make([]string, 0, len(types))
+		for _, typ := range types {
+			parts = append(parts, fmt.Sprintf("`%s` (%d)", strings.ReplaceAll(typ, "|", "\\|"), typeCounts[typ]))
+		}
+		text.WriteString(fmt.Sprintf(" with **%d different types**: %s.\n\n", len(types), strings.Join(parts, ", ")))
+	}
+
+	text.WriteString("| Module | Type | Default | Required | Sensitive |\n")
+	text.WriteString("|--------|------|---------|----------|-----------|\n")
+	for _, d := range definitions {
+		typ := strings.Join(strings.Fields(d.Type), " ")
+		if typ == "" {
+			typ = "any"
+		}
+		def := "-"
+		if d.DefaultValue != "" {
+			def = fmt.Sprintf("`%s`", strings.ReplaceAll(strings.Join(strings.Fields(d.DefaultValue), " "), "|", "\\|"))
+		}
+		text.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s | %s |\n",
+			d.ModuleName, strings.ReplaceAll(typ, "|", "\\|"), def, yesNo(d.Required), yesNo(d.Sensitive)))
+	}
+
+	if len(missing) > 0 {
+		text.WriteString(fmt.Sprintf("\nNot defined by %d module%s: %s\n", len(missing), pluralSuffix(len(missing)), strings.Join(missing, ", ")))
+	}
+
+	return text.String()
+}
+
+func MostActiveModules(activity []database.ModuleReleaseActivity) string {
+	var text strings.Builder
+	text.WriteString("# Most Active Modules\n\n")
+
+	if len(activity) == 0 {
+		text.WriteString("No release metadata indexed yet. Run sync_modules to ingest changelogs.\n")
+		return text.String()
+	}
+
+	text.WriteString("Ranked by number of indexed releases.\n\n")
+	text.WriteString("| # | Module | Releases | Latest | Released |\n")
+	text.WriteString("|---|--------|----------|--------|----------|\n")
+	for i, a := range activity {
+		latest, date := a.LatestVersion, a.LatestReleaseDate
+		if latest == "" {
+			latest = "-"
+		}
+		if date == "" {
+			date = "-"
+		}
+		text.WriteString(fmt.Sprintf("| %d | %s | %d | %s | %s |\n", i+1, a.ModuleName, a.ReleaseCount, latest, date))
+	}
+
+	return text.String()
+}
+
+// RecentlyUpdatedModules renders modules newest first with their last update
+// date and how long ago that was relative to now.
+func RecentlyUpdatedModules(modules []database.Module, now time.Time) string {
+	var text strings.Builder
+	text.WriteString("# Recently Updated Modules\n\n")
+
+	if len(modules) == 0 {
+		text.WriteString("No modules with an update timestamp found. Run sync_modules tool to fetch modules from GitHub.\n")
+		return text.String()
+	}
+
+	text.WriteString("| # | Module | Last Updated | Age |\n")
+	text.WriteString("|---|--------|--------------|-----|\n")
+	for i, m := range modules {
+		date, age := m.LastUpdated, "-"
+		if t, err := time.Parse(time.RFC3339, m.LastUpdated); err == nil {
+			date = t.UTC().Format("2006-01-02 15:04")
+			age = relativeTime(t, now)
+		}
+		text.WriteString(fmt.Sprintf("| %d | %s%s | %s | %s |\n", i+1, m.Name, archivedMarker(m), date, age))
+	}
+
+	return text.String()
+}
+
+// relativeTime describes how long before now t was, in its largest whole
+// unit, e.g. "3 days ago".
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < time.Minute {
+		return "just now"
+	}
+
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"week", 7 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, u := range units {
+		if n := int(d / u.size); n >= 1 {
+			return fmt.Sprintf("%d %s%s ago", n, u.name, pluralSuffix(n))
+		}
+	}
+	return "just now"
+}
+
+type VariableTypeCount struct {
+	Type      string
+	Variables int
+	Modules   int
+}
+
+// maxTypeDisplayLength truncates long object types in the type vocabulary table.
+const maxTypeDisplayLength = 120
+
+func VariableTypes(types []VariableTypeCount, variableCount, limit int) string {
+	var text strings.Builder
+	text.WriteString("# Variable Types Across Modules\n\n")
+
+	if len(types) == 0 {
+		text.WriteString("No variables indexed yet.\n")
+		return text.String()
+	}
+
+	text.WriteString(fmt.Sprintf("%d variable%s use %d distinct type%s.\n\n", variableCount, pluralSuffix(variableCount), len(types), pluralSuffix(len(types))))
+	text.WriteString("| Type | Variables | Modules |\n")
+	text.WriteString("|------|-----------|---------|\n")
+	for i, t := range types {
+		if i >= limit {
+			break
+		}
+		display := t.Type
+		if len(display) > maxTypeDisplayLength {
+			display = display[:maxTypeDisplayLength] + "…"
+		}
+		text.WriteString(fmt.Sprintf("| `%s` | %d | %d |\n", strings.ReplaceAll(display, "|", "\\|"), t.Variables, t.Modules))
+	}
+	if len(types) > limit {
+		text.WriteString(fmt.Sprintf("\n... and %d more types. Raise `limit` to see them.\n", len(types)-limit))
+	}
+
+	return text.String()
+}
+
+func OutputSearch(query string, outputs []database.ModuleOutputRef) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Outputs matching \"%s\" (%d)\n\n", query, len(outputs)))
+
+	if len(outputs) == 0 {
+		text.WriteString("No outputs match this query. Try a shorter or different term.\n")
+		return text.String()
+	}
+
+	text.WriteString("| Module | Output | Sensitive | Description |\n")
+	text.WriteString("|--------|--------|-----------|-------------|\n")
+	for _, o := range outputs {
+		description := strings.ReplaceAll(strings.Join(strings.Fields(o.Description), " "), "|", "\\|")
+		if description == "" {
+			description = "-"
+		}
+		text.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s |\n", o.ModuleName, o.Name, yesNo(o.Sensitive), description))
+	}
+
+	return text.String()
+}
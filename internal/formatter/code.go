@@ -2,26 +2,127 @@ package formatter
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
 )
 
-func CodeSearchResults(query string, files []database.ModuleFile, getModuleName func(int64) string) string {
+// LineMatcher returns the byte range of the first match in line.
+type LineMatcher func(line string) (start, end int, ok bool)
+
+// SubstringMatcher matches query case-insensitively.
+func SubstringMatcher(query string) LineMatcher {
+	queryLower := strings.ToLower(query)
+	return func(line string) (int, int, bool) {
+		if queryLower == "" {
+			return 0, 0, false
+		}
+		i := strings.Index(strings.ToLower(line), queryLower)
+		if i < 0 {
+			return 0, 0, false
+		}
+		return i, i + len(queryLower), true
+	}
+}
+
+// CaseSensitiveMatcher matches query exactly as written.
+func CaseSensitiveMatcher(query string) LineMatcher {
+	return func(line string) (int, int, bool) {
+		if query == "" {
+			return 0, 0, false
+		}
+		i := strings.Index(line, query)
+		if i < 0 {
+			return 0, 0, false
+		}
+		return i, i + len(query), true
+	}
+}
+
+func CodeSearchResults(query string, files []database.FileSearchHit, getModuleName func(int64) string, match LineMatcher, maxPerFile, contextLines int) string {
+	type fileMatches struct {
+		file  database.FileSearchHit
+		lines []int
+	}
+
+	total := 0
+	results := make([]fileMatches, 0, len(files))
+	for _, file := range files {
+		lines := matchingLines(file.Content, match)
+		total += len(lines)
+		results = append(results, fileMatches{file: file, lines: lines})
+	}
+
 	var text strings.Builder
-	text.WriteString(fmt.Sprintf("# Code Search Results for '%s' (%d matches)\n\n", query, len(files)))
+	text.WriteString(fmt.Sprintf("# Code Search Results for '%s' (%d matches in %d files)\n\n", query, total, len(files)))
 
 	if len(files) == 0 {
 		text.WriteString("No code matches found.\n")
 		return text.String()
 	}
 
-	for _, file := range files {
-		moduleName := getModuleName(file.ModuleID)
-		text.WriteString(fmt.Sprintf("## %s / %s\n", moduleName, file.FilePath))
+	for _, r := range results {
+		moduleName := getModuleName(r.file.ModuleID)
+		text.WriteString(fmt.Sprintf("## %s / %s\n", moduleName, r.file.FilePath))
+		if r.file.Snippet != "" {
+			text.WriteString(fmt.Sprintf("Relevance: %.2f · %s\n", r.file.Score, strings.Join(strings.Fields(r.file.Snippet), " ")))
+		}
 		text.WriteString("```\n")
-		text.WriteString(ExtractCodeContext(file.Content, query))
-		text.WriteString("```\n\n")
+		text.WriteString(matchContext(r.file.Content, r.lines, match, maxPerFile, contextLines))
+		text.WriteString("```\n")
+		if extra := len(r.lines) - maxPerFile; maxPerFile > 0 && extra > 0 {
+			noun := "matches"
+			if extra == 1 {
+				noun = "match"
+			}
+			text.WriteString(fmt.Sprintf("+%d more %s in this file\n", extra, noun))
+		}
+		text.WriteString("\n")
+	}
+
+	return text.String()
+}
+
+func matchingLines(content string, match LineMatcher) []int {
+	var lines []int
+	for i, line := range strings.Split(content, "\n") {
+		if _, _, ok := match(line); ok {
+			lines = append(lines, i)
+		}
+	}
+	return lines
+}
+
+// matchContext renders up to maxMatches matching lines with contextLines lines
+// of context on either side, marking the matched text with « ».
+func matchContext(content string, matches []int, match LineMatcher, maxMatches, contextLines int) string {
+	var text strings.Builder
+	lines := strings.Split(content, "\n")
+
+	for n, i := range matches {
+		if maxMatches > 0 && n >= maxMatches {
+			break
+		}
+		line := lines[i]
+		start, end, _ := match(line)
+		if start < 0 || end > len(line) || start > end {
+			start, end = 0, 0
+		}
+		from := max(i-contextLines, 0)
+		to := min(i+contextLines+1, len(lines))
+
+		for j := from; j < to; j++ {
+			switch {
+			case j == i && end > start:
+				text.WriteString(fmt.Sprintf("→ %d: %s«%s»%s\n", j+1, line[:start], line[start:end], line[end:]))
+			case j == i:
+				text.WriteString(fmt.Sprintf("→ %d: %s\n", j+1, line))
+			default:
+				text.WriteString(fmt.Sprintf("  %d: %s\n", j+1, lines[j]))
+			}
+		}
+		text.WriteString("...\n")
 	}
 
 	return text.String()
@@ -63,12 +164,81 @@ func FileContent(moduleName, filePath, fileType string, sizeBytes int64, content
 	return text.String()
 }
 
-func VariableDefinition(moduleName, variableName, block string) string {
+// FileContents renders several files of a module separated by rules,
+// followed by the requested paths that do not exist.
+func FileContents(moduleName string, files []database.ModuleFile, missing []string) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# %s (%d file%s)\n\n", moduleName, len(files), pluralSuffix(len(files))))
+
+	for i, f := range files {
+		if i > 0 {
+			text.WriteString("\n---\n\n")
+		}
+		text.WriteString(fmt.Sprintf("## %s\n\n", f.FilePath))
+		text.WriteString(fmt.Sprintf("**Size:** %d bytes\n", f.SizeBytes))
+		text.WriteString(fmt.Sprintf("**Type:** %s\n\n", f.FileType))
+		text.WriteString("```hcl\n")
+		text.WriteString(f.Content)
+		text.WriteString("\n```\n")
+	}
+
+	if len(missing) > 0 {
+		text.WriteString("\n## Not Found\n\n")
+		for _, p := range missing {
+			text.WriteString(fmt.Sprintf("- %s\n", p))
+		}
+	}
+	return text.String()
+}
+
+func VersionsFile(moduleName, filePath, matchedBy, content string) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# %s / %s\n\n", moduleName, filePath))
+	text.WriteString(fmt.Sprintf("**File:** %s\n", filePath))
+	text.WriteString(fmt.Sprintf("**Matched by:** %s\n\n", matchedBy))
+	text.WriteString("```hcl\n")
+	text.WriteString(content)
+	text.WriteString("\n```\n")
+	return text.String()
+}
+
+func VariableDefinition(moduleName, variableName, block string, validations []database.VariableValidation) string {
 	var text strings.Builder
 	text.WriteString(fmt.Sprintf("# %s / variable \"%s\"\n\n", moduleName, variableName))
 	text.WriteString("```hcl\n")
 	text.WriteString(block)
 	text.WriteString("\n```\n")
+
+	if len(validations) > 0 {
+		text.WriteString(fmt.Sprintf("\n## Validation rules (%d)\n\n", len(validations)))
+		for _, rule := range validations {
+			text.WriteString("- " + validationLine(rule) + "\n")
+		}
+	}
+	return text.String()
+}
+
+// SourceBlock is the source text of one HCL block and the file declaring it.
+type SourceBlock struct {
+	FilePath string
+	Code     string
+}
+
+func ExtractedBlocks(moduleName, address string, blocks []SourceBlock) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# %s / %s\n\n", moduleName, address))
+	if len(blocks) > 1 {
+		text.WriteString(fmt.Sprintf("Found %d matching blocks.\n\n", len(blocks)))
+	}
+	for i, b := range blocks {
+		if i > 0 {
+			text.WriteString("\n")
+		}
+		text.WriteString(fmt.Sprintf("From `%s`:\n\n", b.FilePath))
+		text.WriteString("```hcl\n")
+		text.WriteString(b.Code)
+		text.WriteString("\n```\n")
+	}
 	return text.String()
 }
 
@@ -108,6 +278,7 @@ func PatternComparison(pattern string, results []PatternMatch, showFullBlocks bo
 }
 
 type PatternMatch struct {
+	ModuleID   int64
 	ModuleName string
 	FileName   string
 	Match      string
@@ -115,6 +286,31 @@ type PatternMatch struct {
 	Summary    string
 }
 
+type PatternCount struct {
+	ModuleName string
+	Matches    int
+	Files      int
+}
+
+func PatternCounts(pattern string, counts []PatternCount, total int) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Pattern Frequency: '%s'\n\n", pattern))
+
+	if len(counts) == 0 {
+		text.WriteString("No matches found.\n")
+		return text.String()
+	}
+
+	text.WriteString(fmt.Sprintf("Found %d matches across %d modules\n\n", total, len(counts)))
+	text.WriteString("| Module | Matches | Files |\n")
+	text.WriteString("|--------|---------|-------|\n")
+	for _, c := range counts {
+		text.WriteString(fmt.Sprintf("| %s | %d | %d |\n", c.ModuleName, c.Matches, c.Files))
+	}
+
+	return text.String()
+}
+
 func formatFullBlocks(results []PatternMatch) string {
 	var text strings.Builder
 	for _, result := range results {
@@ -156,7 +352,9 @@ func formatCompactTable(results []PatternMatch) string {
 	return text.String()
 }
 
-func ExampleList(moduleName string, exampleMap map[string][]string) string {
+// ExampleList renders the examples of a module; descriptions holds the
+// one-line summary taken from each example's README.
+func ExampleList(moduleName string, exampleMap map[string][]string, descriptions map[string]string) string {
 	var text strings.Builder
 	text.WriteString(fmt.Sprintf("# Examples for %s\n\n", moduleName))
 
@@ -166,10 +364,18 @@ func ExampleList(moduleName string, exampleMap map[string][]string) string {
 	}
 
 	text.WriteString(fmt.Sprintf("Found %d example(s):\n\n", len(exampleMap)))
-	for exampleName, fileList := range exampleMap {
+	names := make([]string, 0, len(exampleMap))
+	for exampleName := range exampleMap {
+		names = append(names, exampleName)
+	}
+	sort.Strings(names)
+	for _, exampleName := range names {
 		text.WriteString(fmt.Sprintf("## %s\n", exampleName))
+		if description := descriptions[exampleName]; description != "" {
+			text.WriteString(description + "\n\n")
+		}
 		text.WriteString("Files:\n")
-		for _, fileName := range fileList {
+		for _, fileName := range exampleMap[exampleName] {
 			text.WriteString(fmt.Sprintf("- %s\n", fileName))
 		}
 		text.WriteString("\n")
@@ -178,9 +384,42 @@ func ExampleList(moduleName string, exampleMap map[string][]string) string {
 	return text.String()
 }
 
-func ExampleContent(moduleName, exampleName string, files []database.ModuleFile) string {
+// AllExamples renders the catalog-wide example listing; filter is the
+// example name substring it was narrowed by, if any.
+func AllExamples(examples []database.ExampleSummary, filter string) string {
+	var text strings.Builder
+	modules := make(map[string]struct{})
+	for _, e := range examples {
+		modules[e.ModuleName] = struct{}{}
+	}
+	text.WriteString(fmt.Sprintf("# Examples (%d across %d module%s)\n\n", len(examples), len(modules), pluralSuffix(len(modules))))
+	if filter != "" {
+		text.WriteString(fmt.Sprintf("Filtered by example name containing `%s`.\n\n", filter))
+	}
+
+	if len(examples) == 0 {
+		text.WriteString("No examples found.\n")
+		return text.String()
+	}
+
+	text.WriteString("| Module | Example | Files |\n")
+	text.WriteString("|--------|---------|-------|\n")
+	for _, e := range examples {
+		text.WriteString(fmt.Sprintf("| %s | %s | %d |\n", e.ModuleName, e.Name, e.FileCount))
+	}
+	return text.String()
+}
+
+// ExampleContent renders an example's files, preceded by its README when
+// readme is not empty.
+func ExampleContent(moduleName, exampleName, readme string, files []database.ModuleFile) string {
 	var text strings.Builder
 	text.WriteString(fmt.Sprintf("# %s / examples/%s\n\n", moduleName, exampleName))
+	if readme = strings.TrimSpace(readme); readme != "" {
+		text.WriteString("## About\n\n")
+		text.WriteString(demoteHeadings(readme))
+		text.WriteString("\n\n")
+	}
 	text.WriteString(fmt.Sprintf("Contains %d file(s)\n\n", len(files)))
 
 	for _, file := range files {
@@ -191,6 +430,150 @@ func ExampleContent(moduleName, exampleName string, files []database.ModuleFile)
 	return text.String()
 }
 
+// demoteHeadings nests markdown headings two levels deeper so embedded
+// documents sit below the section rendering them.
+func demoteHeadings(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence && strings.HasPrefix(line, "#") {
+			lines[i] = "##" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ExampleFileDiff is the diff of one file path between two examples.
+// Patch is empty when the file is identical in both.
+type ExampleFileDiff struct {
+	Path     string
+	InA, InB bool
+	Patch    string
+}
+
+func ExampleDiff(moduleName, exampleA, exampleB string, diffs []ExampleFileDiff) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# %s: examples/%s vs examples/%s\n\n", moduleName, exampleA, exampleB))
+
+	var identical []string
+	for _, d := range diffs {
+		if d.Patch == "" {
+			identical = append(identical, d.Path)
+			continue
+		}
+		switch {
+		case !d.InA:
+			text.WriteString(fmt.Sprintf("## %s (only in %s)\n\n", d.Path, exampleB))
+		case !d.InB:
+			text.WriteString(fmt.Sprintf("## %s (only in %s)\n\n", d.Path, exampleA))
+		default:
+			text.WriteString(fmt.Sprintf("## %s\n\n", d.Path))
+		}
+		text.WriteString("```diff\n")
+		text.WriteString(d.Patch)
+		text.WriteString("```\n\n")
+	}
+
+	if len(identical) > 0 {
+		text.WriteString(fmt.Sprintf("Identical in both: %s\n", strings.Join(identical, ", ")))
+	}
+	return text.String()
+}
+
+// ExampleVariableUsage is one assignment of a module input in an example.
+type ExampleVariableUsage struct {
+	Example  string
+	FilePath string
+	Call     string
+	Line     int
+	Snippet  string
+}
+
+func VariableUsageInExamples(moduleName, variableName string, exampleCount int, usages []ExampleVariableUsage) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Usage of %s in %s examples\n\n", variableName, moduleName))
+
+	if len(usages) == 0 {
+		text.WriteString(fmt.Sprintf("None of the %d example%s set `%s`.\n", exampleCount, pluralSuffix(exampleCount), variableName))
+		return text.String()
+	}
+
+	used := make(map[string]struct{})
+	for _, u := range usages {
+		used[u.Example] = struct{}{}
+	}
+	text.WriteString(fmt.Sprintf("Set in %d of %d example%s.\n\n", len(used), exampleCount, pluralSuffix(exampleCount)))
+
+	for _, u := range usages {
+		text.WriteString(fmt.Sprintf("## %s (module \"%s\", %s:%d)\n\n", u.Example, u.Call, u.FilePath, u.Line))
+		text.WriteString("```hcl\n")
+		text.WriteString(u.Snippet)
+		text.WriteString("\n```\n\n")
+	}
+	return text.String()
+}
+
+type ExampleBundle struct {
+	Source         string
+	Ref            string
+	Files          []database.ModuleFile
+	TFVars         []string
+	RequiredInputs []string
+}
+
+func ExampleBundleManifest(moduleName, exampleName string, bundle ExampleBundle) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# %s / examples/%s bundle\n\n", moduleName, exampleName))
+
+	text.WriteString("## Module source\n\n")
+	text.WriteString("Replace the relative `source` in the example's module block with:\n\n")
+	text.WriteString(fmt.Sprintf("```hcl\nsource = \"%s\"\n```\n\n", bundle.Source))
+	if bundle.Ref == "" {
+		text.WriteString("No release tag is indexed; pin `?ref=` to a tag before using this in production.\n\n")
+	}
+
+	text.WriteString("## Required tfvars\n\n")
+	if len(bundle.TFVars) == 0 {
+		text.WriteString("The example declares no variables without defaults.\n\n")
+	} else {
+		text.WriteString("```hcl\n")
+		for _, name := range bundle.TFVars {
+			text.WriteString(fmt.Sprintf("%s = \"\"\n", name))
+		}
+		text.WriteString("```\n\n")
+	}
+
+	if len(bundle.RequiredInputs) > 0 {
+		text.WriteString("## Required module inputs\n\n")
+		for _, name := range bundle.RequiredInputs {
+			text.WriteString(fmt.Sprintf("- %s\n", name))
+		}
+		text.WriteString("\n")
+	}
+
+	text.WriteString("## Steps\n\n")
+	text.WriteString("1. Save the files below into an empty directory\n")
+	text.WriteString("2. Update the module `source` as shown above\n")
+	if len(bundle.TFVars) > 0 {
+		text.WriteString("3. Create `terraform.tfvars` with the values above\n")
+		text.WriteString("4. Run `terraform init && terraform plan`\n\n")
+	} else {
+		text.WriteString("3. Run `terraform init && terraform plan`\n\n")
+	}
+
+	text.WriteString(fmt.Sprintf("## Files (%d)\n\n", len(bundle.Files)))
+	for _, file := range bundle.Files {
+		text.WriteString(fmt.Sprintf("### %s\n\n", file.FilePath))
+		text.WriteString(formatExampleFile(file))
+	}
+
+	return text.String()
+}
+
 func formatExampleFile(file database.ModuleFile) string {
 	var text strings.Builder
 
@@ -235,3 +618,261 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+type ExampleFeatureMatch struct {
+	ExampleName string
+	FilePath    string
+	Snippet     string
+}
+
+func ExampleFeatureMatches(moduleName, feature string, matches []ExampleFeatureMatch) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Examples in %s demonstrating '%s'\n\n", moduleName, feature))
+
+	if len(matches) == 0 {
+		text.WriteString("No examples reference this feature.\n")
+		return text.String()
+	}
+
+	examples := make(map[string]struct{})
+	for _, m := range matches {
+		examples[m.ExampleName] = struct{}{}
+	}
+	text.WriteString(fmt.Sprintf("Found in %d example(s):\n\n", len(examples)))
+
+	current := ""
+	for _, m := range matches {
+		if m.ExampleName != current {
+			current = m.ExampleName
+			text.WriteString(fmt.Sprintf("## %s\n\n", m.ExampleName))
+		}
+		text.WriteString(fmt.Sprintf("**File:** %s\n", m.FilePath))
+		text.WriteString("```hcl\n")
+		text.WriteString(m.Snippet)
+		text.WriteString("```\n\n")
+	}
+
+	return text.String()
+}
+
+type UnknownInput struct {
+	Name       string
+	Suggestion string
+}
+
+// ExampleCallCheck is the validation result of one module call in an example.
+type ExampleCallCheck struct {
+	Call     string
+	FilePath string
+	Inputs   int
+	Unknown  []UnknownInput
+	Missing  []string
+}
+
+func ExampleValidation(moduleName, exampleName string, variableCount int, calls []ExampleCallCheck) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Example validation: %s/%s\n\n", moduleName, exampleName))
+
+	if len(calls) == 0 {
+		text.WriteString("No module call to this module found in the example.\n")
+		return text.String()
+	}
+
+	problems := 0
+	for _, c := range calls {
+		problems += len(c.Unknown) + len(c.Missing)
+	}
+	text.WriteString(fmt.Sprintf("Checked %d module call%s against %d declared variable%s.\n",
+		len(calls), pluralSuffix(len(calls)), variableCount, pluralSuffix(variableCount)))
+	if problems == 0 {
+		text.WriteString("\nAll inputs are declared by the module and every required variable is set.\n")
+		return text.String()
+	}
+
+	for _, c := range calls {
+		text.WriteString(fmt.Sprintf("\n## module.%s (%s)\n\n", c.Call, c.FilePath))
+		text.WriteString(fmt.Sprintf("Inputs set: %d\n", c.Inputs))
+		if len(c.Unknown) == 0 && len(c.Missing) == 0 {
+			text.WriteString("\nNo issues.\n")
+			continue
+		}
+		if len(c.Unknown) > 0 {
+			text.WriteString(fmt.Sprintf("\n**Unknown inputs (%d)**\n", len(c.Unknown)))
+			for _, input := range c.Unknown {
+				if input.Suggestion != "" {
+					text.WriteString(fmt.Sprintf("- `%s` (did you mean `%s`?)\n", input.Name, input.Suggestion))
+				} else {
+					text.WriteString(fmt.Sprintf("- `%s`\n", input.Name))
+				}
+			}
+		}
+		if len(c.Missing) > 0 {
+			text.WriteString(fmt.Sprintf("\n**Missing required inputs (%d)**\n", len(c.Missing)))
+			for _, name := range c.Missing {
+				text.WriteString(fmt.Sprintf("- `%s`\n", name))
+			}
+		}
+	}
+
+	return text.String()
+}
+
+// ModuleLocals renders the locals of a module as one locals block per file,
+// in source order.
+func ModuleLocals(moduleName string, locals []database.ModuleLocal) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Locals of %s (%d)\n", moduleName, len(locals)))
+
+	if len(locals) == 0 {
+		text.WriteString("\nThis module declares no locals.\n")
+		return text.String()
+	}
+
+	current := ""
+	for _, l := range locals {
+		if l.FilePath != current {
+			if current != "" {
+				text.WriteString("}\n```\n")
+			}
+			current = l.FilePath
+			text.WriteString(fmt.Sprintf("\n## %s\n\n```hcl\nlocals {\n", l.FilePath))
+		}
+		text.WriteString(fmt.Sprintf("  %s = %s\n", l.Name, l.Expression))
+	}
+	text.WriteString("}\n```\n")
+	return text.String()
+}
+
+type PinnedExampleSource struct {
+	ModuleName   string
+	Example      string
+	Call         string
+	FilePath     string
+	Source       string
+	TargetModule string
+	Pinned       string
+	Latest       string
+	Outdated     bool
+}
+
+func PinnedExampleSources(moduleCount int, pins []PinnedExampleSource) string {
+	var text strings.Builder
+	text.WriteString("# Pinned Example Sources\n\n")
+	text.WriteString(fmt.Sprintf("Scanned examples of %d module%s.\n\n", moduleCount, pluralSuffix(moduleCount)))
+
+	if len(pins) == 0 {
+		text.WriteString("No example module calls pin a specific version.\n")
+		return text.String()
+	}
+
+	outdated := 0
+	for _, p := range pins {
+		if p.Outdated {
+			outdated++
+		}
+	}
+	text.WriteString(fmt.Sprintf("Found %d pinned module call%s, %d behind the latest release.\n\n", len(pins), pluralSuffix(len(pins)), outdated))
+
+	text.WriteString("| Module | Example | Call | Pinned | Latest | Status |\n")
+	text.WriteString("|--------|---------|------|--------|--------|--------|\n")
+	for _, p := range pins {
+		latest := p.Latest
+		status := "up to date"
+		switch {
+		case latest == "":
+			latest = "-"
+			status = "latest unknown"
+		case p.Outdated:
+			status = "**outdated**"
+		}
+		call := fmt.Sprintf("`module.%s`", p.Call)
+		if p.TargetModule != "" && p.TargetModule != p.ModuleName {
+			call = fmt.Sprintf("`module.%s` → %s", p.Call, p.TargetModule)
+		}
+		text.WriteString(fmt.Sprintf("| %s | %s | %s | `%s` | %s | %s |\n", p.ModuleName, p.Example, call, p.Pinned, latest, status))
+	}
+
+	return text.String()
+}
+
+type QuickstartInput struct {
+	Name        string
+	Shape       string
+	Description string
+}
+
+type Quickstart struct {
+	Description   string
+	Source        string
+	Version       string
+	Ref           string
+	Required      []QuickstartInput
+	Outputs       []database.ModuleOutput
+	ExampleName   string // empty when Usage was generated
+	Label         string
+	Usage         string
+	MissingInputs []string // required inputs the example does not set
+}
+
+// maxQuickstartOutputs keeps the outputs section to the ones most callers read.
+const maxQuickstartOutputs = 8
+
+func QuickstartGuide(moduleName string, q Quickstart) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Quickstart: %s\n\n", moduleName))
+	if q.Description != "" {
+		text.WriteString(q.Description + "\n\n")
+	}
+
+	text.WriteString("## Source\n\n")
+	text.WriteString(fmt.Sprintf("```hcl\nsource = \"%s\"\n```\n\n", q.Source))
+	if q.Ref == "" {
+		text.WriteString("No release is indexed; pin `?ref=` to a tag before using this in production.\n\n")
+	} else {
+		text.WriteString(fmt.Sprintf("Latest release: %s\n\n", q.Version))
+	}
+
+	text.WriteString("## Required inputs\n\n")
+	if len(q.Required) == 0 {
+		text.WriteString("None; every input has a default.\n\n")
+	} else {
+		for _, v := range q.Required {
+			line := fmt.Sprintf("- **%s** (`%s`)", v.Name, v.Shape)
+			if v.Description != "" {
+				line += " - " + v.Description
+			}
+			text.WriteString(line + "\n")
+		}
+		text.WriteString("\n")
+	}
+
+	text.WriteString("## Minimal usage\n\n")
+	if q.ExampleName != "" {
+		text.WriteString(fmt.Sprintf("Adapted from `examples/%s`:\n\n", q.ExampleName))
+	} else {
+		text.WriteString("Generated from the required inputs; replace the placeholder values:\n\n")
+	}
+	text.WriteString(fmt.Sprintf("```hcl\n%s\n```\n\n", q.Usage))
+	if len(q.MissingInputs) > 0 {
+		text.WriteString(fmt.Sprintf("The example does not set these required inputs; add them: %s\n\n", strings.Join(q.MissingInputs, ", ")))
+	}
+
+	text.WriteString("## Key outputs\n\n")
+	if len(q.Outputs) == 0 {
+		text.WriteString("The module declares no outputs.\n")
+		return text.String()
+	}
+	for i, o := range q.Outputs {
+		if i == maxQuickstartOutputs {
+			text.WriteString(fmt.Sprintf("- …and %d more\n", len(q.Outputs)-maxQuickstartOutputs))
+			break
+		}
+		line := fmt.Sprintf("- `module.%s.%s`", q.Label, o.Name)
+		if o.Description != "" {
+			line += " - " + o.Description
+		}
+		text.WriteString(line + "\n")
+	}
+
+	return text.String()
+}
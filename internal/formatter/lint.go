@@ -0,0 +1,202 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+)
+
+type SensitiveLeak struct {
+	OutputName string
+	Reference  string
+	Reason     string
+	FilePath   string
+}
+
+func SensitivePropagation(moduleName string, outputCount int, leaks []SensitiveLeak) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Sensitive Output Check for %s\n\n", moduleName))
+	text.WriteString(fmt.Sprintf("Checked %d output%s.\n\n", outputCount, pluralSuffix(outputCount)))
+
+	if len(leaks) == 0 {
+		text.WriteString("No outputs expose sensitive values without `sensitive = true`.\n")
+		return text.String()
+	}
+
+	text.WriteString(fmt.Sprintf("Found %d suspect reference%s:\n\n", len(leaks), pluralSuffix(len(leaks))))
+	text.WriteString("| Output | Exposes | Reference | File |\n")
+	text.WriteString("|--------|---------|-----------|------|\n")
+	for _, l := range leaks {
+		text.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s |\n", l.OutputName, l.Reason, l.Reference, l.FilePath))
+	}
+	text.WriteString("\nMark these outputs with `sensitive = true` or stop exposing the value.\n")
+
+	return text.String()
+}
+
+type ReservedNameFinding struct {
+	Kind   string
+	Name   string
+	Reason string
+}
+
+func ReservedNames(moduleName string, declarationCount int, findings []ReservedNameFinding) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Reserved Name Check for %s\n\n", moduleName))
+	text.WriteString(fmt.Sprintf("Checked %d variable and output declaration%s.\n\n", declarationCount, pluralSuffix(declarationCount)))
+
+	if len(findings) == 0 {
+		text.WriteString("No declarations use reserved or confusing names.\n")
+		return text.String()
+	}
+
+	text.WriteString(fmt.Sprintf("Found %d offending declaration%s:\n\n", len(findings), pluralSuffix(len(findings))))
+	text.WriteString("| Kind | Name | Reason |\n")
+	text.WriteString("|------|------|--------|\n")
+	for _, f := range findings {
+		text.WriteString(fmt.Sprintf("| %s | `%s` | %s |\n", f.Kind, f.Name, f.Reason))
+	}
+
+	return text.String()
+}
+
+type ReadmeInputsDiff struct {
+	Declared     int
+	Documented   int
+	Undocumented []string
+	Removed      []string
+}
+
+func ReadmeInputsAudit(moduleName string, diff ReadmeInputsDiff) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# README Inputs Audit for %s\n\n", moduleName))
+	text.WriteString(fmt.Sprintf("Declared variables: %d, documented inputs: %d\n\n", diff.Declared, diff.Documented))
+
+	if len(diff.Undocumented) == 0 && len(diff.Removed) == 0 {
+		text.WriteString("The README inputs table matches the declared variables.\n")
+		return text.String()
+	}
+
+	if len(diff.Undocumented) > 0 {
+		text.WriteString(fmt.Sprintf("## Missing from README (%d)\n\n", len(diff.Undocumented)))
+		for _, name := range diff.Undocumented {
+			text.WriteString(fmt.Sprintf("- %s\n", name))
+		}
+		text.WriteString("\n")
+	}
+
+	if len(diff.Removed) > 0 {
+		text.WriteString(fmt.Sprintf("## Documented but not declared (%d)\n\n", len(diff.Removed)))
+		for _, name := range diff.Removed {
+			text.WriteString(fmt.Sprintf("- %s\n", name))
+		}
+		text.WriteString("\n")
+	}
+
+	text.WriteString("Regenerate the README with terraform-docs to resolve the drift.\n")
+	return text.String()
+}
+
+type UnpinnedProviderFinding struct {
+	ModuleName string
+	Provider   string
+	Source     string
+	Constraint string
+	Reason     string
+}
+
+func UnpinnedProviders(scope string, moduleCount, providerCount int, findings []UnpinnedProviderFinding) string {
+	var text strings.Builder
+	if scope != "" {
+		text.WriteString(fmt.Sprintf("# Unpinned Providers in %s\n\n", scope))
+	} else {
+		text.WriteString("# Unpinned Providers\n\n")
+	}
+	text.WriteString(fmt.Sprintf("Checked %d provider requirement%s across %d module%s.\n\n",
+		providerCount, pluralSuffix(providerCount), moduleCount, pluralSuffix(moduleCount)))
+
+	if len(findings) == 0 {
+		text.WriteString("Every provider requirement has an upper version bound.\n")
+		return text.String()
+	}
+
+	text.WriteString(fmt.Sprintf("Found %d loosely pinned provider%s:\n\n", len(findings), pluralSuffix(len(findings))))
+	text.WriteString("| Module | Provider | Constraint | Reason |\n")
+	text.WriteString("|--------|----------|------------|--------|\n")
+	for _, f := range findings {
+		provider := fmt.Sprintf("`%s`", f.Provider)
+		if f.Source != "" {
+			provider = fmt.Sprintf("`%s` (`%s`)", f.Provider, f.Source)
+		}
+		constraint := "-"
+		if f.Constraint != "" {
+			constraint = fmt.Sprintf("`%s`", f.Constraint)
+		}
+		text.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", f.ModuleName, provider, constraint, f.Reason))
+	}
+	text.WriteString("\nAdd an upper bound such as `~> 4.0` so provider major releases are adopted deliberately.\n")
+
+	return text.String()
+}
+
+func ParseErrors(scope string, parseErrors []database.ModuleParseError) string {
+	var text strings.Builder
+	if scope != "" {
+		text.WriteString(fmt.Sprintf("# Parse Errors in %s\n\n", scope))
+	} else {
+		text.WriteString("# Parse Errors\n\n")
+	}
+
+	if len(parseErrors) == 0 {
+		text.WriteString("Every Terraform file parsed successfully during the last sync.\n")
+		return text.String()
+	}
+
+	text.WriteString(fmt.Sprintf("%d file%s could not be parsed; their variables, outputs and resources are missing from the index.\n\n", len(parseErrors), pluralSuffix(len(parseErrors))))
+	current := ""
+	for _, e := range parseErrors {
+		if e.ModuleName != current {
+			current = e.ModuleName
+			text.WriteString(fmt.Sprintf("## %s\n\n", current))
+		}
+		text.WriteString(fmt.Sprintf("- `%s`\n  ```\n  %s\n  ```\n", e.FilePath, strings.ReplaceAll(strings.TrimSpace(e.Message), "\n", "\n  ")))
+	}
+
+	return text.String()
+}
+
+type ModuleHealthFinding struct {
+	ModuleName string
+	Failed     []string
+}
+
+type HealthCheckCount struct {
+	Check   string
+	Modules int
+}
+
+func ModuleHealthReport(moduleCount int, checks []HealthCheckCount, findings []ModuleHealthFinding) string {
+	var text strings.Builder
+	text.WriteString("# Module Health Report\n\n")
+	text.WriteString(fmt.Sprintf("Checked %d module%s, %d with at least one failed check.\n\n",
+		moduleCount, pluralSuffix(moduleCount), len(findings)))
+
+	if len(findings) == 0 {
+		text.WriteString("Every module has examples, a README, variables and outputs.\n")
+		return text.String()
+	}
+
+	for _, c := range checks {
+		text.WriteString(fmt.Sprintf("- %s: %d\n", c.Check, c.Modules))
+	}
+	text.WriteString("\n")
+
+	text.WriteString("| Module | Failed checks |\n")
+	text.WriteString("|--------|---------------|\n")
+	for _, f := range findings {
+		text.WriteString(fmt.Sprintf("| %s | %s |\n", f.ModuleName, strings.Join(f.Failed, ", ")))
+	}
+
+	return text.String()
+}
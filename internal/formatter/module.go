@@ -3,13 +3,18 @@ package formatter
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/schema"
 )
 
-func ModuleList(modules []database.Module) string {
+// ModuleList renders the module catalog; providers maps module IDs to their
+// indexed providers, primary first.
+func ModuleList(modules []database.Module, providers map[int64][]string) string {
 	var text strings.Builder
 	text.WriteString(fmt.Sprintf("# Azure CloudNation Terraform Modules (%d modules)\n\n", len(modules)))
 
@@ -18,47 +23,121 @@ func ModuleList(modules []database.Module) string {
 			text.WriteString(fmt.Sprintf("... and %d more modules\n", len(modules)-50))
 			break
 		}
-		text.WriteString(fmt.Sprintf("**%s**\n", module.Name))
+		text.WriteString(fmt.Sprintf("**%s**", module.Name))
+		if p := providers[module.ID]; len(p) > 0 {
+			text.WriteString(fmt.Sprintf(" (%s)", strings.Join(p, ", ")))
+		}
+		text.WriteString(archivedMarker(module) + noTerraformMarker(module) + "\n")
 		if module.Description != "" {
 			text.WriteString(fmt.Sprintf("  %s\n", module.Description))
 		}
-		text.WriteString(fmt.Sprintf("  Repo: %s\n", module.RepoURL))
+		if module.RepoURL != "" {
+			text.WriteString(fmt.Sprintf("  Repo: %s\n", module.RepoURL))
+		}
 		text.WriteString(fmt.Sprintf("  Last synced: %s\n\n", module.SyncedAt.Format("2006-01-02 15:04:05")))
 	}
 
 	return text.String()
 }
 
-func SearchResults(query string, modules []database.Module) string {
+func archivedMarker(module database.Module) string {
+	if module.Archived {
+		return " *[archived]*"
+	}
+	return ""
+}
+
+func noTerraformMarker(module database.Module) string {
+	if module.NoTerraform {
+		return " *[no Terraform configuration indexed]*"
+	}
+	return ""
+}
+
+// SearchHit is a search_modules result with the fields the query matched
+// (name, description, readme or topic) and the terms to highlight.
+type SearchHit struct {
+	Module    database.Module
+	Topics    []string
+	MatchedOn []string
+	Terms     []string
+}
+
+func SearchResults(query string, hits []SearchHit) string {
 	var text strings.Builder
-	text.WriteString(fmt.Sprintf("# Search Results for '%s' (%d matches)\n\n", query, len(modules)))
+	text.WriteString(fmt.Sprintf("# Search Results for '%s' (%d matches)\n\n", query, len(hits)))
 
-	for _, module := range modules {
-		text.WriteString(fmt.Sprintf("**%s**\n", module.Name))
+	for _, hit := range hits {
+		module := hit.Module
+		text.WriteString(fmt.Sprintf("**%s**%s\n", module.Name, archivedMarker(module)))
 		if module.Description != "" {
-			text.WriteString(fmt.Sprintf("  %s\n", module.Description))
+			text.WriteString(fmt.Sprintf("  %s\n", highlightTerms(module.Description, hit.Terms)))
+		}
+		if len(hit.Topics) > 0 {
+			text.WriteString(fmt.Sprintf("  Topics: %s\n", highlightTerms(strings.Join(hit.Topics, ", "), hit.Terms)))
+		}
+		if len(hit.MatchedOn) > 0 {
+			text.WriteString(fmt.Sprintf("  Matched on: %s\n", strings.Join(hit.MatchedOn, ", ")))
 		}
-		text.WriteString(fmt.Sprintf("  Repo: %s\n\n", module.RepoURL))
+		if module.RepoURL != "" {
+			text.WriteString(fmt.Sprintf("  Repo: %s\n", module.RepoURL))
+		}
+		text.WriteString("\n")
 	}
 
-	if len(modules) == 0 {
+	if len(hits) == 0 {
 		text.WriteString("No modules found matching your query.\n")
 	}
 
 	return text.String()
 }
 
-func ModuleInfo(module *database.Module, variables []database.ModuleVariable, outputs []database.ModuleOutput, resources []database.ModuleResource, files []database.ModuleFile) string {
+// highlightTerms bolds every case-insensitive occurrence of terms in s,
+// preferring the longest term where matches overlap.
+func highlightTerms(s string, terms []string) string {
+	if len(terms) == 0 {
+		return s
+	}
+	sorted := append([]string(nil), terms...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	quoted := make([]string, 0, len(sorted))
+	for _, t := range sorted {
+		if t != "" {
+			quoted = append(quoted, regexp.QuoteMeta(t))
+		}
+	}
+	if len(quoted) == 0 {
+		return s
+	}
+	re := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+	return re.ReplaceAllString(s, "**$0**")
+}
+
+func ModuleInfo(module *database.Module, topics []string, variables []database.ModuleVariable, outputs []database.ModuleOutput, resources []database.ModuleResource, files []database.ModuleFile) string {
 	var text strings.Builder
 	text.WriteString(fmt.Sprintf("# %s\n\n", module.Name))
 
+	if module.Archived {
+		text.WriteString("> **Archived:** the repository is archived on GitHub; this module is deprecated and no longer maintained.\n\n")
+	}
+
 	if module.Description != "" {
 		text.WriteString(fmt.Sprintf("**Description:** %s\n\n", module.Description))
 	}
 
-	text.WriteString(fmt.Sprintf("**Repository:** %s\n", module.RepoURL))
+	if module.RepoURL != "" {
+		text.WriteString(fmt.Sprintf("**Repository:** %s\n", module.RepoURL))
+	}
 	text.WriteString(fmt.Sprintf("**Last Updated:** %s\n", module.LastUpdated))
-	text.WriteString(fmt.Sprintf("**Last Synced:** %s\n\n", module.SyncedAt.Format("2006-01-02 15:04:05")))
+	text.WriteString(fmt.Sprintf("**Last Synced:** %s\n", module.SyncedAt.Format("2006-01-02 15:04:05")))
+	if len(topics) > 0 {
+		text.WriteString(fmt.Sprintf("**Topics:** %s\n", strings.Join(topics, ", ")))
+	}
+	text.WriteString("\n")
+
+	if module.NoTerraform {
+		text.WriteString("> **No Terraform configuration indexed:** the repository contains no .tf files, so there are no variables, outputs or resources to show.\n\n")
+	}
 
 	if len(variables) > 0 {
 		text.WriteString(VariablesSection(variables))
@@ -118,18 +197,46 @@ func VariablesSection(variables []database.ModuleVariable) string {
 		if v.Sensitive {
 			text.WriteString(" *[sensitive]*")
 		}
+		if !v.Nullable {
+			text.WriteString(" *[non-nullable]*")
+		}
 		if v.DefaultValue != "" {
 			text.WriteString(fmt.Sprintf(" - default: `%s`", v.DefaultValue))
 		}
 		if v.Description != "" {
 			text.WriteString(fmt.Sprintf("\n  %s", v.Description))
 		}
+		for _, rule := range v.Validations {
+			text.WriteString("\n  " + validationLine(rule))
+		}
+		if len(v.OptionalAttributes) > 0 {
+			text.WriteString("\n  Optional attributes:")
+			for _, attr := range v.OptionalAttributes {
+				text.WriteString("\n  - " + optionalAttributeLine(attr))
+			}
+		}
 		text.WriteString("\n")
 	}
 	text.WriteString("\n")
 	return text.String()
 }
 
+func optionalAttributeLine(attr database.OptionalAttribute) string {
+	line := fmt.Sprintf("`%s` (%s)", attr.Path, attr.Type)
+	if attr.Default != "" {
+		line += fmt.Sprintf(" - default: `%s`", attr.Default)
+	}
+	return line
+}
+
+func validationLine(rule database.VariableValidation) string {
+	line := fmt.Sprintf("Validation: `%s`", strings.Join(strings.Fields(rule.Condition), " "))
+	if rule.ErrorMessage != "" {
+		line += " — " + rule.ErrorMessage
+	}
+	return line
+}
+
 func OutputsSection(outputs []database.ModuleOutput) string {
 	var text strings.Builder
 	text.WriteString("## Outputs\n\n")
@@ -147,6 +254,196 @@ func OutputsSection(outputs []database.ModuleOutput) string {
 	return text.String()
 }
 
+func OutputsWithValues(moduleName string, outputs []database.ModuleOutput) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Outputs of %s (%d)\n\n", moduleName, len(outputs)))
+
+	if len(outputs) == 0 {
+		text.WriteString("This module declares no outputs.\n")
+		return text.String()
+	}
+
+	for _, o := range outputs {
+		text.WriteString(fmt.Sprintf("## %s", o.Name))
+		if o.Sensitive {
+			text.WriteString(" *[sensitive]*")
+		}
+		text.WriteString("\n\n")
+		if o.Description != "" {
+			text.WriteString(fmt.Sprintf("%s\n\n", o.Description))
+		}
+		if o.Value != "" {
+			text.WriteString(fmt.Sprintf("```hcl\nvalue = %s\n```\n\n", o.Value))
+		} else {
+			text.WriteString("*Value expression not indexed; run a sync to refresh.*\n\n")
+		}
+	}
+
+	return text.String()
+}
+
+func PublicAPI(moduleName, hash string, variables []database.ModuleVariable, outputs []database.ModuleOutput) string {
+	var required, optional []database.ModuleVariable
+	for _, v := range variables {
+		if v.Required {
+			required = append(required, v)
+		} else {
+			optional = append(optional, v)
+		}
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Public API of %s\n\n", moduleName))
+	text.WriteString(fmt.Sprintf("**API hash:** `%s`\n\n", hash))
+	text.WriteString("The hash covers variable names, types, defaults, sensitivity and nullability plus output names and sensitivity; compare it between versions to detect interface changes.\n\n")
+
+	text.WriteString(fmt.Sprintf("## Required inputs (%d)\n\n", len(required)))
+	if len(required) == 0 {
+		text.WriteString("None.\n")
+	}
+	for _, v := range required {
+		text.WriteString(publicAPIVariableLine(v, false))
+	}
+
+	text.WriteString(fmt.Sprintf("\n## Optional inputs (%d)\n\n", len(optional)))
+	if len(optional) == 0 {
+		text.WriteString("None.\n")
+	}
+	for _, v := range optional {
+		text.WriteString(publicAPIVariableLine(v, true))
+	}
+
+	text.WriteString(fmt.Sprintf("\n## Outputs (%d)\n\n", len(outputs)))
+	if len(outputs) == 0 {
+		text.WriteString("None.\n")
+	}
+	for _, o := range outputs {
+		text.WriteString(fmt.Sprintf("- `%s`", o.Name))
+		if o.Sensitive {
+			text.WriteString(" *[sensitive]*")
+		}
+		if o.Description != "" {
+			text.WriteString(fmt.Sprintf(" - %s", o.Description))
+		}
+		text.WriteString("\n")
+	}
+
+	return text.String()
+}
+
+func publicAPIVariableLine(v database.ModuleVariable, withDefault bool) string {
+	typ := strings.Join(strings.Fields(v.Type), " ")
+	if typ == "" {
+		typ = "any"
+	}
+	line := fmt.Sprintf("- `%s`: `%s`", v.Name, typ)
+	if withDefault {
+		def := strings.Join(strings.Fields(v.DefaultValue), " ")
+		if def == "" {
+			def = "null"
+		}
+		line += fmt.Sprintf(" = `%s`", def)
+	}
+	if v.Sensitive {
+		line += " *[sensitive]*"
+	}
+	return line + "\n"
+}
+
+func ModuleDefaults(moduleName string, variables []database.ModuleVariable) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Variable defaults: %s\n\n", moduleName))
+	if len(variables) == 0 {
+		text.WriteString("This module declares no variables.\n")
+		return text.String()
+	}
+
+	required := 0
+	for _, v := range variables {
+		if v.Required {
+			required++
+		}
+	}
+	text.WriteString(fmt.Sprintf("%d variable%s, %d required without a default.\n\n", len(variables), pluralSuffix(len(variables)), required))
+
+	text.WriteString("| Name | Default | Notes |\n")
+	text.WriteString("|------|---------|-------|\n")
+	for _, v := range variables {
+		def := "—"
+		if !v.Required {
+			def = strings.Join(strings.Fields(v.DefaultValue), " ")
+			if def == "" {
+				def = "null"
+			}
+			def = fmt.Sprintf("`%s`", strings.ReplaceAll(def, "|", "\\|"))
+		}
+		var notes []string
+		if v.Required {
+			notes = append(notes, "**required**")
+		}
+		if v.Sensitive {
+			notes = append(notes, "sensitive")
+		}
+		text.WriteString(fmt.Sprintf("| %s | %s | %s |\n", v.Name, def, strings.Join(notes, ", ")))
+	}
+	return text.String()
+}
+
+// ModuleDefaultsHCL renders the defaults as tfvars assignments. Required
+// variables are commented out with the shape of their type so they stand out
+// as the values still to fill in.
+func ModuleDefaultsHCL(moduleName string, variables []database.ModuleVariable) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Variable defaults: %s\n\n", moduleName))
+	if len(variables) == 0 {
+		text.WriteString("This module declares no variables.\n")
+		return text.String()
+	}
+
+	width := 0
+	for _, v := range variables {
+		width = max(width, len(v.Name))
+	}
+
+	text.WriteString("```hcl\n")
+	for _, v := range variables {
+		if v.Required {
+			shape := "any"
+			if parsed, err := schema.Parse(v.Type); err == nil {
+				shape = parsed.Shape()
+			}
+			text.WriteString(fmt.Sprintf("# %-*s = <%s> (required)\n", width, v.Name, shape))
+			continue
+		}
+		def := strings.TrimSpace(v.DefaultValue)
+		if def == "" {
+			def = "null"
+		}
+		text.WriteString(fmt.Sprintf("%-*s = %s\n", width, v.Name, def))
+	}
+	text.WriteString("```\n")
+	return text.String()
+}
+
+func ProvidersSection(providers []database.ModuleProvider) string {
+	var text strings.Builder
+	text.WriteString("## Providers\n\n")
+	for _, p := range providers {
+		text.WriteString(fmt.Sprintf("- **%s**", p.Name))
+		if p.Source != "" {
+			text.WriteString(fmt.Sprintf(" (`%s`)", p.Source))
+		}
+		if p.Version != "" {
+			text.WriteString(fmt.Sprintf(" `%s`", p.Version))
+		}
+		if p.IsPrimary {
+			text.WriteString(" *[primary]*")
+		}
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
 func ResourcesSection(resources []database.ModuleResource) string {
 	var text strings.Builder
 	text.WriteString(fmt.Sprintf("## Resources (%d)\n\n", len(resources)))
@@ -165,6 +462,170 @@ func ResourcesSection(resources []database.ModuleResource) string {
 	return text.String()
 }
 
+func DataSources(moduleName string, dataSources []database.ModuleDataSource) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Data Sources of %s (%d)\n\n", moduleName, len(dataSources)))
+
+	if len(dataSources) == 0 {
+		text.WriteString("This module reads no data sources.\n")
+		return text.String()
+	}
+
+	for _, d := range dataSources {
+		text.WriteString(fmt.Sprintf("- `data \"%s\" \"%s\"`", d.DataType, d.DataName))
+		if d.Provider != "" {
+			text.WriteString(fmt.Sprintf(" - provider: %s", d.Provider))
+		}
+		if d.SourceFile != "" {
+			text.WriteString(fmt.Sprintf(" (in %s)", d.SourceFile))
+		}
+		text.WriteString("\n")
+	}
+
+	return text.String()
+}
+
+func ModuleProviders(moduleName string, providers []database.ModuleProvider) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Providers of %s (%d)\n\n", moduleName, len(providers)))
+
+	if len(providers) == 0 {
+		text.WriteString("No providers found in required_providers or resource types.\n")
+		return text.String()
+	}
+
+	text.WriteString("| Provider | Source | Constraint | Declared in |\n")
+	text.WriteString("|----------|--------|------------|-------------|\n")
+	for _, p := range providers {
+		name := p.Name
+		if p.IsPrimary {
+			name += " (primary)"
+		}
+		source, constraint, file := "-", "-", "-"
+		if p.Source != "" {
+			source = "`" + p.Source + "`"
+		}
+		if p.Version != "" {
+			constraint = "`" + p.Version + "`"
+		}
+		if p.SourceFile != "" {
+			file = p.SourceFile
+		}
+		text.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", name, source, constraint, file))
+	}
+
+	unpinned := 0
+	for _, p := range providers {
+		if p.Version == "" {
+			unpinned++
+		}
+	}
+	if unpinned > 0 {
+		text.WriteString(fmt.Sprintf("\n%d provider%s without a version constraint.\n", unpinned, pluralSuffix(unpinned)))
+	}
+
+	return text.String()
+}
+
+func Submodules(parentName string, submodules []database.Submodule) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Submodules of %s (%d)\n\n", parentName, len(submodules)))
+
+	if len(submodules) == 0 {
+		text.WriteString("This module has no nested modules under modules/.\n")
+		return text.String()
+	}
+
+	text.WriteString("| Module | Files | Resources |\n")
+	text.WriteString("|--------|-------|-----------|\n")
+	for _, sm := range submodules {
+		text.WriteString(fmt.Sprintf("| %s | %d | %d |\n", sm.Name, sm.FileCount, sm.ResourceCount))
+	}
+	text.WriteString("\nPass a submodule name as module_name to other tools to inspect it.\n")
+
+	return text.String()
+}
+
+func ResourceAttributes(moduleName, resourceType string, resources []database.ModuleResource) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Resource Attributes of %s\n\n", moduleName))
+
+	if len(resources) == 0 {
+		if resourceType != "" {
+			text.WriteString(fmt.Sprintf("This module declares no %s resources.\n", resourceType))
+		} else {
+			text.WriteString("This module declares no resources.\n")
+		}
+		return text.String()
+	}
+
+	for _, r := range resources {
+		text.WriteString(fmt.Sprintf("## %s.%s", r.ResourceType, r.ResourceName))
+		if r.SourceFile != "" {
+			text.WriteString(fmt.Sprintf(" (%s)", r.SourceFile))
+		}
+		text.WriteString("\n\n")
+
+		var arguments, blocks []string
+		for _, attr := range r.Attributes {
+			switch attr.Kind {
+			case "attribute":
+				arguments = append(arguments, fmt.Sprintf("`%s`", attr.Name))
+			case "dynamic":
+				blocks = append(blocks, fmt.Sprintf("`%s` (dynamic)", attr.Name))
+			default:
+				blocks = append(blocks, fmt.Sprintf("`%s`", attr.Name))
+			}
+		}
+		if len(arguments) == 0 && len(blocks) == 0 {
+			text.WriteString("No arguments set.\n\n")
+			continue
+		}
+		if len(arguments) > 0 {
+			text.WriteString(fmt.Sprintf("- Arguments: %s\n", strings.Join(arguments, ", ")))
+		}
+		if len(blocks) > 0 {
+			text.WriteString(fmt.Sprintf("- Blocks: %s\n", strings.Join(blocks, ", ")))
+		}
+		text.WriteString("\n")
+	}
+
+	return text.String()
+}
+
+// ModuleCall is a module dependency with the catalog module it resolves to,
+// when that module is indexed.
+type ModuleCall struct {
+	database.ModuleDependency
+	Target string
+}
+
+func ModuleDependencies(moduleName string, calls []ModuleCall) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Module Dependencies of %s (%d)\n\n", moduleName, len(calls)))
+
+	if len(calls) == 0 {
+		text.WriteString("This module calls no other modules.\n")
+		return text.String()
+	}
+
+	text.WriteString("| Call | Kind | Source | Version | Catalog module | File |\n")
+	text.WriteString("|------|------|--------|---------|----------------|------|\n")
+	for _, c := range calls {
+		version := c.Version
+		if version == "" {
+			version = "-"
+		}
+		target := c.Target
+		if target == "" {
+			target = "-"
+		}
+		text.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s | %s | %s |\n", c.Name, c.SourceKind, c.Source, version, target, c.FilePath))
+	}
+
+	return text.String()
+}
+
 func FilesSection(files []database.ModuleFile) string {
 	var text strings.Builder
 	text.WriteString(fmt.Sprintf("## Files (%d)\n\n", len(files)))
@@ -183,6 +644,86 @@ func FilesSection(files []database.ModuleFile) string {
 	return text.String()
 }
 
+type treeNode struct {
+	dirs  map[string]*treeNode
+	files []string
+	count int
+}
+
+func newTreeNode() *treeNode {
+	return &treeNode{dirs: make(map[string]*treeNode)}
+}
+
+// ModuleTree renders repository-relative file paths as an indented tree,
+// directories before files at every level.
+func ModuleTree(moduleName string, paths []string) string {
+	root := newTreeNode()
+	for _, p := range paths {
+		parts := strings.Split(p, "/")
+		node := root
+		node.count++
+		for _, dir := range parts[:len(parts)-1] {
+			child, ok := node.dirs[dir]
+			if !ok {
+				child = newTreeNode()
+				node.dirs[dir] = child
+			}
+			child.count++
+			node = child
+		}
+		node.files = append(node.files, parts[len(parts)-1])
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# File tree: %s\n\n", moduleName))
+	if len(paths) == 0 {
+		text.WriteString("No files indexed for this module.\n")
+		return text.String()
+	}
+
+	summary := fmt.Sprintf("%d file%s", len(paths), pluralSuffix(len(paths)))
+	if examples, ok := root.dirs["examples"]; ok {
+		summary += fmt.Sprintf(", %d example%s", len(examples.dirs), pluralSuffix(len(examples.dirs)))
+	}
+	if modules, ok := root.dirs["modules"]; ok {
+		summary += fmt.Sprintf(", %d submodule%s", len(modules.dirs), pluralSuffix(len(modules.dirs)))
+	}
+	text.WriteString(summary + "\n\n```\n.\n")
+	writeTree(&text, root, "")
+	text.WriteString("```\n")
+	return text.String()
+}
+
+func writeTree(text *strings.Builder, node *treeNode, indent string) {
+	dirNames := make([]string, 0, len(node.dirs))
+	for name := range node.dirs {
+		dirNames = append(dirNames, name)
+	}
+	sort.Strings(dirNames)
+	sort.Strings(node.files)
+
+	total := len(dirNames) + len(node.files)
+	i := 0
+	entry := func(label string) string {
+		i++
+		if i == total {
+			text.WriteString(indent + "└── " + label + "\n")
+			return indent + "    "
+		}
+		text.WriteString(indent + "├── " + label + "\n")
+		return indent + "│   "
+	}
+
+	for _, name := range dirNames {
+		child := node.dirs[name]
+		childIndent := entry(fmt.Sprintf("%s/ (%d)", name, child.count))
+		writeTree(text, child, childIndent)
+	}
+	for _, name := range node.files {
+		entry(name)
+	}
+}
+
 func ReadmeExcerpt(readme string) string {
 	var text strings.Builder
 	text.WriteString("## README (excerpt)\n\n")
@@ -199,6 +740,43 @@ func ReadmeExcerpt(readme string) string {
 	return text.String()
 }
 
+func ModuleReadme(moduleName, readme string) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# README: %s\n\n", moduleName))
+	text.WriteString(strings.TrimSpace(readme))
+	text.WriteString("\n")
+	return text.String()
+}
+
+func ReadmeSections(moduleName string, sections []ReadmeSection) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# README: %s\n", moduleName))
+	for _, section := range sections {
+		text.WriteString(fmt.Sprintf("\n## %s\n\n", section.Heading))
+		if section.Content == "" {
+			text.WriteString("_(empty section)_\n")
+			continue
+		}
+		text.WriteString(section.Content)
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+func ReadmeSectionNotFound(moduleName, section string, headings []string) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("No README section matching '%s' in %s", section, moduleName))
+	if len(headings) == 0 {
+		text.WriteString(" (the README has no headings)")
+		return text.String()
+	}
+	text.WriteString("\n\nAvailable sections:\n")
+	for _, heading := range headings {
+		text.WriteString(fmt.Sprintf("- %s\n", heading))
+	}
+	return text.String()
+}
+
 func IncrementalSyncProgress(totalRepos, synced, skipped int, updatedRepos, errors []string) string {
 	var text strings.Builder
 	text.WriteString("# Incremental Sync Completed\n\n")
@@ -273,6 +851,35 @@ func JobList(jobs []JobInfo) string {
 	return text.String()
 }
 
+func IndexStatus(stats *database.IndexStats, dbSize int64, runs []database.SyncRun) string {
+	var text strings.Builder
+	text.WriteString("# Index Status\n\n")
+	text.WriteString(fmt.Sprintf("- Modules: %d\n", stats.ModuleCount))
+	text.WriteString(fmt.Sprintf("- Files: %d\n", stats.FileCount))
+	if dbSize > 0 {
+		text.WriteString(fmt.Sprintf("- Database size: %.1f MB\n", float64(dbSize)/(1<<20)))
+	}
+	if stats.LastSyncedAt.IsZero() {
+		text.WriteString("- Last synced: never\n")
+	} else {
+		text.WriteString(fmt.Sprintf("- Last synced: %s\n", stats.LastSyncedAt.UTC().Format("2006-01-02 15:04:05 UTC")))
+	}
+
+	if len(runs) > 0 {
+		text.WriteString("\n## Recent Syncs\n\n")
+		text.WriteString("| Started | Type | Duration | Repos | Skipped | Updated | Errors |\n")
+		text.WriteString("|---------|------|----------|-------|---------|---------|--------|\n")
+		for _, r := range runs {
+			text.WriteString(fmt.Sprintf("| %s | %s | %s | %d/%d | %d | %d | %d |\n",
+				r.StartedAt.UTC().Format("2006-01-02 15:04"), r.SyncType,
+				r.CompletedAt.Sub(r.StartedAt).Round(time.Second),
+				r.ProcessedRepos, r.TotalRepos, r.SkippedRepos, r.UpdatedRepos, r.ErrorCount))
+		}
+	}
+
+	return text.String()
+}
+
 type JobInfo struct {
 	ID          string
 	Type        string
@@ -280,3 +887,150 @@ type JobInfo struct {
 	StartedAt   time.Time
 	CompletedAt *time.Time
 }
+
+func TerraformVersion(moduleName string, versions []database.ModuleTerraformVersion) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Terraform version for %s\n\n", moduleName))
+
+	if len(versions) == 0 {
+		text.WriteString("**required_version:** not specified\n\n")
+		text.WriteString("The module does not constrain the Terraform CLI version.\n")
+		return text.String()
+	}
+
+	primary := versions[0]
+	text.WriteString(fmt.Sprintf("**required_version:** `%s`\n", primary.RequiredVersion))
+	text.WriteString(fmt.Sprintf("**Declared in:** %s\n", primary.SourceFile))
+
+	if len(versions) > 1 {
+		text.WriteString("\nOther declarations:\n")
+		for _, v := range versions[1:] {
+			text.WriteString(fmt.Sprintf("- `%s` (in %s)\n", v.RequiredVersion, v.SourceFile))
+		}
+	}
+
+	return text.String()
+}
+
+type VariableComplexity struct {
+	Name       string
+	Score      int
+	Depth      int
+	FieldCount int
+	Shape      string
+}
+
+// UnparsedType is a variable whose type expression could not be parsed.
+type UnparsedType struct {
+	Name string
+	Type string
+}
+
+func ComplexVariables(moduleName string, totalVariables int, ranked []VariableComplexity, unparsed []UnparsedType) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Most complex variables in %s\n\n", moduleName))
+
+	switch {
+	case len(ranked) == 0 && len(unparsed) == 0:
+		text.WriteString(fmt.Sprintf("All %d variables use primitive types.\n", totalVariables))
+	case len(ranked) == 0:
+		text.WriteString(fmt.Sprintf("%d of %d variables use primitive types.\n", totalVariables-len(unparsed), totalVariables))
+	default:
+		text.WriteString("| Variable | Score | Depth | Fields | Shape |\n")
+		text.WriteString("|----------|-------|-------|--------|-------|\n")
+		for _, v := range ranked {
+			text.WriteString(fmt.Sprintf("| %s | %d | %d | %d | `%s` |\n", v.Name, v.Score, v.Depth, v.FieldCount, v.Shape))
+		}
+		text.WriteString("\nScore = total object attributes + 2 × nesting depth.\n")
+	}
+
+	if len(unparsed) > 0 {
+		text.WriteString(fmt.Sprintf("\n## Unparsed types (%d)\n\n", len(unparsed)))
+		text.WriteString("These type expressions could not be parsed and are not ranked:\n\n")
+		for _, u := range unparsed {
+			text.WriteString(fmt.Sprintf("- %s: `%s`\n", u.Name, u.Type))
+		}
+	}
+
+	return text.String()
+}
+
+func VariableFields(moduleName, variableName, rootType string, fields []schema.FlatField) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# %s / variable \"%s\" fields\n\n", moduleName, variableName))
+	text.WriteString(fmt.Sprintf("**Type:** `%s`\n\n", rootType))
+
+	if len(fields) == 0 {
+		text.WriteString("This variable has no object attributes.\n")
+		return text.String()
+	}
+
+	text.WriteString("| Path | Type | Optional | Default |\n")
+	text.WriteString("|------|------|----------|---------|\n")
+	for _, f := range fields {
+		optional := "no"
+		if f.Optional {
+			optional = "yes"
+		}
+		def := "-"
+		if f.Default != "" {
+			def = fmt.Sprintf("`%s`", f.Default)
+		}
+		text.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s |\n", f.Path, f.Type, optional, def))
+	}
+	text.WriteString("\n`[*]` marks each element of a list, set or map.\n")
+
+	return text.String()
+}
+
+type RegionInput struct {
+	Path     string
+	Required bool
+	Default  string
+}
+
+type SupportedRegionsReport struct {
+	Inputs      []RegionInput
+	Allowlist   []string
+	ValidatedBy []string
+}
+
+func SupportedRegions(moduleName string, r SupportedRegionsReport) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("# Supported Regions for %s\n\n", moduleName))
+
+	if len(r.Allowlist) > 0 {
+		text.WriteString(fmt.Sprintf("**Designed for:** %s\n\n", strings.Join(r.Allowlist, ", ")))
+		text.WriteString(fmt.Sprintf("Validation on %s restricts the accepted regions.\n\n", backtickList(r.ValidatedBy)))
+	} else {
+		text.WriteString("**Designed for:** any region (no region allowlist found)\n\n")
+	}
+
+	text.WriteString("## Location inputs\n\n")
+	if len(r.Inputs) == 0 {
+		text.WriteString("No location or region input found; the region is probably taken from a resource group or another input.\n")
+		return text.String()
+	}
+	for _, in := range r.Inputs {
+		text.WriteString(fmt.Sprintf("- `%s`", in.Path))
+		if in.Required {
+			text.WriteString(" *[required]*")
+		} else {
+			text.WriteString(" *[optional]*")
+		}
+		if in.Default != "" {
+			text.WriteString(fmt.Sprintf(" - default: `%s`", in.Default))
+		}
+		text.WriteString("\n")
+	}
+
+	return text.String()
+}
+
+func backtickList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("`%s`", item)
+	}
+	return strings.Join(quoted, ", ")
+}
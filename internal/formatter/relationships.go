@@ -167,3 +167,10 @@ func pluralSuffix(n int) string {
 	}
 	return "s"
 }
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
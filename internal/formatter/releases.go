@@ -23,6 +23,13 @@ func ReleaseSummary(moduleName string, release *database.ModuleRelease, entries
 	b.WriteString(fmt.Sprintf("- Module: %s\n", name))
 	b.WriteString(fmt.Sprintf("- Range: %s\n", renderRange(release)))
 	b.WriteString(fmt.Sprintf("- Date: %s\n", releaseDateOrFallback(release)))
+	people, bots := splitContributors(release.Contributors.String)
+	if len(people) > 0 {
+		b.WriteString(fmt.Sprintf("- Contributors: %s\n", strings.Join(people, ", ")))
+	}
+	if len(bots) > 0 {
+		b.WriteString(fmt.Sprintf("- Automated: %s\n", strings.Join(bots, ", ")))
+	}
 
 	sections := groupEntriesBySection(entries)
 	if len(sections.order) == 0 {
@@ -40,6 +47,23 @@ func ReleaseSummary(moduleName string, release *database.ModuleRelease, entries
 	return b.String()
 }
 
+// splitContributors separates bot accounts from human contributors in a
+// stored comma-separated contributor list.
+func splitContributors(list string) ([]string, []string) {
+	var people, bots []string
+	for name := range strings.SplitSeq(list, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+		case strings.HasSuffix(strings.ToLower(name), "[bot]"):
+			bots = append(bots, name)
+		default:
+			people = append(people, name)
+		}
+	}
+	return people, bots
+}
+
 type sectionGrouping struct {
 	order   []string
 	entries map[string][]string
@@ -124,3 +148,521 @@ func shortSHA(sha string) string {
 	}
 	return sha
 }
+
+func FileDiff(moduleName, filePath, fromTag, toTag, status, previousPath, patch string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s: %s\n\n", moduleName, filePath))
+	b.WriteString(fmt.Sprintf("**Range:** %s → %s\n", fromTag, toTag))
+
+	switch status {
+	case "unchanged":
+		b.WriteString("**Status:** unchanged\n")
+		b.WriteString("\nNo changes to this file between the two tags.\n")
+		return b.String()
+	case "absent":
+		b.WriteString("**Status:** absent\n")
+		b.WriteString(fmt.Sprintf("\nThe file does not exist at %s or %s.\n", fromTag, toTag))
+		return b.String()
+	case "added":
+		b.WriteString(fmt.Sprintf("**Status:** added — the file does not exist at %s\n", fromTag))
+	case "removed":
+		b.WriteString(fmt.Sprintf("**Status:** removed — the file does not exist at %s\n", toTag))
+	case "renamed":
+		if previousPath != "" && previousPath != filePath {
+			b.WriteString(fmt.Sprintf("**Status:** renamed from %s\n", previousPath))
+		} else {
+			b.WriteString("**Status:** renamed\n")
+		}
+	default:
+		b.WriteString(fmt.Sprintf("**Status:** %s\n", status))
+	}
+
+	if strings.TrimSpace(patch) == "" {
+		b.WriteString("\nGitHub did not return a patch for this file (binary or too large).\n")
+		return b.String()
+	}
+
+	b.WriteString("\n```diff\n")
+	b.WriteString(strings.TrimRight(patch, "\n"))
+	b.WriteString("\n```\n")
+	return b.String()
+}
+
+// IntroducedInResult is the outcome of scanning releases oldest to newest.
+// FirstScanned is set when the item is already in the oldest release of the
+// range and PreviousSkipped when the release just before the match could not
+// be scanned, so the item may be older than Version.
+type IntroducedInResult struct {
+	Item            string
+	Kind            string
+	Version         string
+	Tag             string
+	Scanned         int
+	FirstScanned    bool
+	PreviousSkipped bool
+	Truncated       bool
+	Skipped         []string
+}
+
+func IntroducedIn(moduleName string, r IntroducedInResult) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# When was `%s` introduced in %s?\n\n", r.Item, moduleName))
+
+	switch {
+	case r.Kind == "":
+		b.WriteString(fmt.Sprintf("Not found in any of the %d scanned release%s.\n", r.Scanned, pluralSuffix(r.Scanned)))
+	case r.FirstScanned && r.Truncated:
+		b.WriteString(fmt.Sprintf("The %s `%s` is present in the oldest scanned release %s; it may be older than the scanned range.\n", r.Kind, r.Item, r.Tag))
+	case r.FirstScanned:
+		b.WriteString(fmt.Sprintf("The %s `%s` is present in all indexed releases (since %s).\n", r.Kind, r.Item, r.Tag))
+	case r.PreviousSkipped:
+		b.WriteString(fmt.Sprintf("The %s `%s` is present in **%s** (%s); the release before it could not be scanned, so it may have been introduced earlier.\n", r.Kind, r.Item, r.Version, r.Tag))
+	default:
+		b.WriteString(fmt.Sprintf("The %s `%s` was introduced in **%s** (%s).\n", r.Kind, r.Item, r.Version, r.Tag))
+	}
+
+	if r.Truncated {
+		b.WriteString("\nOlder releases were not scanned; raise max_releases to look further back.\n")
+	}
+	if len(r.Skipped) > 0 {
+		b.WriteString("\nSkipped releases:\n")
+		for _, s := range r.Skipped {
+			b.WriteString(fmt.Sprintf("- %s\n", s))
+		}
+	}
+
+	return b.String()
+}
+
+type VariableProvenanceResult struct {
+	Variable        string
+	Version         string
+	Tag             string
+	Changes         []string
+	MissingInLatest string
+	OldestScanned   string
+	Scanned         int
+	Truncated       bool
+	Skipped         []string
+}
+
+func VariableProvenance(moduleName string, r VariableProvenanceResult) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Last change to `%s` in %s\n\n", r.Variable, moduleName))
+
+	switch {
+	case r.MissingInLatest != "":
+		b.WriteString(fmt.Sprintf("The variable `%s` is not declared in the latest release %s.\n", r.Variable, r.MissingInLatest))
+	case r.Tag != "":
+		b.WriteString(fmt.Sprintf("Last modified in **%s** (%s):\n\n", r.Version, r.Tag))
+		for _, change := range r.Changes {
+			b.WriteString(fmt.Sprintf("- %s\n", change))
+		}
+	case r.OldestScanned != "" && r.Truncated:
+		b.WriteString(fmt.Sprintf("Unchanged across the %d scanned release%s; the last change predates %s.\n", r.Scanned, pluralSuffix(r.Scanned), r.OldestScanned))
+	case r.OldestScanned != "":
+		b.WriteString(fmt.Sprintf("Unchanged in all indexed releases (declared since %s).\n", r.OldestScanned))
+	default:
+		b.WriteString("No release could be scanned.\n")
+	}
+
+	if r.Truncated && r.Tag == "" && r.MissingInLatest == "" {
+		b.WriteString("\nOlder releases were not scanned; raise max_releases to look further back.\n")
+	}
+	if len(r.Skipped) > 0 {
+		b.WriteString("\nSkipped releases:\n")
+		for _, s := range r.Skipped {
+			b.WriteString(fmt.Sprintf("- %s\n", s))
+		}
+	}
+
+	return b.String()
+}
+
+type UnmentionedChange struct {
+	FilePath string
+	Status   string
+	Lines    int
+}
+
+type ChangelogVerificationReport struct {
+	Range            string
+	EntryCount       int
+	FileCount        int
+	UnmatchedEntries []string
+	UnmentionedFiles []UnmentionedChange
+}
+
+func ChangelogVerification(moduleName, version string, r ChangelogVerificationReport) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Changelog verification: %s %s\n\n", moduleName, version))
+	b.WriteString(fmt.Sprintf("- Range: %s\n", r.Range))
+	b.WriteString(fmt.Sprintf("- Changelog entries: %d\n", r.EntryCount))
+	b.WriteString(fmt.Sprintf("- Changed files: %d\n\n", r.FileCount))
+
+	if len(r.UnmatchedEntries) == 0 && len(r.UnmentionedFiles) == 0 {
+		b.WriteString("Every entry maps to a changed file and no significant change is unmentioned.\n")
+		return b.String()
+	}
+
+	if len(r.UnmatchedEntries) > 0 {
+		b.WriteString("## Entries without a matching code change\n\n")
+		for _, title := range r.UnmatchedEntries {
+			b.WriteString(fmt.Sprintf("- %s\n", title))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.UnmentionedFiles) > 0 {
+		b.WriteString("## Changed files not mentioned in the changelog\n\n")
+		for _, f := range r.UnmentionedFiles {
+			b.WriteString(fmt.Sprintf("- %s (%s, %d lines)\n", f.FilePath, f.Status, f.Lines))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("This is a best-effort keyword match; review flagged items manually.\n")
+	return b.String()
+}
+
+type BreakingChange struct {
+	Version string
+	Date    string
+	Title   string
+}
+
+type ReadmeSection struct {
+	Heading string
+	Content string
+}
+
+type MigrationNotesReport struct {
+	StateBlocks     []database.ModuleStateMigration
+	BreakingChanges []BreakingChange
+	ReadmeSections  []ReadmeSection
+	ReleaseCount    int
+}
+
+func MigrationNotes(moduleName string, r MigrationNotesReport) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Migration notes: %s\n\n", moduleName))
+
+	if len(r.StateBlocks) == 0 && len(r.BreakingChanges) == 0 && len(r.ReadmeSections) == 0 {
+		b.WriteString(fmt.Sprintf("No migration guidance found (checked module code, %d release%s and the README).\n", r.ReleaseCount, pluralSuffix(r.ReleaseCount)))
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("- State refactors: %d\n", len(r.StateBlocks)))
+	b.WriteString(fmt.Sprintf("- Breaking changes: %d (across %d release%s)\n", len(r.BreakingChanges), r.ReleaseCount, pluralSuffix(r.ReleaseCount)))
+	b.WriteString(fmt.Sprintf("- README sections: %d\n", len(r.ReadmeSections)))
+
+	if len(r.StateBlocks) > 0 {
+		b.WriteString("\n## State refactors\n\n")
+		for _, block := range r.StateBlocks {
+			b.WriteString(stateMigrationLine(block))
+		}
+	}
+
+	if len(r.BreakingChanges) > 0 {
+		b.WriteString("\n## Breaking changes\n\n")
+		for _, change := range r.BreakingChanges {
+			version := change.Version
+			if change.Date != "" {
+				version = fmt.Sprintf("%s, %s", version, change.Date)
+			}
+			b.WriteString(fmt.Sprintf("- **%s** — %s\n", version, change.Title))
+		}
+	}
+
+	for _, section := range r.ReadmeSections {
+		b.WriteString(fmt.Sprintf("\n## README: %s\n\n", section.Heading))
+		if section.Content == "" {
+			b.WriteString("_(empty section)_\n")
+			continue
+		}
+		b.WriteString(section.Content)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func stateMigrationLine(m database.ModuleStateMigration) string {
+	location := fmt.Sprintf("%s:%d", m.FilePath, m.Line)
+	switch m.BlockType {
+	case "moved":
+		return fmt.Sprintf("- moved: `%s` → `%s` (%s)\n", m.FromAddress, m.ToAddress, location)
+	case "import":
+		return fmt.Sprintf("- import: id `%s` → `%s` (%s)\n", m.ImportID, m.ToAddress, location)
+	}
+	return fmt.Sprintf("- %s: `%s` (%s)\n", m.BlockType, m.FromAddress, location)
+}
+
+func StateMigrations(moduleName string, migrations []database.ModuleStateMigration) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# State migrations of %s (%d)\n\n", moduleName, len(migrations)))
+
+	if len(migrations) == 0 {
+		b.WriteString("This module declares no moved, removed or import blocks.\n")
+		return b.String()
+	}
+
+	for _, kind := range []string{"moved", "removed", "import"} {
+		var lines []string
+		for _, m := range migrations {
+			if m.BlockType == kind {
+				lines = append(lines, stateMigrationLine(m))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("## %s (%d)\n\n", kind, len(lines)))
+		b.WriteString(strings.Join(lines, ""))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("Terraform applies these while planning, so state follows the refactor without manual `terraform state mv`.\n")
+	return b.String()
+}
+
+type DeclarationDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+func (d DeclarationDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+type VersionComparison struct {
+	FromTag      string
+	ToTag        string
+	ChangedFiles []string
+	Variables    DeclarationDiff
+	Outputs      DeclarationDiff
+	Resources    DeclarationDiff
+	DataSources  DeclarationDiff
+}
+
+func CompareVersions(moduleName string, c VersionComparison) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s: %s → %s\n\n", moduleName, c.FromTag, c.ToTag))
+
+	sections := []struct {
+		noun string
+		diff DeclarationDiff
+	}{
+		{"variable", c.Variables},
+		{"output", c.Outputs},
+		{"resource", c.Resources},
+		{"data source", c.DataSources},
+	}
+
+	var summary []string
+	for _, s := range sections {
+		if n := len(s.diff.Added); n > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s%s added", n, s.noun, pluralSuffix(n)))
+		}
+		if n := len(s.diff.Removed); n > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s%s removed", n, s.noun, pluralSuffix(n)))
+		}
+		if n := len(s.diff.Changed); n > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s%s changed", n, s.noun, pluralSuffix(n)))
+		}
+	}
+
+	if len(summary) == 0 {
+		if len(c.ChangedFiles) == 0 {
+			b.WriteString("No Terraform files of this module changed between these versions.\n")
+		} else {
+			b.WriteString(fmt.Sprintf("%d Terraform file%s changed, but no variables, outputs, resources or data sources were added, removed or redeclared.\n", len(c.ChangedFiles), pluralSuffix(len(c.ChangedFiles))))
+		}
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("**Summary:** %s\n", strings.Join(summary, ", ")))
+	b.WriteString(fmt.Sprintf("**Changed Terraform files:** %s\n", strings.Join(c.ChangedFiles, ", ")))
+
+	for _, s := range sections {
+		if s.diff.empty() {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("\n## %ss\n\n", strings.ToUpper(s.noun[:1])+s.noun[1:]))
+		for _, name := range s.diff.Added {
+			b.WriteString(fmt.Sprintf("- added `%s`\n", name))
+		}
+		for _, name := range s.diff.Removed {
+			b.WriteString(fmt.Sprintf("- removed `%s`\n", name))
+		}
+		for _, change := range s.diff.Changed {
+			b.WriteString(fmt.Sprintf("- changed %s\n", change))
+		}
+	}
+
+	return b.String()
+}
+
+type ModuleTag struct {
+	Name        string
+	CommitSHA   string
+	InChangelog bool
+}
+
+func ModuleTags(moduleName string, tags []ModuleTag, truncated bool) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Tags for %s (%d)\n\n", moduleName, len(tags)))
+
+	if len(tags) == 0 {
+		b.WriteString("No tags found.\n")
+		return b.String()
+	}
+
+	undocumented := 0
+	b.WriteString("| Tag | Commit | Changelog |\n")
+	b.WriteString("|-----|--------|-----------|\n")
+	for _, tag := range tags {
+		changelog := "yes"
+		if !tag.InChangelog {
+			changelog = "no"
+			undocumented++
+		}
+		b.WriteString(fmt.Sprintf("| %s | %s | %s |\n", tag.Name, shortSHA(tag.CommitSHA), changelog))
+	}
+
+	if undocumented > 0 {
+		b.WriteString(fmt.Sprintf("\n%d tag%s without a changelog entry.\n", undocumented, pluralSuffix(undocumented)))
+	}
+	if truncated {
+		b.WriteString("\nThe tag list was capped; further tags may exist. Raise limit to fetch more.\n")
+	}
+
+	return b.String()
+}
+
+type ReleaseListing struct {
+	Version    string
+	Tag        string
+	Date       string
+	EntryCount int
+}
+
+func ModuleReleases(moduleName string, releases []ReleaseListing) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Releases of %s (%d)\n\n", moduleName, len(releases)))
+
+	if len(releases) == 0 {
+		b.WriteString("No releases indexed. Run backfill_release to ingest the changelog.\n")
+		return b.String()
+	}
+
+	b.WriteString("| Version | Tag | Date | Entries |\n")
+	b.WriteString("|---------|-----|------|---------|\n")
+	for _, r := range releases {
+		date := r.Date
+		if date == "" {
+			date = "-"
+		}
+		b.WriteString(fmt.Sprintf("| %s | %s | %s | %d |\n", r.Version, r.Tag, date, r.EntryCount))
+	}
+	b.WriteString("\nUse get_release_summary with a version for the full notes.\n")
+
+	return b.String()
+}
+
+// ReleaseNotes pairs a release with its changelog entries.
+type ReleaseNotes struct {
+	Release database.ModuleRelease
+	Entries []database.ModuleReleaseEntry
+}
+
+// ChangelogRange renders the entries of several releases grouped by section,
+// each tagged with the version that introduced it. Releases are expected
+// newest first.
+func ChangelogRange(moduleName, from, to, compareURL string, releases []ReleaseNotes) string {
+	var b strings.Builder
+	b.WriteString("Module Changelog Range\n")
+	b.WriteString(fmt.Sprintf("- Module: %s\n", moduleName))
+	b.WriteString(fmt.Sprintf("- Range: %s → %s\n", from, to))
+
+	versions := make([]string, 0, len(releases))
+	var entries []database.ModuleReleaseEntry
+	for _, r := range releases {
+		versions = append(versions, r.Release.Version)
+		for _, entry := range r.Entries {
+			entry.Title = fmt.Sprintf("%s (%s)", entry.Title, r.Release.Version)
+			entries = append(entries, entry)
+		}
+	}
+	b.WriteString(fmt.Sprintf("- Releases: %s\n", strings.Join(versions, ", ")))
+	if compareURL != "" {
+		b.WriteString(fmt.Sprintf("- Compare: %s\n", compareURL))
+	}
+
+	sections := groupEntriesBySection(entries)
+	if len(sections.order) == 0 {
+		b.WriteString("- No categorized entries found\n")
+		return b.String()
+	}
+
+	for _, section := range sections.order {
+		b.WriteString(fmt.Sprintf("- %s\n", section))
+		for _, title := range sections.entries[section] {
+			b.WriteString(fmt.Sprintf("    - %s\n", title))
+		}
+	}
+
+	return b.String()
+}
+
+type ResourceRef struct {
+	Address string
+	File    string
+}
+
+type ResourceTypeChange struct {
+	From ResourceRef
+	To   ResourceRef
+}
+
+type ResourceComparison struct {
+	FromTag     string
+	ToTag       string
+	Added       []ResourceRef
+	Removed     []ResourceRef
+	TypeChanged []ResourceTypeChange
+	Unchanged   int
+}
+
+func ResourceChanges(moduleName string, c ResourceComparison) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Resource changes in %s: %s → %s\n\n", moduleName, c.FromTag, c.ToTag))
+	b.WriteString(fmt.Sprintf("**Summary:** %d added, %d removed, %d type changed, %d unchanged\n", len(c.Added), len(c.Removed), len(c.TypeChanged), c.Unchanged))
+
+	if len(c.Added) == 0 && len(c.Removed) == 0 && len(c.TypeChanged) == 0 {
+		b.WriteString("\nThe resource set is identical in both versions.\n")
+		return b.String()
+	}
+
+	if len(c.Removed) > 0 {
+		b.WriteString("\n## Removed (destroyed on upgrade unless moved)\n\n")
+		for _, r := range c.Removed {
+			b.WriteString(fmt.Sprintf("- `%s` (%s)\n", r.Address, r.File))
+		}
+	}
+	if len(c.TypeChanged) > 0 {
+		b.WriteString("\n## Type changed (replaced on upgrade)\n\n")
+		for _, t := range c.TypeChanged {
+			b.WriteString(fmt.Sprintf("- `%s` → `%s` (%s)\n", t.From.Address, t.To.Address, t.To.File))
+		}
+	}
+	if len(c.Added) > 0 {
+		b.WriteString("\n## Added (created on upgrade)\n\n")
+		for _, r := range c.Added {
+			b.WriteString(fmt.Sprintf("- `%s` (%s)\n", r.Address, r.File))
+		}
+	}
+
+	return b.String()
+}
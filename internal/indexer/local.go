@@ -0,0 +1,229 @@
+package indexer
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var gitHubRemotePattern = regexp.MustCompile(`github\.com[:/]([^/\s]+)/([^/\s]+?)(?:\.git)?/?$`)
+
+// SetLocalSource makes the syncer index cloned repositories below root
+// instead of fetching them from GitHub. Each subdirectory named with the
+// repository prefix is treated as one module repository.
+func (s *Syncer) SetLocalSource(root string) {
+	s.localRoot = root
+}
+
+func (s *Syncer) isLocal() bool {
+	return s.localRoot != ""
+}
+
+func (s *Syncer) fetchLocalRepositories() ([]GitHubRepo, error) {
+	entries, err := os.ReadDir(s.localRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local source %s: %w", s.localRoot, err)
+	}
+
+	var repos []GitHubRepo
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), s.repoPrefix) {
+			continue
+		}
+
+		dir := filepath.Join(s.localRoot, entry.Name())
+		updatedAt, err := latestModTime(dir)
+		if err != nil {
+			log.Printf("Skipping %s (unreadable directory: %v)", entry.Name(), err)
+			continue
+		}
+		if updatedAt.IsZero() {
+			log.Printf("Skipping %s (empty directory)", entry.Name())
+			continue
+		}
+
+		fullName := localFullName(dir, s.org, entry.Name())
+		repo := GitHubRepo{
+			Name:      entry.Name(),
+			FullName:  fullName,
+			UpdatedAt: updatedAt.UTC().Format(time.RFC3339),
+		}
+		// Without an owner there is no GitHub page to link to.
+		if strings.Contains(fullName, "/") {
+			repo.HTMLURL = "https://github.com/" + fullName
+		}
+		repos = append(repos, repo)
+	}
+
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+	return repos, nil
+}
+
+// localFullName derives owner/name from the checkout's origin remote so that
+// release and source tools keep pointing at the upstream repository.
+func localFullName(dir, org, name string) string {
+	if remote := originRemoteURL(dir); remote != "" {
+		if m := gitHubRemotePattern.FindStringSubmatch(remote); m != nil {
+			return m[1] + "/" + m[2]
+		}
+	}
+	if org == "" {
+		return name
+	}
+	return org + "/" + name
+}
+
+func originRemoteURL(dir string) string {
+	f, err := os.Open(filepath.Join(dir, ".git", "config"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func latestModTime(dir string) (time.Time, error) {
+	var latest time.Time
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		if d.IsDir() {
+			if rel != "." && shouldSkipPath(filepath.ToSlash(rel)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest, err
+}
+
+func (s *Syncer) fetchLocalReadme(repoName string) (string, error) {
+	dir := filepath.Join(s.localRoot, repoName)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(entry.Name(), "README.md") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	return "", fmt.Errorf("no README found in %s", dir)
+}
+
+// checkLocalRepository reports ErrRepoContentUnavailable when the checkout
+// of a repository is missing.
+func (s *Syncer) checkLocalRepository(repoName string) error {
+	if _, err := os.Stat(filepath.Join(s.localRoot, repoName)); err != nil {
+		return ErrRepoContentUnavailable
+	}
+	return nil
+}
+
+func (s *Syncer) indexLocalRepository(moduleID int64, repo GitHubRepo) (bool, []int64, error) {
+	return s.indexDirectory(filepath.Join(s.localRoot, repo.Name), moduleID, repo)
+}
+
+// indexDirectory stores the files of a local checkout, routing files under
+// modules/ to their submodule records.
+func (s *Syncer) indexDirectory(dir string, moduleID int64, repo GitHubRepo) (bool, []int64, error) {
+	examplesFound := false
+	submoduleIDs := make(map[string]int64)
+	var submoduleOrder []int64
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		relativePath := filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if relativePath != "." && shouldSkipPath(relativePath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || shouldSkipPath(relativePath) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if reason := s.oversizeReason(relativePath, info.Size()); reason != "" {
+			log.Printf("Skipping %s/%s (%s)", repo.Name, relativePath, reason)
+			return nil
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", relativePath, err)
+		}
+		if isBinaryContent(content) {
+			log.Printf("Skipping %s/%s (binary content)", repo.Name, relativePath)
+			return nil
+		}
+
+		targetModuleID, _ := s.resolveTargetModule(moduleID, relativePath, repo, submoduleIDs, &submoduleOrder)
+
+		if err := s.insertModuleFile(targetModuleID, relativePath, int64(len(content)), content); err != nil {
+			log.Printf("Warning: failed to insert file %s: %v", relativePath, err)
+		}
+
+		if strings.HasPrefix(relativePath, "examples/") {
+			examplesFound = true
+		}
+		return nil
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
+	return examplesFound, submoduleOrder, nil
+}
@@ -0,0 +1,29 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAcquire(t *testing.T) {
+	exhausted := func() *RateLimiter {
+		return &RateLimiter{tokens: 0, maxTokens: 60, refillAt: time.Now().Add(time.Hour)}
+	}
+
+	if err := exhausted().acquire(context.Background()); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("interactive acquire = %v, want ErrRateLimited", err)
+	}
+
+	ctx, cancel := context.WithCancel(waitForRateLimit(context.Background()))
+	cancel()
+	if err := exhausted().acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled sync acquire = %v, want context.Canceled", err)
+	}
+
+	rl := &RateLimiter{tokens: 1, maxTokens: 60, refillAt: time.Now().Add(time.Hour)}
+	if err := rl.acquire(context.Background()); err != nil || rl.tokens != 0 {
+		t.Errorf("acquire with budget = %v, tokens %d, want nil and 0", err, rl.tokens)
+	}
+}
@@ -2,15 +2,18 @@ package indexer
 
 import (
 	"bufio"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/util"
 )
 
 const maxReleaseHistory = 40
@@ -33,23 +36,32 @@ type parsedSection struct {
 	Entries []string
 }
 
-func (s *Syncer) captureModuleReleaseMetadata(moduleID int64, repo GitHubRepo) error {
+func (s *Syncer) captureModuleReleaseMetadata(ctx context.Context, moduleID int64, repo GitHubRepo) error {
 	file, err := s.findChangelogFile(repo.Name)
 	if err != nil {
 		return err
 	}
-	if file == nil || strings.TrimSpace(file.Content) == "" {
-		return nil
+
+	var releases []parsedRelease
+	if file != nil && strings.TrimSpace(file.Content) != "" {
+		releases = parseChangelogReleases(file.Content)
 	}
 
-	releases := parseChangelogReleases(file.Content)
+	remote := !s.isLocal() && s.githubClient != nil
+	if remote {
+		if fetched, err := s.githubClient.listReleases(ctx, repo.FullName, maxReleaseHistory); err == nil {
+			releases = mergeGitHubReleases(releases, fetched)
+		} else {
+			log.Printf("Warning: failed to fetch releases for %s: %v", repo.FullName, err)
+		}
+	}
 	if len(releases) == 0 {
 		return nil
 	}
 
 	var tags []GitHubTag
-	if s.githubClient != nil {
-		if fetched, err := s.githubClient.listTags(repo.FullName, 5); err == nil {
+	if remote {
+		if fetched, err := s.githubClient.listTags(ctx, repo.FullName, 5); err == nil {
 			tags = fetched
 		} else {
 			log.Printf("Warning: failed to fetch tags for %s: %v", repo.FullName, err)
@@ -104,6 +116,106 @@ func (s *Syncer) captureModuleReleaseMetadata(moduleID int64, repo GitHubRepo) e
 	return nil
 }
 
+// mergeGitHubReleases adds published GitHub releases to the releases parsed
+// from the changelog. The changelog wins when both describe a version; the
+// release only fills in a missing date or missing entries. The result is
+// ordered newest first so previous-release links line up.
+func mergeGitHubReleases(releases []parsedRelease, published []GitHubRelease) []parsedRelease {
+	byVersion := make(map[string]int, len(releases))
+	for idx, rel := range releases {
+		byVersion[rel.Version] = idx
+	}
+
+	for _, gh := range published {
+		if gh.Draft {
+			continue
+		}
+		rel, ok := parseGitHubRelease(gh)
+		if !ok {
+			continue
+		}
+		idx, exists := byVersion[rel.Version]
+		if !exists {
+			byVersion[rel.Version] = len(releases)
+			releases = append(releases, rel)
+			continue
+		}
+		if releases[idx].ReleaseDate == "" {
+			releases[idx].ReleaseDate = rel.ReleaseDate
+		}
+		if !hasReleaseEntries(releases[idx]) {
+			releases[idx].Sections = rel.Sections
+		}
+	}
+
+	util.SortNewestFirst(releases, func(r parsedRelease) string { return r.Version })
+	if len(releases) > maxReleaseHistory {
+		releases = releases[:maxReleaseHistory]
+	}
+	return releases
+}
+
+// parseGitHubRelease converts a GitHub release into a parsedRelease, reading
+// its body with the same line parser as a changelog block.
+func parseGitHubRelease(gh GitHubRelease) (parsedRelease, bool) {
+	version := normalizeVersion(gh.TagName)
+	if version == "" {
+		return parsedRelease{}, false
+	}
+
+	rel := parsedRelease{
+		Version:  version,
+		Tag:      strings.TrimSpace(gh.TagName),
+		Sections: []*parsedSection{},
+	}
+	if t, err := time.Parse(time.RFC3339, gh.PublishedAt); err == nil {
+		rel.ReleaseDate = t.Format("2006-01-02")
+	}
+
+	var currentSection *parsedSection
+	scanner := bufio.NewScanner(strings.NewReader(gh.Body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "## ") {
+			currentSection = nil
+			continue
+		}
+		currentSection = parseReleaseLine(&rel, currentSection, line)
+	}
+
+	return rel, true
+}
+
+// parseReleaseLine applies one line of a release block to rel: a ### heading
+// opens a section and list entries go into the current one, opening an
+// "Other" section when none is open. It returns the section now current.
+func parseReleaseLine(rel *parsedRelease, section *parsedSection, line string) *parsedSection {
+	if remainder, ok := strings.CutPrefix(line, "### "); ok {
+		section = &parsedSection{Name: strings.TrimSpace(remainder)}
+		rel.Sections = append(rel.Sections, section)
+		return section
+	}
+	if isListEntry(line) {
+		if section == nil {
+			section = &parsedSection{Name: "Other"}
+			rel.Sections = append(rel.Sections, section)
+		}
+		if entry := cleanBulletText(line); entry != "" {
+			section.Entries = append(section.Entries, entry)
+		}
+	}
+	return section
+}
+
+func hasReleaseEntries(rel parsedRelease) bool {
+	for _, section := range rel.Sections {
+		if section != nil && len(section.Entries) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Syncer) findChangelogFile(moduleName string) (*database.ModuleFile, error) {
 	candidates := []string{
 		"CHANGELOG.md",
@@ -156,23 +268,7 @@ func parseChangelogReleases(content string) []parsedRelease {
 			continue
 		}
 
-		if remainder, ok := strings.CutPrefix(line, "### "); ok {
-			sectionName := strings.TrimSpace(remainder)
-			currentSection = &parsedSection{Name: sectionName}
-			current.Sections = append(current.Sections, currentSection)
-			continue
-		}
-
-		if isListEntry(line) {
-			if currentSection == nil {
-				currentSection = &parsedSection{Name: "Other"}
-				current.Sections = append(current.Sections, currentSection)
-			}
-			entry := cleanBulletText(line)
-			if entry != "" {
-				currentSection.Entries = append(currentSection.Entries, entry)
-			}
-		}
+		currentSection = parseReleaseLine(current, currentSection, line)
 	}
 
 	if current != nil && len(releases) < maxReleaseHistory {
@@ -411,3 +507,36 @@ func makeNullString(value string) sql.NullString {
 	}
 	return sql.NullString{String: value, Valid: true}
 }
+
+// Contributors returns the unique commit authors of a compare range, sorted.
+// Commits without a linked GitHub account fall back to the git author name;
+// bot accounts keep their "[bot]" suffix so callers can tell them apart.
+func (c *GitHubCompareResult) Contributors() []string {
+	seen := make(map[string]struct{})
+	var contributors []string
+	for _, commit := range c.Commits {
+		name := ""
+		if commit.Author != nil && commit.Author.Login != "" {
+			name = commit.Author.Login
+			if strings.EqualFold(commit.Author.Type, "Bot") && !strings.HasSuffix(name, "[bot]") {
+				name += "[bot]"
+			}
+		} else {
+			name = strings.TrimSpace(commit.Commit.Author.Name)
+		}
+		name = strings.ReplaceAll(name, ",", " ")
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		contributors = append(contributors, name)
+	}
+	sort.Slice(contributors, func(i, j int) bool {
+		return strings.ToLower(contributors[i]) < strings.ToLower(contributors[j])
+	})
+	return contributors
+}
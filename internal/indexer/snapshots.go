@@ -0,0 +1,119 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+)
+
+// maxSnapshotCacheEntries bounds the per-tag parse cache; tag contents never
+// change so entries are only evicted to cap memory.
+const maxSnapshotCacheEntries = 64
+
+// TagSnapshot holds the declarations parsed from a module's own directory at
+// a given git ref.
+type TagSnapshot struct {
+	Tag         string
+	Files       []database.ModuleFile
+	Variables   []database.ModuleVariable
+	Outputs     []database.ModuleOutput
+	Resources   []database.ModuleResource
+	DataSources []database.ModuleDataSource
+}
+
+// SnapshotAtTag downloads the repository archive at tag and parses the
+// Terraform files directly under rootPrefix ("" for the repository root,
+// "modules/<name>/" for submodules). Results are cached per repo, tag and prefix.
+func (s *Syncer) SnapshotAtTag(ctx context.Context, repoFullName, tag, rootPrefix string) (*TagSnapshot, error) {
+	if s.githubClient == nil {
+		return nil, fmt.Errorf("github client is not initialized")
+	}
+	if repoFullName == "" || strings.TrimSpace(tag) == "" {
+		return nil, fmt.Errorf("repository name and tag are required")
+	}
+
+	key := repoFullName + "@" + tag + ":" + rootPrefix
+	s.snapshotMutex.Lock()
+	if cached, ok := s.snapshotCache[key]; ok {
+		s.snapshotMutex.Unlock()
+		return cached, nil
+	}
+	s.snapshotMutex.Unlock()
+
+	archiveURL := fmt.Sprintf("https://api.github.com/repos/%s/tarball/%s", repoFullName, url.PathEscape(tag))
+	data, err := s.githubClient.getArchive(ctx, archiveURL)
+	if err != nil {
+		return nil, err
+	}
+
+	tarReader, err := openTarArchive(data)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &TagSnapshot{Tag: tag}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		if !isRegularFile(header.Typeflag) {
+			continue
+		}
+
+		relativePath := normalizeArchivePath(header.Name)
+		if !strings.HasPrefix(relativePath, rootPrefix) || !strings.HasSuffix(relativePath, ".tf") {
+			continue
+		}
+		if strings.Contains(strings.TrimPrefix(relativePath, rootPrefix), "/") {
+			continue
+		}
+
+		contentBytes, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", relativePath, err)
+		}
+		content := string(contentBytes)
+
+		snapshot.Files = append(snapshot.Files, database.ModuleFile{
+			FileName:  path.Base(relativePath),
+			FilePath:  relativePath,
+			FileType:  getFileType(relativePath),
+			Content:   content,
+			SizeBytes: header.Size,
+		})
+
+		body, err := parseHCLBody(content, relativePath)
+		if err != nil {
+			log.Printf("Warning: failed to parse %s at %s: %v", relativePath, tag, err)
+			continue
+		}
+
+		snapshot.Variables = append(snapshot.Variables, extractVariables(body, content)...)
+		snapshot.Outputs = append(snapshot.Outputs, extractOutputs(body, content)...)
+		snapshot.Resources = append(snapshot.Resources, extractResources(body, relativePath)...)
+		snapshot.DataSources = append(snapshot.DataSources, extractDataSources(body, relativePath)...)
+	}
+
+	s.snapshotMutex.Lock()
+	if len(s.snapshotCache) >= maxSnapshotCacheEntries {
+		for k := range s.snapshotCache {
+			delete(s.snapshotCache, k)
+			break
+		}
+	}
+	s.snapshotCache[key] = snapshot
+	s.snapshotMutex.Unlock()
+
+	return snapshot, nil
+}
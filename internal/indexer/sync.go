@@ -5,6 +5,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -14,12 +15,16 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/schema"
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/util"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
@@ -29,23 +34,57 @@ import (
 )
 
 type Syncer struct {
-	db           *database.DB
-	githubClient *GitHubClient
-	org          string
-	workerCount  int
-}
-
-const defaultWorkerCount = 4
+	db              *database.DB
+	githubClient    *GitHubClient
+	org             string
+	workerCount     int
+	snapshotCache   map[string]*TagSnapshot
+	snapshotMutex   *sync.Mutex
+	localRoot       string
+	maxFileSize     int64
+	repoPrefix      string
+	includeArchived bool
+}
+
+// DefaultRepoPrefix is the naming convention of CloudNation module
+// repositories on GitHub.
+const DefaultRepoPrefix = "terraform-azure-"
+
+const (
+	defaultWorkerCount = 4
+	defaultMaxFileSize = 1 << 20
+	// maxSourceFileSize caps Terraform, markdown, YAML and JSON files, which
+	// stay eligible above the configured limit.
+	maxSourceFileSize = 8 << 20
+	// binarySniffLength is how much of a file is checked for NUL bytes.
+	binarySniffLength = 8000
+	// topicTagWeight ranks repository topics above any derived tag.
+	topicTagWeight = 10
+)
 
 type GitHubRepo struct {
-	Name        string `json:"name"`
-	FullName    string `json:"full_name"`
-	Description string `json:"description"`
-	UpdatedAt   string `json:"updated_at"`
-	HTMLURL     string `json:"html_url"`
-	Private     bool   `json:"private"`
-	Archived    bool   `json:"archived"`
-	Size        int    `json:"size"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Description   string `json:"description"`
+	UpdatedAt     string `json:"updated_at"`
+	HTMLURL       string `json:"html_url"`
+	Private       bool   `json:"private"`
+	Archived      bool   `json:"archived"`
+	Size          int    `json:"size"`
+	DefaultBranch string `json:"default_branch"`
+
+	// Topics are the repository topics, returned inline by the repos API.
+	Topics []string `json:"topics"`
+
+	// HeadSHA is the default branch head, resolved during sync.
+	HeadSHA string `json:"-"`
+}
+
+type GitHubBranch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
 }
 
 type GitHubContent struct {
@@ -65,14 +104,38 @@ type GitHubTag struct {
 	} `json:"commit"`
 }
 
+type GitHubRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	PublishedAt string `json:"published_at"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+}
+
 type GitHubCompareResult struct {
-	Files []GitHubCompareFile `json:"files"`
+	Files   []GitHubCompareFile   `json:"files"`
+	Commits []GitHubCompareCommit `json:"commits"`
+}
+
+type GitHubCompareCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Author struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+		Type  string `json:"type"`
+	} `json:"author"`
 }
 
 type GitHubCompareFile struct {
-	Filename string `json:"filename"`
-	Status   string `json:"status"`
-	Patch    string `json:"patch"`
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+	Status           string `json:"status"`
+	Patch            string `json:"patch"`
 }
 
 type GitHubClient struct {
@@ -90,9 +153,16 @@ type paginatedResponse struct {
 
 type CacheEntry struct {
 	Data      any
+	ETag      string // sent as If-None-Match once the entry has expired
 	ExpiresAt time.Time
 }
 
+// cacheTTL is how long a cached response is served without revalidation.
+const cacheTTL = 10 * time.Minute
+
+// errNotModified reports a 304 answer to a conditional request.
+var errNotModified = errors.New("not modified")
+
 type RateLimiter struct {
 	tokens    int
 	maxTokens int
@@ -109,8 +179,37 @@ type SyncProgress struct {
 	UpdatedRepos   []string
 }
 
+// ProgressFunc receives a snapshot of a running sync after each repository
+// has been processed.
+type ProgressFunc func(SyncProgress)
+
+type progressKey struct{}
+
+// WithProgress returns a context that makes SyncAll and SyncUpdates report
+// their progress to fn.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func progressFuncFrom(ctx context.Context) ProgressFunc {
+	fn, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return fn
+}
+
 var ErrRepoContentUnavailable = errors.New("repository content unavailable")
 
+// ErrRateLimited is returned to interactive calls once the GitHub rate limit
+// is exhausted; syncs wait for the reset instead.
+var ErrRateLimited = errors.New("GitHub rate limit exhausted")
+
+type rateLimitWaitKey struct{}
+
+// waitForRateLimit marks ctx as belonging to a sync, whose requests wait out
+// an exhausted rate limit rather than fail.
+func waitForRateLimit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rateLimitWaitKey{}, true)
+}
+
 func NewSyncer(db *database.DB, token string, org string) *Syncer {
 	client := &GitHubClient{
 		httpClient: &http.Client{Timeout: 30 * time.Second},
@@ -125,13 +224,78 @@ func NewSyncer(db *database.DB, token string, org string) *Syncer {
 	}
 
 	return &Syncer{
-		db:           db,
-		githubClient: client,
-		org:          org,
-		workerCount:  defaultWorkerCount,
+		db:            db,
+		githubClient:  client,
+		org:           org,
+		workerCount:   defaultWorkerCount,
+		snapshotCache: make(map[string]*TagSnapshot),
+		snapshotMutex: &sync.Mutex{},
+		maxFileSize:   defaultMaxFileSize,
+		repoPrefix:    DefaultRepoPrefix,
 	}
 }
 
+// SetIncludeArchived makes syncs index archived repositories too. Their
+// modules are flagged as archived.
+func (s *Syncer) SetIncludeArchived(include bool) {
+	s.includeArchived = include
+}
+
+// SetRepoPrefix sets the name prefix a repository needs to be synced. An
+// empty prefix matches every name; private repositories are still skipped,
+// and archived ones unless SetIncludeArchived is set.
+func (s *Syncer) SetRepoPrefix(prefix string) {
+	s.repoPrefix = prefix
+}
+
+// inTx runs fn with a copy of the syncer whose database handle is bound to a
+// single transaction, so the many inserts of one module commit together and
+// a failure rolls back only that module's writes. fn must not do network
+// I/O, as other workers wait for the write lock meanwhile.
+func (s *Syncer) inTx(fn func(tx *Syncer) error) error {
+	return s.db.WithTx(func(db *database.DB) error {
+		tx := *s
+		tx.db = db
+		return fn(&tx)
+	})
+}
+
+// SetMaxFileSize sets the largest file, in bytes, stored during a sync.
+// Terraform, markdown, YAML and JSON files are kept up to a fixed ceiling
+// regardless. Values below one fall back to the default.
+func (s *Syncer) SetMaxFileSize(n int64) {
+	if n < 1 {
+		n = defaultMaxFileSize
+	}
+	s.maxFileSize = n
+}
+
+// recordSyncRun stores the outcome of a sync for the sync_status history.
+func (s *Syncer) recordSyncRun(syncType string, startedAt time.Time, progress *SyncProgress) {
+	run := &database.SyncRun{
+		SyncType:       syncType,
+		StartedAt:      startedAt,
+		CompletedAt:    time.Now(),
+		TotalRepos:     progress.TotalRepos,
+		ProcessedRepos: progress.ProcessedRepos,
+		SkippedRepos:   progress.SkippedRepos,
+		UpdatedRepos:   len(progress.UpdatedRepos),
+		ErrorCount:     len(progress.Errors),
+	}
+	if err := s.db.InsertSyncRun(run); err != nil {
+		log.Printf("Warning: failed to record sync run: %v", err)
+	}
+}
+
+// SetWorkerCount sets how many repositories are synced concurrently. Values
+// below one fall back to the default.
+func (s *Syncer) SetWorkerCount(n int) {
+	if n < 1 {
+		n = defaultWorkerCount
+	}
+	s.workerCount = n
+}
+
 func (s *Syncer) workerCountFor(total int) int {
 	if total <= 1 {
 		if total < 1 {
@@ -160,7 +324,7 @@ func (s *Syncer) workerCountFor(total int) int {
 	return count
 }
 
-func (s *Syncer) CompareTags(repoFullName, baseTag, headTag string) (*GitHubCompareResult, error) {
+func (s *Syncer) CompareTags(ctx context.Context, repoFullName, baseTag, headTag string) (*GitHubCompareResult, error) {
 	if s.githubClient == nil {
 		return nil, fmt.Errorf("github client is not initialized")
 	}
@@ -172,40 +336,93 @@ func (s *Syncer) CompareTags(repoFullName, baseTag, headTag string) (*GitHubComp
 	if base == "" || head == "" {
 		return nil, fmt.Errorf("base and head tags are required")
 	}
-	return s.githubClient.compare(repoFullName, base, head)
+	return s.githubClient.compare(ctx, repoFullName, base, head)
 }
 
-func (s *Syncer) SyncAll() (*SyncProgress, error) {
+// FileExistsAt reports whether a repository-relative file exists at the
+// given tag or commit.
+func (s *Syncer) FileExistsAt(ctx context.Context, repoFullName, path, ref string) (bool, error) {
+	if s.githubClient == nil {
+		return false, fmt.Errorf("github client is not initialized")
+	}
+	if repoFullName == "" {
+		return false, fmt.Errorf("repository name is required")
+	}
+	return s.githubClient.fileExists(ctx, repoFullName, path, ref)
+}
+
+// ListTags returns up to limit git tags of a repository in the order GitHub
+// reports them, and whether further tags may exist beyond the limit.
+func (s *Syncer) ListTags(ctx context.Context, repoFullName string, limit int) ([]GitHubTag, bool, error) {
+	if s.githubClient == nil {
+		return nil, false, fmt.Errorf("github client is not initialized")
+	}
+	if repoFullName == "" {
+		return nil, false, fmt.Errorf("repository name is required")
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	pages := (limit + 99) / 100
+	tags, err := s.githubClient.listTags(ctx, repoFullName, pages)
+	if err != nil {
+		return nil, false, err
+	}
+	truncated := len(tags) >= pages*100
+	if len(tags) > limit {
+		tags = tags[:limit]
+		truncated = true
+	}
+	return tags, truncated, nil
+}
+
+// SyncAll indexes every module repository of the organization. Cancelling
+// ctx stops the sync before the next repository; modules indexed so far are
+// kept and the partial progress is returned along with the context error.
+func (s *Syncer) SyncAll(ctx context.Context) (*SyncProgress, error) {
+	ctx = waitForRateLimit(ctx)
 	progress := &SyncProgress{}
+	defer s.recordSyncRun("full", time.Now(), progress)
 
 	log.Println("Fetching repositories from GitHub...")
-	repos, err := s.fetchRepositories()
+	repos, err := s.fetchRepositories(ctx)
 	if err != nil {
+		progress.Errors = append(progress.Errors, err.Error())
 		return nil, fmt.Errorf("failed to fetch repositories: %w", err)
 	}
 
 	progress.TotalRepos = len(repos)
 	log.Printf("Found %d repositories", len(repos))
 
-	s.processRepoQueue(repos, progress, nil)
+	s.processRepoQueue(ctx, repos, progress, nil)
 
 	if err := s.db.ReclaimFreePages(); err != nil {
 		log.Printf("Warning: failed to reclaim free pages: %v", err)
 	}
 
+	if err := syncCancelled(ctx, progress); err != nil {
+		return progress, err
+	}
+
 	log.Printf("Sync completed: %d/%d repositories synced successfully",
 		progress.ProcessedRepos-len(progress.Errors), progress.TotalRepos)
 
 	return progress, nil
 }
 
-func (s *Syncer) SyncUpdates() (*SyncProgress, error) {
+// SyncUpdates re-indexes only the repositories that changed since the last
+// sync. Cancellation behaves as in SyncAll.
+func (s *Syncer) SyncUpdates(ctx context.Context) (*SyncProgress, error) {
+	ctx = waitForRateLimit(ctx)
 	progress := &SyncProgress{}
+	defer s.recordSyncRun("incremental", time.Now(), progress)
 
 	s.githubClient.clearCache()
 	log.Println("Fetching repositories from GitHub (cache cleared)...")
-	repos, err := s.fetchRepositories()
+	repos, err := s.fetchRepositories(ctx)
 	if err != nil {
+		progress.Errors = append(progress.Errors, err.Error())
 		return nil, fmt.Errorf("failed to fetch repositories: %w", err)
 	}
 
@@ -215,6 +432,9 @@ func (s *Syncer) SyncUpdates() (*SyncProgress, error) {
 	reposToSync := make([]GitHubRepo, 0, len(repos))
 
 	for _, repo := range repos {
+		if ctx.Err() != nil {
+			break
+		}
 		progress.CurrentRepo = repo.Name
 
 		existingModule, err := s.db.GetModule(repo.Name)
@@ -230,6 +450,21 @@ func (s *Syncer) SyncUpdates() (*SyncProgress, error) {
 			continue
 		}
 
+		// updated_at also moves on stars and description edits, so the head
+		// commit decides whenever both sides know it.
+		repo.HeadSHA = s.headCommitSHA(ctx, repo)
+		if existingModule.CommitSHA != "" && repo.HeadSHA != "" {
+			if existingModule.CommitSHA == repo.HeadSHA {
+				log.Printf("Skipping %s (already at %s)", repo.Name, shortSHA(repo.HeadSHA))
+				progress.SkippedRepos++
+				progress.ProcessedRepos++
+				continue
+			}
+			log.Printf("Module %s needs update: DB=%s vs GitHub=%s", repo.Name, shortSHA(existingModule.CommitSHA), shortSHA(repo.HeadSHA))
+			reposToSync = append(reposToSync, repo)
+			continue
+		}
+
 		if existingModule.LastUpdated == repo.UpdatedAt {
 			log.Printf("Skipping %s (already up-to-date)", repo.Name)
 			progress.SkippedRepos++
@@ -245,12 +480,16 @@ func (s *Syncer) SyncUpdates() (*SyncProgress, error) {
 		p.UpdatedRepos = append(p.UpdatedRepos, repo.Name)
 	}
 
-	s.processRepoQueue(reposToSync, progress, onSuccess)
+	s.processRepoQueue(ctx, reposToSync, progress, onSuccess)
 
 	if err := s.db.ReclaimFreePages(); err != nil {
 		log.Printf("Warning: failed to reclaim free pages: %v", err)
 	}
 
+	if err := syncCancelled(ctx, progress); err != nil {
+		return progress, err
+	}
+
 	syncedCount := len(progress.UpdatedRepos)
 
 	log.Printf("Sync completed: %d/%d repositories synced, %d skipped (up-to-date), %d errors",
@@ -259,7 +498,19 @@ func (s *Syncer) SyncUpdates() (*SyncProgress, error) {
 	return progress, nil
 }
 
-func (s *Syncer) processRepoQueue(repos []GitHubRepo, progress *SyncProgress, onSuccess func(*SyncProgress, GitHubRepo)) {
+// syncCancelled records and returns the context error once a sync was
+// interrupted, or nil when it ran to completion.
+func syncCancelled(ctx context.Context, progress *SyncProgress) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+	log.Printf("Sync cancelled after %d/%d repositories", progress.ProcessedRepos, progress.TotalRepos)
+	progress.Errors = append(progress.Errors, fmt.Sprintf("sync cancelled: %v", err))
+	return fmt.Errorf("sync cancelled: %w", err)
+}
+
+func (s *Syncer) processRepoQueue(ctx context.Context, repos []GitHubRepo, progress *SyncProgress, onSuccess func(*SyncProgress, GitHubRepo)) {
 	if len(repos) == 0 {
 		return
 	}
@@ -268,8 +519,19 @@ func (s *Syncer) processRepoQueue(repos []GitHubRepo, progress *SyncProgress, on
 	var startedCounter atomic.Int64
 	var mu sync.Mutex
 	startOffset := int64(progress.ProcessedRepos)
+	report := progressFuncFrom(ctx)
+
+	// notify runs with mu held so snapshots arrive in order.
+	notify := func() {
+		if report != nil {
+			report(*progress)
+		}
+	}
 
 	handleRepo := func(repo GitHubRepo) {
+		if ctx.Err() != nil {
+			return
+		}
 		seq := startOffset + startedCounter.Add(1)
 		log.Printf("Syncing repository: %s (%d/%d)", repo.Name, seq, progress.TotalRepos)
 
@@ -277,7 +539,11 @@ func (s *Syncer) processRepoQueue(repos []GitHubRepo, progress *SyncProgress, on
 		progress.CurrentRepo = repo.Name
 		mu.Unlock()
 
-		err := s.syncRepository(repo)
+		err := s.syncRepository(ctx, repo)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			log.Printf("Sync of %s interrupted", repo.Name)
+			return
+		}
 		if err != nil {
 			errMsg := fmt.Sprintf("Failed to sync %s: %v", repo.Name, err)
 			log.Println(errMsg)
@@ -285,6 +551,7 @@ func (s *Syncer) processRepoQueue(repos []GitHubRepo, progress *SyncProgress, on
 			progress.Errors = append(progress.Errors, errMsg)
 			progress.ProcessedRepos++
 			progress.CurrentRepo = repo.Name
+			notify()
 			mu.Unlock()
 			return
 		}
@@ -295,11 +562,15 @@ func (s *Syncer) processRepoQueue(repos []GitHubRepo, progress *SyncProgress, on
 		if onSuccess != nil {
 			onSuccess(progress, repo)
 		}
+		notify()
 		mu.Unlock()
 	}
 
 	if workerCount <= 1 {
 		for _, repo := range repos {
+			if ctx.Err() != nil {
+				break
+			}
 			handleRepo(repo)
 		}
 		return
@@ -316,20 +587,29 @@ func (s *Syncer) processRepoQueue(repos []GitHubRepo, progress *SyncProgress, on
 		})
 	}
 
+dispatch:
 	for _, repo := range repos {
-		jobs <- repo
+		select {
+		case jobs <- repo:
+		case <-ctx.Done():
+			break dispatch
+		}
 	}
 
 	close(jobs)
 	wg.Wait()
 }
 
-func (s *Syncer) fetchRepositories() ([]GitHubRepo, error) {
+func (s *Syncer) fetchRepositories(ctx context.Context) ([]GitHubRepo, error) {
+	if s.isLocal() {
+		return s.fetchLocalRepositories()
+	}
+
 	url := fmt.Sprintf("https://api.github.com/orgs/%s/repos?per_page=100", s.org)
 
 	var allRepos []GitHubRepo
 	for url != "" {
-		data, nextURL, err := s.githubClient.getWithPagination(url)
+		data, nextURL, err := s.githubClient.getWithPagination(ctx, url)
 		if err != nil {
 			return nil, err
 		}
@@ -345,7 +625,7 @@ func (s *Syncer) fetchRepositories() ([]GitHubRepo, error) {
 
 	var terraformRepos []GitHubRepo
 	for _, repo := range allRepos {
-		if !strings.HasPrefix(repo.Name, "terraform-azure-") {
+		if !strings.HasPrefix(repo.Name, s.repoPrefix) {
 			continue
 		}
 
@@ -354,7 +634,7 @@ func (s *Syncer) fetchRepositories() ([]GitHubRepo, error) {
 			continue
 		}
 
-		if repo.Archived {
+		if repo.Archived && !s.includeArchived {
 			log.Printf("Skipping %s (archived repository)", repo.Name)
 			continue
 		}
@@ -370,42 +650,78 @@ func (s *Syncer) fetchRepositories() ([]GitHubRepo, error) {
 	return terraformRepos, nil
 }
 
-func (s *Syncer) syncRepository(repo GitHubRepo) error {
-	moduleID, err := s.insertModuleMetadata(repo)
-	if err != nil {
+func (s *Syncer) syncRepository(ctx context.Context, repo GitHubRepo) error {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 
-	if err := s.clearExistingModuleData(moduleID, repo.Name); err != nil {
-		log.Printf("Warning: failed to clear old data for %s: %v", repo.Name, err)
+	// Resolve the head before fetching content so a push during the sync
+	// leaves an older SHA behind and triggers another sync next time.
+	if repo.HeadSHA == "" {
+		repo.HeadSHA = s.headCommitSHA(ctx, repo)
 	}
 
-	if err := s.syncReadme(moduleID, repo); err != nil {
+	readme, err := s.fetchModuleReadme(ctx, repo)
+	if err != nil {
 		log.Printf("Warning: failed to fetch README for %s: %v", repo.Name, err)
 	}
 
-	hasExamples, submoduleIDs, err := s.syncRepositoryContent(moduleID, repo)
+	archive, err := s.fetchRepositoryArchive(ctx, repo)
 	if err != nil {
 		if errors.Is(err, ErrRepoContentUnavailable) {
-			return s.handleUnavailableRepo(moduleID, repo.Name)
+			return s.handleUnavailableRepo(repo.Name)
 		}
 		return fmt.Errorf("failed to sync files: %w", err)
 	}
-
-	if err := s.parseModulesAndSubmodules(moduleID, submoduleIDs, repo.Name); err != nil {
-		log.Printf("Warning: failed to parse terraform files: %v", err)
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	if hasExamples {
-		s.markModuleHasExamples(moduleID)
+	// The module record, the removal of its old data and the new files and
+	// parse results commit together, so a failed or interrupted sync keeps
+	// the previous index, including last_updated, and the next incremental
+	// sync retries the module.
+	var (
+		moduleID     int64
+		submoduleIDs []int64
+	)
+	err = s.inTx(func(tx *Syncer) error {
+		var err error
+		moduleID, err = tx.insertModuleMetadata(repo, readme)
+		if err != nil {
+			return err
+		}
+		if err := tx.clearExistingModuleData(moduleID, repo.Name); err != nil {
+			return fmt.Errorf("failed to clear old data: %w", err)
+		}
+
+		var hasExamples bool
+		hasExamples, submoduleIDs, err = tx.indexRepositoryContent(moduleID, repo, archive)
+		if err != nil {
+			return fmt.Errorf("failed to sync files: %w", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := tx.parseModulesAndSubmodules(moduleID, submoduleIDs, repo.Name); err != nil {
+			log.Printf("Warning: failed to parse terraform files: %v", err)
+		}
+		if hasExamples {
+			tx.markModuleHasExamples(moduleID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Persist tags for root and submodules to enable related-module queries and ranking.
-	if err := s.persistModuleTags(moduleID); err != nil {
+	if err := s.persistModuleTags(moduleID, repo.Topics); err != nil {
 		log.Printf("Warning: failed to persist tags for %s: %v", repo.Name, err)
 	}
 	for _, childID := range submoduleIDs {
-		if err := s.persistModuleTags(childID); err != nil {
+		if err := s.persistModuleTags(childID, nil); err != nil {
 			log.Printf("Warning: failed to persist tags for submodule %d of %s: %v", childID, repo.Name, err)
 		}
 	}
@@ -420,20 +736,58 @@ func (s *Syncer) syncRepository(repo GitHubRepo) error {
 		}
 	}
 
-	if err := s.captureModuleReleaseMetadata(moduleID, repo); err != nil {
+	if err := s.captureModuleReleaseMetadata(ctx, moduleID, repo); err != nil {
 		log.Printf("Warning: failed to ingest release metadata for %s: %v", repo.Name, err)
 	}
 
+	if repo.HeadSHA != "" {
+		if err := s.db.SetModuleCommitSHA(moduleID, repo.HeadSHA); err != nil {
+			log.Printf("Warning: failed to store commit SHA for %s: %v", repo.Name, err)
+		}
+	}
+
 	return nil
 }
 
-func (s *Syncer) insertModuleMetadata(repo GitHubRepo) (int64, error) {
+// headCommitSHA returns the head commit of the repository's default branch,
+// or "" when it is unknown. Local checkouts are compared by timestamp only.
+func (s *Syncer) headCommitSHA(ctx context.Context, repo GitHubRepo) string {
+	if s.isLocal() || repo.DefaultBranch == "" {
+		return ""
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/branches/%s", repo.FullName, repo.DefaultBranch)
+	data, err := s.githubClient.get(ctx, url)
+	if err != nil {
+		log.Printf("Warning: failed to fetch head commit for %s: %v", repo.Name, err)
+		return ""
+	}
+
+	var branch GitHubBranch
+	if err := json.Unmarshal(data, &branch); err != nil {
+		log.Printf("Warning: failed to decode branch %s of %s: %v", repo.DefaultBranch, repo.Name, err)
+		return ""
+	}
+	return branch.Commit.SHA
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func (s *Syncer) insertModuleMetadata(repo GitHubRepo, readme string) (int64, error) {
 	module := &database.Module{
-		Name:        repo.Name,
-		FullName:    repo.FullName,
-		Description: repo.Description,
-		RepoURL:     repo.HTMLURL,
-		LastUpdated: repo.UpdatedAt,
+		Name:          repo.Name,
+		FullName:      repo.FullName,
+		Description:   repo.Description,
+		RepoURL:       repo.HTMLURL,
+		LastUpdated:   repo.UpdatedAt,
+		ReadmeContent: readme,
+		Org:           s.moduleOrg(repo),
+		Archived:      repo.Archived,
 	}
 
 	moduleID, err := s.db.InsertModule(module)
@@ -455,33 +809,48 @@ func (s *Syncer) clearExistingModuleData(moduleID int64, repoName string) error
 	return s.db.DeleteChildModules(repoName)
 }
 
-func (s *Syncer) syncReadme(moduleID int64, repo GitHubRepo) error {
-	readme, err := s.fetchReadme(repo.FullName)
-	if err != nil {
-		return err
+func (s *Syncer) fetchModuleReadme(ctx context.Context, repo GitHubRepo) (string, error) {
+	if s.isLocal() {
+		return s.fetchLocalReadme(repo.Name)
 	}
+	return s.fetchReadme(ctx, repo.FullName)
+}
 
-	module := &database.Module{
-		ID:            moduleID,
-		Name:          repo.Name,
-		FullName:      repo.FullName,
-		Description:   repo.Description,
-		RepoURL:       repo.HTMLURL,
-		LastUpdated:   repo.UpdatedAt,
-		ReadmeContent: readme,
+// fetchRepositoryArchive downloads the repository tarball. Local sources are
+// read while indexing, so only the checkout's presence is checked.
+func (s *Syncer) fetchRepositoryArchive(ctx context.Context, repo GitHubRepo) ([]byte, error) {
+	if s.isLocal() {
+		return nil, s.checkLocalRepository(repo.Name)
 	}
-
-	_, err = s.db.InsertModule(module)
-	return err
+	archiveURL := fmt.Sprintf("https://api.github.com/repos/%s/tarball", repo.FullName)
+	return s.githubClient.getArchive(ctx, archiveURL)
 }
 
-func (s *Syncer) syncRepositoryContent(moduleID int64, repo GitHubRepo) (bool, []int64, error) {
-	return s.syncRepositoryFromArchive(moduleID, repo)
+// indexRepositoryContent stores the files of a repository from its archive
+// or local checkout, routing files under modules/ to submodule records.
+func (s *Syncer) indexRepositoryContent(moduleID int64, repo GitHubRepo, archive []byte) (bool, []int64, error) {
+	if s.isLocal() {
+		return s.indexLocalRepository(moduleID, repo)
+	}
+	tarReader, err := openTarArchive(archive)
+	if err != nil {
+		return false, nil, err
+	}
+	return s.processArchiveEntries(tarReader, moduleID, repo)
 }
 
-func (s *Syncer) handleUnavailableRepo(moduleID int64, repoName string) error {
+// handleUnavailableRepo drops a previously indexed module whose content can
+// no longer be fetched.
+func (s *Syncer) handleUnavailableRepo(repoName string) error {
 	log.Printf("Skipping %s: repository content unavailable", repoName)
-	if delErr := s.db.DeleteModuleByID(moduleID); delErr != nil {
+	module, err := s.db.GetModule(repoName)
+	if err != nil {
+		return nil
+	}
+	if err := s.db.DeleteChildModules(repoName); err != nil {
+		log.Printf("Warning: failed to delete submodules of %s: %v", repoName, err)
+	}
+	if delErr := s.db.DeleteModuleByID(module.ID); delErr != nil {
 		log.Printf("Warning: failed to delete module record for %s: %v", repoName, delErr)
 	}
 	return nil
@@ -507,7 +876,10 @@ func (s *Syncer) markModuleHasExamples(moduleID int64) {
 	}
 }
 
-func (s *Syncer) persistModuleTags(moduleID int64) error {
+// persistModuleTags replaces the module's tags with ones derived from its
+// resource types and name, plus the repository topics, which take precedence
+// over a derived tag of the same name.
+func (s *Syncer) persistModuleTags(moduleID int64, topics []string) error {
 	module, err := s.db.GetModuleByID(moduleID)
 	if err != nil {
 		return err
@@ -532,7 +904,7 @@ func (s *Syncer) persistModuleTags(moduleID int64) error {
 		}
 	}
 
-	name := module.Name
+	name := strings.TrimPrefix(module.Name, s.repoPrefix)
 	name = strings.ReplaceAll(name, "terraform-", "")
 	name = strings.ReplaceAll(name, "azure-", "")
 	name = strings.ReplaceAll(name, "//", "-")
@@ -558,6 +930,11 @@ func (s *Syncer) persistModuleTags(moduleID int64) error {
 			log.Printf("Warning: failed inserting tag %s for %s: %v", tag, module.Name, err)
 		}
 	}
+	for _, topic := range topics {
+		if err := s.db.InsertModuleTag(moduleID, topic, topicTagWeight, database.TagSourceTopic); err != nil {
+			log.Printf("Warning: failed inserting topic %s for %s: %v", topic, module.Name, err)
+		}
+	}
 	return nil
 }
 
@@ -569,7 +946,7 @@ func (s *Syncer) persistModuleAliases(moduleID int64) error {
 	tags, _ := s.db.GetModuleTags(moduleID)
 
 	name := module.Name
-	name = strings.TrimPrefix(name, "terraform-azure-")
+	name = strings.TrimPrefix(name, s.repoPrefix)
 	name = strings.TrimPrefix(name, "terraform-")
 	name = strings.TrimPrefix(name, "azure-")
 	name = strings.ReplaceAll(name, "//modules/", "-")
@@ -640,24 +1017,6 @@ func (s *Syncer) persistModuleAliases(moduleID int64) error {
 	return nil
 }
 
-func (s *Syncer) syncRepositoryFromArchive(moduleID int64, repo GitHubRepo) (bool, []int64, error) {
-	archiveURL := fmt.Sprintf("https://api.github.com/repos/%s/tarball", repo.FullName)
-	data, err := s.githubClient.getArchive(archiveURL)
-	if err != nil {
-		if errors.Is(err, ErrRepoContentUnavailable) {
-			return false, nil, ErrRepoContentUnavailable
-		}
-		return false, nil, err
-	}
-
-	tarReader, err := openTarArchive(data)
-	if err != nil {
-		return false, nil, err
-	}
-
-	return s.processArchiveEntries(tarReader, moduleID, repo)
-}
-
 func openTarArchive(data []byte) (*tar.Reader, error) {
 	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
 	if err != nil {
@@ -689,10 +1048,19 @@ func (s *Syncer) processArchiveEntries(tarReader *tar.Reader, moduleID int64, re
 			continue
 		}
 
+		if reason := s.oversizeReason(relativePath, header.Size); reason != "" {
+			log.Printf("Skipping %s/%s (%s)", repo.Name, relativePath, reason)
+			continue
+		}
+
 		contentBytes, err := io.ReadAll(tarReader)
 		if err != nil {
 			return false, nil, fmt.Errorf("failed to read file %s: %w", relativePath, err)
 		}
+		if isBinaryContent(contentBytes) {
+			log.Printf("Skipping %s/%s (binary content)", repo.Name, relativePath)
+			continue
+		}
 
 		targetModuleID, _ := s.resolveTargetModule(moduleID, relativePath, repo, submoduleIDs, &submoduleOrder)
 
@@ -748,6 +1116,36 @@ func (s *Syncer) insertModuleFile(moduleID int64, relativePath string, size int6
 	return s.db.InsertFile(file)
 }
 
+// oversizeReason explains why a file of the given size is too large to
+// store, or returns "" when it is eligible.
+func (s *Syncer) oversizeReason(relativePath string, size int64) string {
+	limit := s.maxFileSize
+	if limit <= 0 {
+		limit = defaultMaxFileSize
+	}
+	if isSourceFile(relativePath) {
+		limit = max(limit, maxSourceFileSize)
+	}
+	if size > limit {
+		return fmt.Sprintf("%d bytes exceeds the %d byte limit", size, limit)
+	}
+	return ""
+}
+
+func isSourceFile(relativePath string) bool {
+	switch strings.ToLower(path.Ext(relativePath)) {
+	case ".tf", ".md", ".yml", ".yaml", ".json":
+		return true
+	}
+	return false
+}
+
+// isBinaryContent reports whether content looks binary, using the presence
+// of a NUL byte near the start of the file.
+func isBinaryContent(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), binarySniffLength)], 0) >= 0
+}
+
 func normalizeArchivePath(name string) string {
 	parts := strings.SplitN(name, "/", 2)
 	if len(parts) < 2 {
@@ -779,6 +1177,16 @@ func shouldSkipPath(relativePath string) bool {
 	return false
 }
 
+// moduleOrg records the configured org as module provenance, falling back to
+// the repository owner when no org is configured.
+func (s *Syncer) moduleOrg(repo GitHubRepo) string {
+	if s.org != "" {
+		return s.org
+	}
+	owner, _, _ := strings.Cut(repo.FullName, "/")
+	return owner
+}
+
 func (s *Syncer) ensureSubmoduleModule(repo GitHubRepo, subKey string) (int64, error) {
 	submoduleName := fmt.Sprintf("%s//modules/%s", repo.Name, subKey)
 	module := &database.Module{
@@ -787,6 +1195,8 @@ func (s *Syncer) ensureSubmoduleModule(repo GitHubRepo, subKey string) (int64, e
 		Description: fmt.Sprintf("Submodule %s of %s", subKey, repo.Name),
 		RepoURL:     repo.HTMLURL,
 		LastUpdated: repo.UpdatedAt,
+		Org:         s.moduleOrg(repo),
+		Archived:    repo.Archived,
 	}
 
 	moduleID, err := s.db.InsertModule(module)
@@ -805,9 +1215,9 @@ func isRegularFile(typeFlag byte) bool {
 	return typeFlag == tar.TypeReg
 }
 
-func (s *Syncer) fetchReadme(repoFullName string) (string, error) {
+func (s *Syncer) fetchReadme(ctx context.Context, repoFullName string) (string, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/readme", repoFullName)
-	data, err := s.githubClient.get(url)
+	data, err := s.githubClient.get(ctx, url)
 	if err != nil {
 		return "", err
 	}
@@ -817,12 +1227,12 @@ func (s *Syncer) fetchReadme(repoFullName string) (string, error) {
 		return "", err
 	}
 
-	return s.fetchFileContent(content)
+	return s.fetchFileContent(ctx, content)
 }
 
-func (s *Syncer) fetchFileContent(content GitHubContent) (string, error) {
+func (s *Syncer) fetchFileContent(ctx context.Context, content GitHubContent) (string, error) {
 	if content.DownloadURL != "" {
-		data, err := s.githubClient.get(content.DownloadURL)
+		data, err := s.githubClient.get(ctx, content.DownloadURL)
 		if err != nil {
 			return "", err
 		}
@@ -846,16 +1256,30 @@ func (s *Syncer) parseAndIndexTerraformFiles(moduleID int64) error {
 		return err
 	}
 
+	terraformFiles := 0
 	for _, file := range files {
 		if file.FileType != "terraform" {
 			continue
 		}
+		terraformFiles++
 
 		if err := s.parseAndIndexTerraformFile(moduleID, file); err != nil {
 			log.Printf("Warning: failed to parse %s: %v", file.FilePath, err)
+			if err := s.db.InsertParseError(&database.ModuleParseError{ModuleID: moduleID, FilePath: file.FilePath, Message: err.Error()}); err != nil {
+				log.Printf("Warning: failed to record parse error for %s: %v", file.FilePath, err)
+			}
 		}
 	}
 
+	if terraformFiles == 0 {
+		log.Printf("Module %d has no Terraform files to index", moduleID)
+	}
+	if err := s.db.SetModuleNoTerraform(moduleID, terraformFiles == 0); err != nil {
+		log.Printf("Warning: failed to flag module %d Terraform presence: %v", moduleID, err)
+	}
+
+	s.indexPrimaryProvider(moduleID)
+
 	return nil
 }
 
@@ -865,38 +1289,45 @@ func (s *Syncer) parseAndIndexTerraformFile(moduleID int64, file database.Module
 		return err
 	}
 
-	s.indexVariables(moduleID, body, file.Content)
-	s.indexOutputs(moduleID, body, file.Content)
-	s.indexResources(moduleID, body, file.FileName)
+	s.indexVariables(moduleID, body, file.Content, file.FilePath)
+	s.indexOutputs(moduleID, body, file.Content, file.FilePath)
+	s.indexResources(moduleID, body, file.FilePath)
 	s.indexDataSources(moduleID, body, file.FileName)
+	s.indexStateMigrations(moduleID, body, file.Content, file.FilePath)
+	s.indexLocals(moduleID, body, file.Content, file.FilePath)
+	s.indexDependencies(moduleID, body, file.Content, file.FilePath)
+	s.indexTerraformVersions(moduleID, body, file.Content, file.FilePath)
+	s.indexRequiredProviders(moduleID, body, file.FilePath)
 	s.indexHCLBlocks(moduleID, file.FilePath, body)
 	s.indexRelationships(moduleID, file.FilePath, body)
 
 	return nil
 }
 
-func (s *Syncer) indexVariables(moduleID int64, body *hclsyntax.Body, content string) {
+func (s *Syncer) indexVariables(moduleID int64, body *hclsyntax.Body, content, filePath string) {
 	variables := extractVariables(body, content)
 	for _, v := range variables {
 		v.ModuleID = moduleID
+		v.SourceFile = filePath
 		if err := s.db.InsertVariable(&v); err != nil {
 			log.Printf("Warning: failed to insert variable: %v", err)
 		}
 	}
 }
 
-func (s *Syncer) indexOutputs(moduleID int64, body *hclsyntax.Body, content string) {
+func (s *Syncer) indexOutputs(moduleID int64, body *hclsyntax.Body, content, filePath string) {
 	outputs := extractOutputs(body, content)
 	for _, o := range outputs {
 		o.ModuleID = moduleID
+		o.SourceFile = filePath
 		if err := s.db.InsertOutput(&o); err != nil {
 			log.Printf("Warning: failed to insert output: %v", err)
 		}
 	}
 }
 
-func (s *Syncer) indexResources(moduleID int64, body *hclsyntax.Body, fileName string) {
-	resources := extractResources(body, fileName)
+func (s *Syncer) indexResources(moduleID int64, body *hclsyntax.Body, filePath string) {
+	resources := extractResources(body, filePath)
 	for _, r := range resources {
 		r.ModuleID = moduleID
 		if err := s.db.InsertResource(&r); err != nil {
@@ -915,6 +1346,130 @@ func (s *Syncer) indexDataSources(moduleID int64, body *hclsyntax.Body, fileName
 	}
 }
 
+func (s *Syncer) indexStateMigrations(moduleID int64, body *hclsyntax.Body, content, filePath string) {
+	// Examples refactor their own root configuration, not the module's state.
+	if strings.HasPrefix(filePath, "examples/") {
+		return
+	}
+	migrations := extractStateMigrations(body, content, filePath)
+	for _, m := range migrations {
+		m.ModuleID = moduleID
+		if err := s.db.InsertStateMigration(&m); err != nil {
+			log.Printf("Warning: failed to insert state migration: %v", err)
+		}
+	}
+}
+
+func (s *Syncer) indexLocals(moduleID int64, body *hclsyntax.Body, content, filePath string) {
+	// Example locals belong to the example's root configuration.
+	if strings.HasPrefix(filePath, "examples/") {
+		return
+	}
+	locals := extractLocals(body, content, filePath)
+	for _, l := range locals {
+		l.ModuleID = moduleID
+		if err := s.db.InsertLocal(&l); err != nil {
+			log.Printf("Warning: failed to insert local: %v", err)
+		}
+	}
+}
+
+func (s *Syncer) indexDependencies(moduleID int64, body *hclsyntax.Body, content, filePath string) {
+	// Examples call the module itself; they are not part of its composition.
+	if strings.HasPrefix(filePath, "examples/") {
+		return
+	}
+	dependencies := extractModuleCalls(body, content, filePath)
+	for _, d := range dependencies {
+		d.ModuleID = moduleID
+		if err := s.db.InsertDependency(&d); err != nil {
+			log.Printf("Warning: failed to insert module dependency: %v", err)
+		}
+	}
+}
+
+func (s *Syncer) indexTerraformVersions(moduleID int64, body *hclsyntax.Body, content, filePath string) {
+	versions := extractTerraformVersions(body, content, filePath)
+	for _, v := range versions {
+		v.ModuleID = moduleID
+		if err := s.db.InsertTerraformVersion(&v); err != nil {
+			log.Printf("Warning: failed to insert terraform version: %v", err)
+		}
+	}
+}
+
+func (s *Syncer) indexRequiredProviders(moduleID int64, body *hclsyntax.Body, filePath string) {
+	// Examples declare providers for their own root configuration.
+	if strings.HasPrefix(filePath, "examples/") {
+		return
+	}
+	providers := extractRequiredProviders(body, filePath)
+	for _, p := range providers {
+		p.ModuleID = moduleID
+		if err := s.db.InsertProvider(&p); err != nil {
+			log.Printf("Warning: failed to insert provider: %v", err)
+		}
+	}
+}
+
+// indexPrimaryProvider completes the provider set with providers only seen on
+// resources and marks the one backing the most resources as primary.
+func (s *Syncer) indexPrimaryProvider(moduleID int64) {
+	resources, err := s.db.GetModuleResources(moduleID)
+	if err != nil {
+		log.Printf("Warning: failed to load resources for provider detection: %v", err)
+		return
+	}
+
+	// Examples often pull in helper providers (random, tls) the module
+	// itself never uses, so only the module's own resources count.
+	counts := make(map[string]int)
+	for _, r := range resources {
+		if r.Provider != "" && !strings.HasPrefix(r.SourceFile, "examples/") {
+			counts[r.Provider]++
+		}
+	}
+
+	for name := range counts {
+		if err := s.db.InsertProvider(&database.ModuleProvider{ModuleID: moduleID, Name: name}); err != nil {
+			log.Printf("Warning: failed to insert provider: %v", err)
+		}
+	}
+
+	providers, err := s.db.GetModuleProviders(moduleID)
+	if err != nil {
+		log.Printf("Warning: failed to load providers: %v", err)
+		return
+	}
+
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, p.Name)
+	}
+	if primary := primaryProvider(names, counts); primary != "" {
+		if err := s.db.SetPrimaryProvider(moduleID, primary); err != nil {
+			log.Printf("Warning: failed to set primary provider: %v", err)
+		}
+	}
+}
+
+// primaryProvider picks the provider with the most resources, breaking ties
+// by name so the result never depends on map iteration order.
+func primaryProvider(names []string, resourceCounts map[string]int) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+
+	primary := ""
+	best := -1
+	for _, name := range sorted {
+		if resourceCounts[name] > best {
+			primary = name
+			best = resourceCounts[name]
+		}
+	}
+	return primary
+}
+
 func parseHCLBody(content string, filename string) (*hclsyntax.Body, error) {
 	parser := hclparse.NewParser()
 	file, diags := parser.ParseHCL([]byte(content), filename)
@@ -941,10 +1496,13 @@ func extractVariables(body *hclsyntax.Body, content string) []database.ModuleVar
 		variable := database.ModuleVariable{
 			Name:     block.Labels[0],
 			Required: true,
+			Nullable: true,
+			Block:    expressionText(content, block.Range()),
 		}
 
 		if attr, ok := block.Body.Attributes["type"]; ok {
 			variable.Type = strings.TrimSpace(expressionText(content, attr.Expr.Range()))
+			variable.OptionalAttributes = optionalAttributes(variable.Type)
 		}
 
 		if attr, ok := block.Body.Attributes["description"]; ok {
@@ -962,12 +1520,51 @@ func extractVariables(body *hclsyntax.Body, content string) []database.ModuleVar
 			variable.Sensitive = attributeIsTrue(attr, content)
 		}
 
+		if attr, ok := block.Body.Attributes["nullable"]; ok {
+			variable.Nullable = !attributeIsFalse(attr, content)
+		}
+
+		for _, nested := range block.Body.Blocks {
+			if nested.Type != "validation" {
+				continue
+			}
+			var rule database.VariableValidation
+			if attr, ok := nested.Body.Attributes["condition"]; ok {
+				rule.Condition = strings.TrimSpace(expressionText(content, attr.Expr.Range()))
+			}
+			if attr, ok := nested.Body.Attributes["error_message"]; ok {
+				rule.ErrorMessage = attributeString(attr, content)
+			}
+			variable.Validations = append(variable.Validations, rule)
+		}
+
 		variables = append(variables, variable)
 	}
 
 	return variables
 }
 
+// optionalAttributes lists the optional(...) attributes of an object type
+// constraint. Unparseable constraints yield none.
+func optionalAttributes(typeExpr string) []database.OptionalAttribute {
+	parsed, err := schema.Parse(typeExpr)
+	if err != nil {
+		return nil
+	}
+	var attrs []database.OptionalAttribute
+	for _, field := range parsed.Flatten("") {
+		if !field.Optional {
+			continue
+		}
+		attrs = append(attrs, database.OptionalAttribute{
+			Path:    field.Path,
+			Type:    field.Type,
+			Default: strings.Join(strings.Fields(field.Default), " "),
+		})
+	}
+	return attrs
+}
+
 func extractOutputs(body *hclsyntax.Body, content string) []database.ModuleOutput {
 	var outputs []database.ModuleOutput
 
@@ -981,9 +1578,11 @@ func extractOutputs(body *hclsyntax.Body, content string) []database.ModuleOutpu
 		}
 
 		if attr, ok := block.Body.Attributes["description"]; ok {
-			if literal, ok := attr.Expr.(*hclsyntax.LiteralValueExpr); ok && literal.Val.Type() == cty.String {
-				output.Description = literal.Val.AsString()
-			}
+			output.Description = attributeString(attr, content)
+		}
+
+		if attr, ok := block.Body.Attributes["value"]; ok {
+			output.Value = strings.TrimSpace(expressionText(content, attr.Expr.Range()))
 		}
 
 		if attr, ok := block.Body.Attributes["sensitive"]; ok {
@@ -996,7 +1595,7 @@ func extractOutputs(body *hclsyntax.Body, content string) []database.ModuleOutpu
 	return outputs
 }
 
-func extractResources(body *hclsyntax.Body, fileName string) []database.ModuleResource {
+func extractResources(body *hclsyntax.Body, filePath string) []database.ModuleResource {
 	var resources []database.ModuleResource
 
 	for _, block := range body.Blocks {
@@ -1009,7 +1608,8 @@ func extractResources(body *hclsyntax.Body, fileName string) []database.ModuleRe
 			ResourceType: resourceType,
 			ResourceName: block.Labels[1],
 			Provider:     providerFromType(resourceType),
-			SourceFile:   fileName,
+			SourceFile:   filePath,
+			Attributes:   extractResourceAttributes(block.Body),
 		}
 
 		resources = append(resources, resource)
@@ -1018,6 +1618,43 @@ func extractResources(body *hclsyntax.Body, fileName string) []database.ModuleRe
 	return resources
 }
 
+// extractResourceAttributes lists the arguments and nested block types set
+// directly in a resource body, in source order. Repeated blocks are recorded
+// once; dynamic blocks are recorded under the block type they generate.
+func extractResourceAttributes(body *hclsyntax.Body) []database.ResourceAttribute {
+	type positioned struct {
+		attr  database.ResourceAttribute
+		start int
+	}
+	var items []positioned
+
+	for name, attr := range body.Attributes {
+		items = append(items, positioned{database.ResourceAttribute{Name: name, Kind: "attribute"}, attr.SrcRange.Start.Byte})
+	}
+
+	seen := make(map[string]struct{})
+	for _, block := range body.Blocks {
+		attr := database.ResourceAttribute{Name: block.Type, Kind: "block"}
+		if block.Type == "dynamic" && len(block.Labels) > 0 {
+			attr = database.ResourceAttribute{Name: block.Labels[0], Kind: "dynamic"}
+		}
+		key := attr.Kind + ":" + attr.Name
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		items = append(items, positioned{attr, block.TypeRange.Start.Byte})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].start < items[j].start })
+
+	attributes := make([]database.ResourceAttribute, 0, len(items))
+	for _, item := range items {
+		attributes = append(attributes, item.attr)
+	}
+	return attributes
+}
+
 func extractDataSources(body *hclsyntax.Body, fileName string) []database.ModuleDataSource {
 	var dataSources []database.ModuleDataSource
 
@@ -1040,6 +1677,220 @@ func extractDataSources(body *hclsyntax.Body, fileName string) []database.Module
 	return dataSources
 }
 
+// extractStateMigrations collects moved, removed and import blocks, which tell
+// callers how existing state is carried across a refactor.
+func extractStateMigrations(body *hclsyntax.Body, content, filePath string) []database.ModuleStateMigration {
+	var migrations []database.ModuleStateMigration
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "moved", "removed", "import":
+		default:
+			continue
+		}
+
+		migrations = append(migrations, database.ModuleStateMigration{
+			BlockType:   block.Type,
+			FromAddress: attributeText(block.Body, "from", content),
+			ToAddress:   attributeText(block.Body, "to", content),
+			ImportID:    attributeText(block.Body, "id", content),
+			FilePath:    filePath,
+			Line:        block.DefRange().Start.Line,
+		})
+	}
+
+	return migrations
+}
+
+// extractLocals returns every value of the file's locals blocks in source
+// order, keeping the raw expression since locals are rarely literals.
+func extractLocals(body *hclsyntax.Body, content, filePath string) []database.ModuleLocal {
+	var locals []database.ModuleLocal
+
+	for _, block := range body.Blocks {
+		if block.Type != "locals" {
+			continue
+		}
+
+		attrs := make([]*hclsyntax.Attribute, 0, len(block.Body.Attributes))
+		for _, attr := range block.Body.Attributes {
+			attrs = append(attrs, attr)
+		}
+		sort.Slice(attrs, func(i, j int) bool {
+			return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+		})
+
+		for _, attr := range attrs {
+			locals = append(locals, database.ModuleLocal{
+				Name:       attr.Name,
+				Expression: strings.TrimSpace(expressionText(content, attr.Expr.Range())),
+				FilePath:   filePath,
+				Line:       attr.SrcRange.Start.Line,
+			})
+		}
+	}
+
+	return locals
+}
+
+func extractModuleCalls(body *hclsyntax.Body, content, filePath string) []database.ModuleDependency {
+	var dependencies []database.ModuleDependency
+
+	for _, block := range body.Blocks {
+		if block.Type != "module" || len(block.Labels) == 0 {
+			continue
+		}
+		attr, ok := block.Body.Attributes["source"]
+		if !ok {
+			continue
+		}
+
+		source := attributeString(attr, content)
+		dependency := database.ModuleDependency{
+			Name:       block.Labels[0],
+			Source:     source,
+			SourceKind: moduleSourceKind(source),
+			FilePath:   filePath,
+		}
+		if version, ok := block.Body.Attributes["version"]; ok {
+			dependency.Version = attributeString(version, content)
+		}
+
+		dependencies = append(dependencies, dependency)
+	}
+
+	return dependencies
+}
+
+// registrySourcePattern matches <namespace>/<name>/<provider> with an optional
+// registry host and //subdir, as accepted by Terraform.
+var registrySourcePattern = regexp.MustCompile(`^(?:[a-zA-Z0-9.-]+\.[a-z]+/)?[a-zA-Z0-9][a-zA-Z0-9_-]*/[a-zA-Z0-9][a-zA-Z0-9_-]*/[a-zA-Z0-9]+(?://.*)?$`)
+
+// moduleSourceKind classifies a module source address the way Terraform's
+// installer would pick a getter for it.
+func moduleSourceKind(source string) string {
+	switch {
+	case strings.HasPrefix(source, "./"), strings.HasPrefix(source, "../"):
+		return "local"
+	case strings.HasPrefix(source, "git::"),
+		strings.HasPrefix(source, "git@"),
+		strings.HasPrefix(source, "github.com/"),
+		strings.HasPrefix(source, "bitbucket.org/"):
+		return "git"
+	case registrySourcePattern.MatchString(source):
+		return "registry"
+	}
+	return "other"
+}
+
+func attributeText(body *hclsyntax.Body, name, content string) string {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return ""
+	}
+	return strings.Join(strings.Fields(expressionText(content, attr.Expr.Range())), " ")
+}
+
+// extractRequiredProviders reads required_providers entries in declaration
+// order, accepting both the object form and the legacy version-string form.
+func extractRequiredProviders(body *hclsyntax.Body, filePath string) []database.ModuleProvider {
+	var providers []database.ModuleProvider
+
+	for _, block := range body.Blocks {
+		if block.Type != "terraform" {
+			continue
+		}
+		for _, inner := range block.Body.Blocks {
+			if inner.Type != "required_providers" {
+				continue
+			}
+			attrs := make([]*hclsyntax.Attribute, 0, len(inner.Body.Attributes))
+			for _, attr := range inner.Body.Attributes {
+				attrs = append(attrs, attr)
+			}
+			sort.Slice(attrs, func(i, j int) bool {
+				return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+			})
+
+			for _, attr := range attrs {
+				provider := database.ModuleProvider{
+					Name:       attr.Name,
+					SourceFile: filePath,
+				}
+				if val, diags := attr.Expr.Value(nil); !diags.HasErrors() && val.IsKnown() && !val.IsNull() {
+					switch {
+					case val.Type() == cty.String:
+						// Legacy shorthand: name = "<version constraint>".
+						provider.Version = val.AsString()
+					case val.Type().IsObjectType():
+						provider.Source = objectStringAttr(val, "source")
+						provider.Version = objectStringAttr(val, "version")
+					}
+				}
+				providers = append(providers, provider)
+			}
+		}
+	}
+
+	return providers
+}
+
+func objectStringAttr(val cty.Value, name string) string {
+	if !val.Type().HasAttribute(name) {
+		return ""
+	}
+	attr := val.GetAttr(name)
+	if !attr.IsKnown() || attr.IsNull() || attr.Type() != cty.String {
+		return ""
+	}
+	return attr.AsString()
+}
+
+func extractTerraformVersions(body *hclsyntax.Body, content, filePath string) []database.ModuleTerraformVersion {
+	var versions []database.ModuleTerraformVersion
+
+	for _, block := range body.Blocks {
+		if block.Type != "terraform" {
+			continue
+		}
+
+		attr, ok := block.Body.Attributes["required_version"]
+		if !ok {
+			continue
+		}
+
+		constraint := attributeString(attr, content)
+		if constraint == "" {
+			continue
+		}
+
+		versions = append(versions, database.ModuleTerraformVersion{
+			RequiredVersion: constraint,
+			SourceFile:      filePath,
+		})
+	}
+
+	return versions
+}
+
+// attributeString evaluates static string attributes (quoted strings are
+// parsed as templates, not literals) and falls back to the raw expression text.
+func attributeString(attr *hclsyntax.Attribute, content string) string {
+	if val, diags := attr.Expr.Value(nil); !diags.HasErrors() && val.IsKnown() && !val.IsNull() && val.Type() == cty.String {
+		return strings.TrimSpace(val.AsString())
+	}
+	return strings.Trim(strings.TrimSpace(expressionText(content, attr.Expr.Range())), `"`)
+}
+
+func attributeIsFalse(attr *hclsyntax.Attribute, content string) bool {
+	if literal, ok := attr.Expr.(*hclsyntax.LiteralValueExpr); ok && literal.Val.Type() == cty.Bool {
+		return literal.Val.False()
+	}
+
+	text := strings.TrimSpace(expressionText(content, attr.Expr.Range()))
+	return strings.EqualFold(text, "false")
+}
+
 func attributeIsTrue(attr *hclsyntax.Attribute, content string) bool {
 	if literal, ok := attr.Expr.(*hclsyntax.LiteralValueExpr); ok && literal.Val.Type() == cty.Bool {
 		return literal.Val.True()
@@ -1054,19 +1905,22 @@ func (s *Syncer) indexHCLBlocks(moduleID int64, filePath string, body *hclsyntax
 	walk = func(b *hclsyntax.Body) {
 		for _, bl := range b.Blocks {
 			blockType := bl.Type
-			if blockType == "resource" || blockType == "dynamic" || blockType == "lifecycle" {
-				typeLabel := ""
-				if blockType == "resource" && len(bl.Labels) >= 2 {
-					typeLabel = bl.Labels[0]
-				} else if blockType == "dynamic" && len(bl.Labels) >= 1 {
+			if isIndexedBlockType(blockType) {
+				typeLabel, nameLabel := "", ""
+				switch {
+				case (blockType == "resource" || blockType == "data") && len(bl.Labels) >= 2:
+					typeLabel, nameLabel = bl.Labels[0], bl.Labels[1]
+				case blockType == "dynamic" && len(bl.Labels) >= 1:
 					typeLabel = bl.Labels[0]
+				case (blockType == "variable" || blockType == "output") && len(bl.Labels) >= 1:
+					nameLabel = bl.Labels[0]
 				}
 				rng := bl.Range()
 				start := int(rng.Start.Byte)
 				end := int(rng.End.Byte)
 				paths := collectAttrPaths(bl.Body, "")
 				attrPaths := strings.Join(paths, "\n")
-				_, err := s.db.InsertHCLBlock(moduleID, filePath, blockType, typeLabel, start, end, attrPaths)
+				_, err := s.db.InsertHCLBlock(moduleID, filePath, blockType, typeLabel, nameLabel, start, end, attrPaths)
 				if err != nil {
 					log.Printf("Warning: failed to insert hcl block %s in %s: %v", blockType, filePath, err)
 				}
@@ -1079,6 +1933,14 @@ func (s *Syncer) indexHCLBlocks(moduleID int64, filePath string, body *hclsyntax
 	walk(body)
 }
 
+func isIndexedBlockType(blockType string) bool {
+	switch blockType {
+	case "resource", "data", "variable", "output", "locals", "dynamic", "lifecycle":
+		return true
+	}
+	return false
+}
+
 func collectAttrPaths(b *hclsyntax.Body, prefix string) []string {
 	var out []string
 	for k := range b.Attributes {
@@ -1273,86 +2135,174 @@ func getFileType(fileName string) string {
 	return "other"
 }
 
-func (rl *RateLimiter) acquire() bool {
+// acquire takes a request token. When the budget is exhausted, syncs wait
+// until the limit resets, returning ctx's error if ctx ends first; other
+// callers get ErrRateLimited with the reset time right away.
+func (rl *RateLimiter) acquire(ctx context.Context) error {
+	for {
+		rl.mutex.Lock()
+		now := time.Now()
+		if now.After(rl.refillAt) {
+			rl.tokens = rl.maxTokens
+			rl.refillAt = now.Add(time.Hour)
+		}
+		if rl.tokens > 0 {
+			rl.tokens--
+			rl.mutex.Unlock()
+			return nil
+		}
+		reset := rl.refillAt
+		rl.mutex.Unlock()
+
+		if syncing, _ := ctx.Value(rateLimitWaitKey{}).(bool); !syncing {
+			return fmt.Errorf("%w; rate limited until %s", ErrRateLimited, reset.UTC().Format(time.RFC3339))
+		}
+		wait := time.Until(reset)
+		log.Printf("GitHub rate limit exhausted; waiting %s until reset", wait.Round(time.Second))
+		timer := time.NewTimer(wait + time.Second)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// update replaces the local estimate with the budget GitHub reports in the
+// X-RateLimit-* response headers. Responses without them are ignored.
+func (rl *RateLimiter) update(headers http.Header) {
+	remaining, err := strconv.Atoi(headers.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(headers.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+
 	rl.mutex.Lock()
 	defer rl.mutex.Unlock()
+	rl.tokens = remaining
+	rl.refillAt = time.Unix(reset, 0)
+	if limit, err := strconv.Atoi(headers.Get("X-RateLimit-Limit")); err == nil && limit > 0 {
+		rl.maxTokens = limit
+	}
+}
 
-	if time.Now().After(rl.refillAt) {
-		rl.tokens = rl.maxTokens
-		rl.refillAt = time.Now().Add(time.Hour)
+// rateLimited reports whether GitHub rejected a request because the primary
+// rate limit is exhausted, as opposed to a permission error.
+func rateLimited(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
 	}
+	return resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
 
-	if rl.tokens > 0 {
-		rl.tokens--
-		return true
+// send issues an authenticated GET request, keeping the rate limiter in
+// step with GitHub. A non-empty etag makes the request conditional. A request
+// rejected for an exhausted limit is retried once after the reset.
+func (gc *GitHubClient) send(ctx context.Context, url, etag string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := gc.rateLimit.acquire(ctx); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if gc.token != "" {
+			req.Header.Set("Authorization", "token "+gc.token)
+		}
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		req.Header.Set("User-Agent", "az-cn-wam-mcp/1.0.0")
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		resp, err := gc.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		gc.rateLimit.update(resp.Header)
+
+		if attempt == 0 && rateLimited(resp) {
+			resp.Body.Close()
+			continue
+		}
+		return resp, nil
 	}
-	return false
 }
 
+// clearCache expires all cached responses. Paginated listings keep their body
+// and ETag so the next fetch can be a conditional request, which GitHub
+// answers with 304 without charging the rate limit.
 func (gc *GitHubClient) clearCache() {
 	gc.cacheMutex.Lock()
-	gc.cache = make(map[string]CacheEntry)
+	kept := make(map[string]CacheEntry)
+	for url, entry := range gc.cache {
+		if _, ok := entry.Data.(paginatedResponse); ok && entry.ETag != "" {
+			entry.ExpiresAt = time.Time{}
+			kept[url] = entry
+		}
+	}
+	gc.cache = kept
 	gc.cacheMutex.Unlock()
 }
 
-func (gc *GitHubClient) get(url string) ([]byte, error) {
+// cacheLookup returns the cached entry for url and whether it is still fresh.
+func (gc *GitHubClient) cacheLookup(url string) (CacheEntry, bool, bool) {
 	gc.cacheMutex.RLock()
-	if entry, exists := gc.cache[url]; exists && time.Now().Before(entry.ExpiresAt) {
-		gc.cacheMutex.RUnlock()
-		if data, ok := entry.Data.([]byte); ok {
-			return data, nil
-		}
-	}
-	gc.cacheMutex.RUnlock()
+	defer gc.cacheMutex.RUnlock()
+	entry, exists := gc.cache[url]
+	return entry, exists, exists && time.Now().Before(entry.ExpiresAt)
+}
 
-	if !gc.rateLimit.acquire() {
-		return nil, fmt.Errorf("rate limit exceeded")
+func (gc *GitHubClient) storeCache(url string, data any, etag string) {
+	gc.cacheMutex.Lock()
+	gc.cache[url] = CacheEntry{
+		Data:      data,
+		ETag:      etag,
+		ExpiresAt: time.Now().Add(cacheTTL),
 	}
+	gc.cacheMutex.Unlock()
+}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+func (gc *GitHubClient) get(ctx context.Context, url string) ([]byte, error) {
+	entry, exists, fresh := gc.cacheLookup(url)
+	cached, isBytes := entry.Data.([]byte)
+	if fresh && isBytes {
+		return cached, nil
 	}
 
-	if gc.token != "" {
-		req.Header.Set("Authorization", "token "+gc.token)
+	etag := ""
+	if exists && isBytes {
+		etag = entry.ETag
 	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "az-cn-wam-mcp/1.0.0")
 
-	resp, err := gc.httpClient.Do(req)
-	if err != nil {
-		return nil, err
+	data, headers, err := gc.doRequest(ctx, url, etag)
+	if errors.Is(err, errNotModified) {
+		gc.storeCache(url, cached, etag)
+		return cached, nil
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
-	}
-
-	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	gc.cacheMutex.Lock()
-	gc.cache[url] = CacheEntry{
-		Data:      data,
-		ExpiresAt: time.Now().Add(10 * time.Minute),
-	}
-	gc.cacheMutex.Unlock()
-
+	gc.storeCache(url, data, headers.Get("ETag"))
 	return data, nil
 }
 
-func (gc *GitHubClient) listTags(repoFullName string, maxPages int) ([]GitHubTag, error) {
+func (gc *GitHubClient) listTags(ctx context.Context, repoFullName string, maxPages int) ([]GitHubTag, error) {
 	if maxPages <= 0 {
 		maxPages = 1
 	}
 	var tags []GitHubTag
 	for page := 1; page <= maxPages; page++ {
 		endpoint := fmt.Sprintf("https://api.github.com/repos/%s/tags?per_page=100&page=%d", repoFullName, page)
-		data, err := gc.get(endpoint)
+		data, err := gc.get(ctx, endpoint)
 		if err != nil {
 			return nil, err
 		}
@@ -1368,14 +2318,27 @@ func (gc *GitHubClient) listTags(repoFullName string, maxPages int) ([]GitHubTag
 	return tags, nil
 }
 
-func (gc *GitHubClient) compare(repoFullName, base, head string) (*GitHubCompareResult, error) {
+func (gc *GitHubClient) listReleases(ctx context.Context, repoFullName string, limit int) ([]GitHubRelease, error) {
+	endpoint := fmt.Sprintf("https://api.github.com/repos/%s/releases?per_page=%d", repoFullName, limit)
+	data, err := gc.get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var releases []GitHubRelease
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+func (gc *GitHubClient) compare(ctx context.Context, repoFullName, base, head string) (*GitHubCompareResult, error) {
 	compareURL := fmt.Sprintf(
 		"https://api.github.com/repos/%s/compare/%s...%s",
 		repoFullName,
 		url.PathEscape(base),
 		url.PathEscape(head),
 	)
-	data, err := gc.get(compareURL)
+	data, err := gc.get(ctx, compareURL)
 	if err != nil {
 		return nil, err
 	}
@@ -1386,23 +2349,36 @@ func (gc *GitHubClient) compare(repoFullName, base, head string) (*GitHubCompare
 	return &result, nil
 }
 
-func (gc *GitHubClient) getArchive(url string) ([]byte, error) {
-	if !gc.rateLimit.acquire() {
-		return nil, fmt.Errorf("rate limit exceeded")
+// fileExists reports whether path exists in the repository at ref.
+func (gc *GitHubClient) fileExists(ctx context.Context, repoFullName, path, ref string) (bool, error) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
 	}
-
-	req, err := http.NewRequest("GET", url, nil)
+	contentsURL := fmt.Sprintf(
+		"https://api.github.com/repos/%s/contents/%s?ref=%s",
+		repoFullName,
+		strings.Join(segments, "/"),
+		url.QueryEscape(ref),
+	)
+	resp, err := gc.send(ctx, contentsURL, "")
 	if err != nil {
-		return nil, err
+		return false, err
 	}
+	defer resp.Body.Close()
 
-	if gc.token != "" {
-		req.Header.Set("Authorization", "token "+gc.token)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
 	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "az-cn-wam-mcp/1.0.0")
+}
 
-	resp, err := gc.httpClient.Do(req)
+func (gc *GitHubClient) getArchive(ctx context.Context, url string) ([]byte, error) {
+	resp, err := gc.send(ctx, url, "")
 	if err != nil {
 		return nil, err
 	}
@@ -1419,55 +2395,46 @@ func (gc *GitHubClient) getArchive(url string) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
-func (gc *GitHubClient) getWithPagination(url string) ([]byte, string, error) {
-	gc.cacheMutex.RLock()
-	if entry, exists := gc.cache[url]; exists && time.Now().Before(entry.ExpiresAt) {
-		gc.cacheMutex.RUnlock()
-		if cached, ok := entry.Data.(paginatedResponse); ok {
-			return cached.data, cached.nextURL, nil
-		}
+func (gc *GitHubClient) getWithPagination(ctx context.Context, url string) ([]byte, string, error) {
+	entry, exists, fresh := gc.cacheLookup(url)
+	cached, isPage := entry.Data.(paginatedResponse)
+	if fresh && isPage {
+		return cached.data, cached.nextURL, nil
+	}
+
+	etag := ""
+	if exists && isPage {
+		etag = entry.ETag
 	}
-	gc.cacheMutex.RUnlock()
 
-	data, headers, err := gc.doRequest(url)
+	data, headers, err := gc.doRequest(ctx, url, etag)
+	if errors.Is(err, errNotModified) {
+		gc.storeCache(url, cached, etag)
+		return cached.data, cached.nextURL, nil
+	}
 	if err != nil {
 		return nil, "", err
 	}
 
 	nextURL := parseNextLink(headers.Get("Link"))
-
-	gc.cacheMutex.Lock()
-	gc.cache[url] = CacheEntry{
-		Data:      paginatedResponse{data: data, nextURL: nextURL},
-		ExpiresAt: time.Now().Add(10 * time.Minute),
-	}
-	gc.cacheMutex.Unlock()
+	gc.storeCache(url, paginatedResponse{data: data, nextURL: nextURL}, headers.Get("ETag"))
 
 	return data, nextURL, nil
 }
 
-func (gc *GitHubClient) doRequest(url string) ([]byte, http.Header, error) {
-	if !gc.rateLimit.acquire() {
-		return nil, nil, fmt.Errorf("rate limit exceeded")
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	if gc.token != "" {
-		req.Header.Set("Authorization", "token "+gc.token)
-	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "az-cn-wam-mcp/1.0.0")
-
-	resp, err := gc.httpClient.Do(req)
+// doRequest fetches url and returns the body and headers of a 200 response.
+// When etag is set the request is conditional and an unchanged resource
+// yields errNotModified.
+func (gc *GitHubClient) doRequest(ctx context.Context, url, etag string) ([]byte, http.Header, error) {
+	resp, err := gc.send(ctx, url, etag)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return nil, nil, errNotModified
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, nil, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
 	}
@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// JSONSchema converts the type into a JSON Schema (draft 2020-12) fragment.
+// Sets become arrays of unique items, maps objects with additionalProperties
+// and tuples arrays with prefixItems; any maps to the empty schema.
+func (t *Type) JSONSchema() map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+	switch t.Kind {
+	case "string", "number":
+		return map[string]any{"type": t.Kind}
+	case "bool":
+		return map[string]any{"type": "boolean"}
+	case "list":
+		return map[string]any{"type": "array", "items": t.Elem.JSONSchema()}
+	case "set":
+		return map[string]any{"type": "array", "items": t.Elem.JSONSchema(), "uniqueItems": true}
+	case "map":
+		return map[string]any{"type": "object", "additionalProperties": t.Elem.JSONSchema()}
+	case "tuple":
+		items := make([]any, 0, len(t.Elems))
+		for _, elem := range t.Elems {
+			items = append(items, elem.JSONSchema())
+		}
+		return map[string]any{"type": "array", "prefixItems": items, "minItems": len(items), "maxItems": len(items)}
+	case "object":
+		properties := make(map[string]any, len(t.Fields))
+		required := []string{}
+		for _, field := range t.Fields {
+			prop := field.Type.JSONSchema()
+			if field.Default != "" {
+				if value, ok := LiteralJSON(field.Default); ok {
+					prop["default"] = value
+				}
+			}
+			properties[field.Name] = prop
+			if !field.Optional {
+				required = append(required, field.Name)
+			}
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	}
+	return map[string]any{}
+}
+
+// LiteralJSON converts a constant HCL expression such as a variable default
+// into JSON. It reports false for expressions that reference variables or
+// call functions.
+func LiteralJSON(expr string) (json.RawMessage, bool) {
+	src := strings.TrimSpace(expr)
+	if src == "" {
+		return nil, false
+	}
+	parsed, diags := hclsyntax.ParseExpression([]byte(src), "default.tf", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+	if diags.HasErrors() {
+		return nil, false
+	}
+	value, diags := parsed.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return nil, false
+	}
+	data, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"string", "string", `{"type":"string"}`},
+		{"bool", "bool", `{"type":"boolean"}`},
+		{"any", "any", `{}`},
+		{"empty", "", `{}`},
+		{"list", "list(number)", `{"items":{"type":"number"},"type":"array"}`},
+		{"set", "set(string)", `{"items":{"type":"string"},"type":"array","uniqueItems":true}`},
+		{"map", "map(bool)", `{"additionalProperties":{"type":"boolean"},"type":"object"}`},
+		{
+			"tuple",
+			"tuple([string, number])",
+			`{"maxItems":2,"minItems":2,"prefixItems":[{"type":"string"},{"type":"number"}],"type":"array"}`,
+		},
+		{
+			"object with optional attributes",
+			`object({ name = string, tier = optional(string, "Standard"), tags = optional(map(string)) })`,
+			`{"additionalProperties":false,"properties":{"name":{"type":"string"},"tags":{"additionalProperties":{"type":"string"},"type":"object"},"tier":{"default":"Standard","type":"string"}},"required":["name"],"type":"object"}`,
+		},
+		{
+			"non-literal default is dropped",
+			`object({ location = optional(string, var.location) })`,
+			`{"additionalProperties":false,"properties":{"location":{"type":"string"}},"required":[],"type":"object"}`,
+		},
+		{
+			"nested collections",
+			`map(object({ rules = optional(list(string), []) }))`,
+			`{"additionalProperties":{"additionalProperties":false,"properties":{"rules":{"default":[],"items":{"type":"string"},"type":"array"}},"required":[],"type":"object"},"type":"object"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			got, err := json.Marshal(typ.JSONSchema())
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("JSONSchema(%q)\n got  %s\n want %s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLiteralJSON(t *testing.T) {
+	tests := []struct {
+		expr   string
+		want   string
+		wantOK bool
+	}{
+		{`"westeurope"`, `"westeurope"`, true},
+		{`3`, `3`, true},
+		{`true`, `true`, true},
+		{`null`, `null`, true},
+		{`["a", "b"]`, `["a","b"]`, true},
+		{`{ env = "dev" }`, `{"env":"dev"}`, true},
+		{`var.location`, ``, false},
+		{`lower("A")`, ``, false},
+		{`   `, ``, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := LiteralJSON(tt.expr)
+		if ok != tt.wantOK {
+			t.Errorf("LiteralJSON(%q) ok = %v, want %v", tt.expr, ok, tt.wantOK)
+			continue
+		}
+		if ok && string(got) != tt.want {
+			t.Errorf("LiteralJSON(%q) = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
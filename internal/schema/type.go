@@ -0,0 +1,306 @@
+// Package schema parses Terraform variable type constraints into a type tree
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type Type struct {
+	Kind   string  `json:"kind"`
+	Elem   *Type   `json:"element,omitempty"`
+	Elems  []*Type `json:"elements,omitempty"`
+	Fields []Field `json:"fields,omitempty"`
+}
+
+type Field struct {
+	Name     string `json:"name"`
+	Type     *Type  `json:"type"`
+	Optional bool   `json:"optional,omitempty"`
+	Default  string `json:"default,omitempty"`
+}
+
+// Parse builds a type tree from a type constraint such as
+// map(object({ name = string, tags = optional(map(string), {}) })).
+func Parse(expr string) (*Type, error) {
+	src := strings.TrimSpace(expr)
+	if src == "" {
+		return &Type{Kind: "any"}, nil
+	}
+
+	parsed, diags := hclsyntax.ParseExpression([]byte(src), "type.tf", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid type expression: %s", diags.Error())
+	}
+
+	return parseExpr(parsed, []byte(src))
+}
+
+func parseExpr(expr hclsyntax.Expression, src []byte) (*Type, error) {
+	switch e := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		name := e.Traversal.RootName()
+		switch name {
+		case "string", "number", "bool", "any":
+			return &Type{Kind: name}, nil
+		}
+		return nil, fmt.Errorf("unknown type keyword %q", name)
+
+	case *hclsyntax.FunctionCallExpr:
+		switch e.Name {
+		case "list", "set", "map":
+			if len(e.Args) != 1 {
+				return nil, fmt.Errorf("%s() takes exactly one argument", e.Name)
+			}
+			elem, err := parseExpr(e.Args[0], src)
+			if err != nil {
+				return nil, err
+			}
+			return &Type{Kind: e.Name, Elem: elem}, nil
+
+		case "object":
+			if len(e.Args) != 1 {
+				return nil, fmt.Errorf("object() takes exactly one argument")
+			}
+			cons, ok := e.Args[0].(*hclsyntax.ObjectConsExpr)
+			if !ok {
+				return nil, fmt.Errorf("object() argument must be an attribute map")
+			}
+			return parseObject(cons, src)
+
+		case "tuple":
+			if len(e.Args) != 1 {
+				return nil, fmt.Errorf("tuple() takes exactly one argument")
+			}
+			cons, ok := e.Args[0].(*hclsyntax.TupleConsExpr)
+			if !ok {
+				return nil, fmt.Errorf("tuple() argument must be a list of types")
+			}
+			t := &Type{Kind: "tuple"}
+			for _, item := range cons.Exprs {
+				elem, err := parseExpr(item, src)
+				if err != nil {
+					return nil, err
+				}
+				t.Elems = append(t.Elems, elem)
+			}
+			return t, nil
+		}
+		return nil, fmt.Errorf("unknown type constructor %q", e.Name)
+	}
+
+	return nil, fmt.Errorf("unsupported type expression")
+}
+
+func parseObject(cons *hclsyntax.ObjectConsExpr, src []byte) (*Type, error) {
+	t := &Type{Kind: "object"}
+	for _, item := range cons.Items {
+		name := hcl.ExprAsKeyword(item.KeyExpr)
+		if name == "" {
+			if val, diags := item.KeyExpr.Value(nil); !diags.HasErrors() && val.Type() == cty.String {
+				name = val.AsString()
+			}
+		}
+		if name == "" {
+			return nil, fmt.Errorf("object attribute names must be static")
+		}
+
+		field := Field{Name: name}
+		valueExpr := item.ValueExpr
+		if call, ok := valueExpr.(*hclsyntax.FunctionCallExpr); ok && call.Name == "optional" {
+			if len(call.Args) < 1 || len(call.Args) > 2 {
+				return nil, fmt.Errorf("optional() takes one or two arguments")
+			}
+			field.Optional = true
+			valueExpr = call.Args[0]
+			if len(call.Args) == 2 {
+				field.Default = strings.TrimSpace(string(call.Args[1].Range().SliceBytes(src)))
+			}
+		}
+
+		fieldType, err := parseExpr(valueExpr, src)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		field.Type = fieldType
+		t.Fields = append(t.Fields, field)
+	}
+	return t, nil
+}
+
+func (t *Type) IsPrimitive() bool {
+	switch t.Kind {
+	case "string", "number", "bool", "any":
+		return true
+	}
+	return false
+}
+
+// Depth counts nested collection and structural levels; primitives are 0.
+func (t *Type) Depth() int {
+	if t == nil || t.IsPrimitive() {
+		return 0
+	}
+	deepest := 0
+	for _, child := range t.children() {
+		if d := child.Depth(); d > deepest {
+			deepest = d
+		}
+	}
+	return deepest + 1
+}
+
+// FieldCount counts object attributes at every level of the tree.
+func (t *Type) FieldCount() int {
+	if t == nil {
+		return 0
+	}
+	count := len(t.Fields)
+	for _, child := range t.children() {
+		count += child.FieldCount()
+	}
+	return count
+}
+
+// Complexity scores how hard a type is to configure: every attribute counts
+// once and every level of nesting counts double.
+func (t *Type) Complexity() int {
+	return t.FieldCount() + 2*t.Depth()
+}
+
+// Shape renders a compact description that names at most three attributes
+// per object, e.g. map(object{name, address_prefixes, +2 more}).
+func (t *Type) Shape() string {
+	if t == nil {
+		return "any"
+	}
+	switch t.Kind {
+	case "list", "set", "map":
+		return fmt.Sprintf("%s(%s)", t.Kind, t.Elem.Shape())
+	case "tuple":
+		parts := make([]string, 0, len(t.Elems))
+		for _, elem := range t.Elems {
+			parts = append(parts, elem.Shape())
+		}
+		return fmt.Sprintf("tuple[%s]", strings.Join(parts, ", "))
+	case "object":
+		names := make([]string, 0, 4)
+		for i, field := range t.Fields {
+			if i == 3 {
+				names = append(names, fmt.Sprintf("+%d more", len(t.Fields)-3))
+				break
+			}
+			names = append(names, field.Name)
+		}
+		return fmt.Sprintf("object{%s}", strings.Join(names, ", "))
+	}
+	return t.Kind
+}
+
+func (t *Type) children() []*Type {
+	var out []*Type
+	if t.Elem != nil {
+		out = append(out, t.Elem)
+	}
+	out = append(out, t.Elems...)
+	for _, field := range t.Fields {
+		out = append(out, field.Type)
+	}
+	return out
+}
+
+type FlatField struct {
+	Path     string
+	Type     string
+	Optional bool
+	Default  string
+}
+
+// Flatten lists every object attribute reachable from t as a dotted path.
+// Elements of collections are marked with [*], e.g. subnets[*].name.
+func (t *Type) Flatten(prefix string) []FlatField {
+	if t == nil {
+		return nil
+	}
+	switch t.Kind {
+	case "list", "set", "map":
+		return t.Elem.Flatten(prefix + "[*]")
+	case "tuple":
+		var out []FlatField
+		for i, elem := range t.Elems {
+			out = append(out, elem.Flatten(fmt.Sprintf("%s[%d]", prefix, i))...)
+		}
+		return out
+	case "object":
+		var out []FlatField
+		for _, field := range t.Fields {
+			path := field.Name
+			if prefix != "" {
+				path = prefix + "." + field.Name
+			}
+			out = append(out, FlatField{
+				Path:     path,
+				Type:     field.Type.String(),
+				Optional: field.Optional,
+				Default:  field.Default,
+			})
+			out = append(out, field.Type.Flatten(path)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// String renders the type with object attributes elided, e.g. map(object).
+func (t *Type) String() string {
+	if t == nil {
+		return "any"
+	}
+	switch t.Kind {
+	case "list", "set", "map":
+		return fmt.Sprintf("%s(%s)", t.Kind, t.Elem.String())
+	case "tuple":
+		parts := make([]string, 0, len(t.Elems))
+		for _, elem := range t.Elems {
+			parts = append(parts, elem.String())
+		}
+		return fmt.Sprintf("tuple([%s])", strings.Join(parts, ", "))
+	}
+	return t.Kind
+}
+
+// Expr renders the full type constraint in a canonical single-line form, so
+// constraints that differ only in layout render identically.
+func (t *Type) Expr() string {
+	if t == nil {
+		return "any"
+	}
+	switch t.Kind {
+	case "list", "set", "map":
+		return fmt.Sprintf("%s(%s)", t.Kind, t.Elem.Expr())
+	case "tuple":
+		parts := make([]string, 0, len(t.Elems))
+		for _, elem := range t.Elems {
+			parts = append(parts, elem.Expr())
+		}
+		return fmt.Sprintf("tuple([%s])", strings.Join(parts, ", "))
+	case "object":
+		parts := make([]string, 0, len(t.Fields))
+		for _, field := range t.Fields {
+			value := field.Type.Expr()
+			switch {
+			case field.Optional && field.Default != "":
+				value = fmt.Sprintf("optional(%s, %s)", value, strings.Join(strings.Fields(field.Default), " "))
+			case field.Optional:
+				value = fmt.Sprintf("optional(%s)", value)
+			}
+			parts = append(parts, fmt.Sprintf("%s = %s", field.Name, value))
+		}
+		return fmt.Sprintf("object({ %s })", strings.Join(parts, ", "))
+	}
+	return t.Kind
+}
@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr    string
+		shape   string
+		str     string
+		canon   string
+		wantErr bool
+	}{
+		{expr: "string", shape: "string", str: "string", canon: "string"},
+		{expr: "", shape: "any", str: "any", canon: "any"},
+		{expr: "list(map(number))", shape: "list(map(number))", str: "list(map(number))", canon: "list(map(number))"},
+		{expr: "tuple([string, bool])", shape: "tuple[string, bool]", str: "tuple([string, bool])", canon: "tuple([string, bool])"},
+		{
+			expr:  "object({\n  name = string\n  tier = optional(string, \"Standard\")\n})",
+			shape: "object{name, tier}",
+			str:   "object",
+			canon: `object({ name = string, tier = optional(string, "Standard") })`,
+		},
+		{
+			expr:  `map(object({ a = string, b = number, c = bool, d = optional(any) }))`,
+			shape: "map(object{a, b, c, +1 more})",
+			str:   "map(object)",
+			canon: "map(object({ a = string, b = number, c = bool, d = optional(any) }))",
+		},
+		{expr: `object({ "quoted-key" = string })`, shape: "object{quoted-key}", str: "object", canon: "object({ quoted-key = string })"},
+		{expr: "strng", wantErr: true},
+		{expr: "list(string, number)", wantErr: true},
+		{expr: "foo(string)", wantErr: true},
+		{expr: "object(string)", wantErr: true},
+		{expr: "object({ a = optional() })", wantErr: true},
+		{expr: "object({ a = optional(string, 1, 2) })", wantErr: true},
+		{expr: "list(", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.expr)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %s, want error", tt.expr, got.Expr())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		if s := got.Shape(); s != tt.shape {
+			t.Errorf("Parse(%q).Shape() = %q, want %q", tt.expr, s, tt.shape)
+		}
+		if s := got.String(); s != tt.str {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.expr, s, tt.str)
+		}
+		if s := got.Expr(); s != tt.canon {
+			t.Errorf("Parse(%q).Expr() = %q, want %q", tt.expr, s, tt.canon)
+		}
+	}
+}
+
+func TestParseOptional(t *testing.T) {
+	typ, err := Parse(`object({ name = string, rules = optional(list(string), []), tags = optional(map(string)) })`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Field{
+		{Name: "name", Type: &Type{Kind: "string"}},
+		{Name: "rules", Type: &Type{Kind: "list", Elem: &Type{Kind: "string"}}, Optional: true, Default: "[]"},
+		{Name: "tags", Type: &Type{Kind: "map", Elem: &Type{Kind: "string"}}, Optional: true},
+	}
+	if !reflect.DeepEqual(typ.Fields, want) {
+		t.Errorf("Fields = %+v, want %+v", typ.Fields, want)
+	}
+}
+
+func TestComplexity(t *testing.T) {
+	tests := []struct {
+		expr       string
+		depth      int
+		fields     int
+		complexity int
+	}{
+		{"string", 0, 0, 0},
+		{"list(string)", 1, 0, 2},
+		{"object({ a = string, b = number })", 1, 2, 4},
+		{"map(object({ a = string, b = optional(object({ c = string })) }))", 3, 3, 9},
+	}
+
+	for _, tt := range tests {
+		typ, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		if got := typ.Depth(); got != tt.depth {
+			t.Errorf("Depth(%q) = %d, want %d", tt.expr, got, tt.depth)
+		}
+		if got := typ.FieldCount(); got != tt.fields {
+			t.Errorf("FieldCount(%q) = %d, want %d", tt.expr, got, tt.fields)
+		}
+		if got := typ.Complexity(); got != tt.complexity {
+			t.Errorf("Complexity(%q) = %d, want %d", tt.expr, got, tt.complexity)
+		}
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	typ, err := Parse(`object({ name = string, subnets = optional(map(object({ prefix = string, nsg = optional(string, "default") })), {}) })`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []FlatField{
+		{Path: "name", Type: "string"},
+		{Path: "subnets", Type: "map(object)", Optional: true, Default: "{}"},
+		{Path: "subnets[*].prefix", Type: "string"},
+		{Path: "subnets[*].nsg", Type: "string", Optional: true, Default: `"default"`},
+	}
+	if got := typ.Flatten(""); !reflect.DeepEqual(got, want) {
+		t.Errorf("Flatten = %+v, want %+v", got, want)
+	}
+}
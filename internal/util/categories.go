@@ -0,0 +1,151 @@
+package util
+
+import "strings"
+
+// CategoryOrder is the display order of the high-level Azure categories.
+var CategoryOrder = []string{
+	"networking",
+	"compute",
+	"storage",
+	"data",
+	"identity",
+	"integration",
+	"security",
+	"monitoring",
+	"other",
+}
+
+// resourceCategoryRules is matched in order against the leading whole
+// underscore-separated segments of the resource type with its provider prefix
+// removed, so "lb" matches azurerm_lb_rule but not azurerm_synapse_..., and
+// more specific keywords must come first.
+var resourceCategoryRules = []struct {
+	keyword  string
+	category string
+}{
+	{"network_security_group", "security"},
+	{"network_ddos_protection_plan", "security"},
+	{"web_application_firewall", "security"},
+	{"firewall", "security"},
+	{"key_vault", "security"},
+	{"security_center", "security"},
+	{"sentinel", "security"},
+	{"ddos", "security"},
+	{"user_assigned_identity", "identity"},
+	{"federated_identity", "identity"},
+	{"role_assignment", "identity"},
+	{"role_definition", "identity"},
+	{"pim", "identity"},
+	{"log_analytics", "monitoring"},
+	{"application_insights", "monitoring"},
+	{"monitor", "monitoring"},
+	{"dashboard", "monitoring"},
+	{"portal_dashboard", "monitoring"},
+	{"private_endpoint", "networking"},
+	{"private_dns", "networking"},
+	{"dns", "networking"},
+	{"virtual_network", "networking"},
+	{"virtual_wan", "networking"},
+	{"virtual_hub", "networking"},
+	{"vpn", "networking"},
+	{"express_route", "networking"},
+	{"subnet", "networking"},
+	{"network", "networking"},
+	{"public_ip", "networking"},
+	{"nat_gateway", "networking"},
+	{"route", "networking"},
+	{"application_gateway", "networking"},
+	{"lb", "networking"},
+	{"frontdoor", "networking"},
+	{"cdn", "networking"},
+	{"traffic_manager", "networking"},
+	{"bastion", "networking"},
+	{"local_network_gateway", "networking"},
+	{"servicebus", "integration"},
+	{"eventhub", "integration"},
+	{"eventgrid", "integration"},
+	{"api_management", "integration"},
+	{"logic_app", "integration"},
+	{"relay", "integration"},
+	{"notification_hub", "integration"},
+	{"signalr", "integration"},
+	{"web_pubsub", "integration"},
+	{"communication_service", "integration"},
+	{"storage", "storage"},
+	{"managed_disk", "storage"},
+	{"netapp", "storage"},
+	{"recovery_services", "storage"},
+	{"backup", "storage"},
+	{"data_protection", "storage"},
+	{"mssql", "data"},
+	{"sql", "data"},
+	{"postgresql", "data"},
+	{"mysql", "data"},
+	{"mariadb", "data"},
+	{"cosmosdb", "data"},
+	{"redis", "data"},
+	{"data_factory", "data"},
+	{"synapse", "data"},
+	{"databricks", "data"},
+	{"kusto", "data"},
+	{"search_service", "data"},
+	{"stream_analytics", "data"},
+	{"purview", "data"},
+	{"kubernetes", "compute"},
+	{"virtual_machine", "compute"},
+	{"orchestrated_virtual_machine_scale_set", "compute"},
+	{"linux", "compute"},
+	{"windows", "compute"},
+	{"container", "compute"},
+	{"service_plan", "compute"},
+	{"app_service", "compute"},
+	{"function_app", "compute"},
+	{"web_app", "compute"},
+	{"static_site", "compute"},
+	{"static_web_app", "compute"},
+	{"spring_cloud", "compute"},
+	{"batch", "compute"},
+	{"availability_set", "compute"},
+	{"image", "compute"},
+	{"shared_image", "compute"},
+}
+
+// ResourceCategory maps a resource type (e.g. azurerm_subnet) to one of the
+// high-level categories in CategoryOrder.
+func ResourceCategory(resourceType string) string {
+	rest := strings.ToLower(resourceType)
+	if _, after, ok := strings.Cut(rest, "_"); ok {
+		rest = after
+	}
+	if strings.HasPrefix(strings.ToLower(resourceType), "azuread_") {
+		return "identity"
+	}
+	for _, rule := range resourceCategoryRules {
+		if rest == rule.keyword || strings.HasPrefix(rest, rule.keyword+"_") {
+			return rule.category
+		}
+	}
+	return "other"
+}
+
+// DominantCategory returns the category most resource types fall into,
+// preferring CategoryOrder on ties and ignoring "other" when possible.
+func DominantCategory(resourceTypes []string) string {
+	counts := make(map[string]int)
+	for _, rt := range resourceTypes {
+		counts[ResourceCategory(rt)]++
+	}
+
+	best := "other"
+	bestCount := 0
+	for _, category := range CategoryOrder {
+		if category == "other" {
+			continue
+		}
+		if counts[category] > bestCount {
+			best = category
+			bestCount = counts[category]
+		}
+	}
+	return best
+}
@@ -0,0 +1,35 @@
+package util
+
+import "testing"
+
+func TestResourceCategory(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		want         string
+	}{
+		{"azurerm_lb", "networking"},
+		{"azurerm_lb_rule", "networking"},
+		{"azurerm_subnet", "networking"},
+		{"azurerm_virtual_network_peering", "networking"},
+		{"azurerm_network_security_group", "security"},
+		{"azurerm_web_application_firewall_policy", "security"},
+		{"azurerm_linux_web_app", "compute"},
+		{"azurerm_image", "compute"},
+		{"azurerm_shared_image_version", "compute"},
+		{"azurerm_container_registry_task", "compute"},
+		{"azurerm_role_assignment", "identity"},
+		{"azurerm_synapse_role_assignment", "data"},
+		{"azurerm_kusto_database_principal_assignment", "data"},
+		{"azurerm_monitor_diagnostic_setting", "monitoring"},
+		{"azurerm_storage_account_network_rules", "storage"},
+		{"azuread_group", "identity"},
+		{"azurerm_resource_group", "other"},
+		{"random_password", "other"},
+	}
+
+	for _, tt := range tests {
+		if got := ResourceCategory(tt.resourceType); got != tt.want {
+			t.Errorf("ResourceCategory(%q) = %q, want %q", tt.resourceType, got, tt.want)
+		}
+	}
+}
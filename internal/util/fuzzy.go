@@ -0,0 +1,36 @@
+package util
+
+import "strings"
+
+// NormalizeQuery lowercases a module reference and joins its words with
+// hyphens, so "Terraform Azure AKS" and "terraform_azure_aks" both become
+// terraform-azure-aks.
+func NormalizeQuery(q string) string {
+	fields := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-' || r == '\t'
+	})
+	return strings.Join(fields, "-")
+}
+
+// Levenshtein returns the number of single-character insertions, deletions
+// and substitutions needed to turn a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
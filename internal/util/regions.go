@@ -0,0 +1,32 @@
+package util
+
+import "strings"
+
+// azureRegions lists public Azure region names in their programmatic form.
+var azureRegions = map[string]struct{}{
+	"australiacentral": {}, "australiacentral2": {}, "australiaeast": {}, "australiasoutheast": {},
+	"austriaeast": {}, "belgiumcentral": {}, "brazilsouth": {}, "brazilsoutheast": {},
+	"canadacentral": {}, "canadaeast": {}, "centralindia": {}, "centralus": {},
+	"chilecentral": {}, "eastasia": {}, "eastus": {}, "eastus2": {},
+	"francecentral": {}, "francesouth": {}, "germanynorth": {}, "germanywestcentral": {},
+	"indonesiacentral": {}, "israelcentral": {}, "italynorth": {}, "japaneast": {},
+	"japanwest": {}, "jioindiacentral": {}, "jioindiawest": {}, "koreacentral": {},
+	"koreasouth": {}, "malaysiawest": {}, "mexicocentral": {}, "newzealandnorth": {},
+	"northcentralus": {}, "northeurope": {}, "norwayeast": {}, "norwaywest": {},
+	"polandcentral": {}, "qatarcentral": {}, "southafricanorth": {}, "southafricawest": {},
+	"southcentralus": {}, "southeastasia": {}, "southindia": {}, "spaincentral": {},
+	"swedencentral": {}, "swedensouth": {}, "switzerlandnorth": {}, "switzerlandwest": {},
+	"uaecentral": {}, "uaenorth": {}, "uksouth": {}, "ukwest": {},
+	"westcentralus": {}, "westeurope": {}, "westindia": {}, "westus": {},
+	"westus2": {}, "westus3": {},
+}
+
+// NormalizeAzureRegion returns the programmatic name of an Azure region given
+// either form ("West Europe" or "westeurope"), or "" if it is not a region.
+func NormalizeAzureRegion(value string) string {
+	name := strings.ToLower(strings.Join(strings.Fields(value), ""))
+	if _, ok := azureRegions[name]; ok {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package util
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CompareVersions orders two release versions numerically, ignoring a leading
+// "v". Missing segments count as zero and a pre-release sorts before its
+// release. Pre-release identifiers compare their numeric parts numerically,
+// so rc2 sorts before rc10. It returns -1, 0 or 1.
+func CompareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	for i := 0; i < len(aCore) || i < len(bCore); i++ {
+		av, bv := versionSegment(aCore, i), versionSegment(bCore, i)
+		if av != bv {
+			if av < bv {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return comparePrerelease(aPre, bPre)
+}
+
+// SortNewestFirst orders items by descending version, keeping the input order
+// for equal versions.
+func SortNewestFirst[T any](items []T, version func(T) string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return CompareVersions(version(items[i]), version(items[j])) > 0
+	})
+}
+
+func splitVersion(v string) ([]string, string) {
+	v = strings.TrimPrefix(strings.TrimSpace(strings.ToLower(v)), "v")
+	v, _, _ = strings.Cut(v, "+")
+	core, pre, _ := strings.Cut(v, "-")
+	return strings.Split(core, "."), pre
+}
+
+func versionSegment(segments []string, i int) int {
+	if i >= len(segments) {
+		return 0
+	}
+	n, err := strconv.Atoi(segments[i])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func comparePrerelease(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if c := compareIdentifier(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(aParts) < len(bParts):
+		return -1
+	case len(aParts) > len(bParts):
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier compares one pre-release identifier such as "rc1" or "3",
+// splitting off a trailing number so it compares numerically.
+func compareIdentifier(a, b string) int {
+	aText, aNum, aHasNum := splitTrailingNumber(a)
+	bText, bNum, bHasNum := splitTrailingNumber(b)
+	if c := strings.Compare(aText, bText); c != 0 {
+		return c
+	}
+	switch {
+	case aHasNum && bHasNum:
+		if aNum != bNum {
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	case aHasNum:
+		return 1
+	case bHasNum:
+		return -1
+	}
+	return 0
+}
+
+func splitTrailingNumber(s string) (string, int, bool) {
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+	if i == len(s) {
+		return s, 0, false
+	}
+	n, err := strconv.Atoi(s[i:])
+	if err != nil {
+		return s, 0, false
+	}
+	return s[:i], n, true
+}
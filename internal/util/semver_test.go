@@ -0,0 +1,58 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.2.0", "1.2.0", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"1.9.9", "2.0.0", -1},
+		{"1.0.0+build.5", "1.0.0", 0},
+		{"1.0.0-rc1", "1.0.0", -1},
+		{"1.0.0", "1.0.0-beta", 1},
+		{"1.0.0-rc2", "1.0.0-rc10", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-alpha", "1.0.0-alpha1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha", 1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"V2.0.0-RC1", "v2.0.0-rc1", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := CompareVersions(tt.b, tt.a); got != -tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestSortNewestFirst(t *testing.T) {
+	versions := []string{"v1.0.0", "v1.10.0", "v1.2.0-rc1", "v1.2.0", "v0.9.0", "v1.2.0-rc10"}
+	SortNewestFirst(versions, func(v string) string { return v })
+
+	want := []string{"v1.10.0", "v1.2.0", "v1.2.0-rc10", "v1.2.0-rc1", "v1.0.0", "v0.9.0"}
+	if !slices.Equal(versions, want) {
+		t.Errorf("SortNewestFirst = %v, want %v", versions, want)
+	}
+}
+
+func TestSortNewestFirstStable(t *testing.T) {
+	type release struct{ tag, name string }
+	releases := []release{{"v1.0.0", "first"}, {"1.0.0", "second"}, {"v2.0.0", "newest"}}
+	SortNewestFirst(releases, func(r release) string { return r.tag })
+
+	got := []string{releases[0].name, releases[1].name, releases[2].name}
+	if want := []string{"newest", "first", "second"}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
@@ -2,26 +2,27 @@ package util
 
 import "strings"
 
+// ExpandQueryVariants returns the lowercase query as typed, with separators
+// turned into spaces, and with separators removed, so "Key-Vault" yields
+// "key-vault", "key vault" and "keyvault". The order is stable, most literal
+// first, so callers can rank by variant.
 func ExpandQueryVariants(q string) []string {
-	base := strings.TrimSpace(q)
+	base := strings.ToLower(strings.TrimSpace(q))
 	if base == "" {
 		return []string{""}
 	}
-	variants := map[string]struct{}{}
+	var out []string
+	seen := map[string]struct{}{}
 	add := func(s string) {
-		if s != "" {
-			variants[s] = struct{}{}
+		if _, ok := seen[s]; s != "" && !ok {
+			seen[s] = struct{}{}
+			out = append(out, s)
 		}
 	}
 
 	add(base)
-	spaced := strings.NewReplacer("-", " ", "_", " ", "/", " ").Replace(base)
-	add(strings.Join(strings.Fields(spaced), " "))
+	spaced := strings.Join(strings.Fields(strings.NewReplacer("-", " ", "_", " ", "/", " ").Replace(base)), " ")
+	add(spaced)
 	add(strings.ReplaceAll(spaced, " ", ""))
-
-	out := make([]string, 0, len(variants))
-	for v := range variants {
-		out = append(out, strings.ToLower(v))
-	}
 	return out
 }
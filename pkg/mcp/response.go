@@ -4,6 +4,9 @@ import "encoding/json"
 
 type MCPResponse struct {
 	Content []ContentBlock `json:"content"`
+	// IsError marks a failed tool call so clients can tell it apart from a
+	// result without parsing the text.
+	IsError bool `json:"isError,omitempty"`
 }
 
 type ContentBlock struct {
@@ -12,9 +15,13 @@ type ContentBlock struct {
 }
 
 func (r *MCPResponse) ToMap() map[string]any {
-	return map[string]any{
+	result := map[string]any{
 		"content": r.Content,
 	}
+	if r.IsError {
+		result["isError"] = true
+	}
+	return result
 }
 
 func SuccessResponse(text string) map[string]any {
@@ -30,6 +37,7 @@ func ErrorResponse(message string) map[string]any {
 		Content: []ContentBlock{
 			{Type: "text", Text: message},
 		},
+		IsError: true,
 	}).ToMap()
 }
 
@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"os"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,7 +23,7 @@ import (
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/formatter"
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/indexer"
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/util"
-	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 )
 
@@ -42,31 +44,75 @@ type RPCError struct {
 type ToolCallParams struct {
 	Name      string `json:"name"`
 	Arguments any    `json:"arguments"`
+	Meta      struct {
+		ProgressToken any `json:"progressToken"`
+	} `json:"_meta"`
 }
 
 var errModuleNotInPrompt = errors.New("module not found in prompt")
 
 type Server struct {
+	ctx       context.Context
 	db        *database.DB
 	syncer    *indexer.Syncer
 	writer    io.Writer
+	writeMu   sync.Mutex
 	jobs      map[string]*SyncJob
 	jobsMutex sync.RWMutex
 	dbPath    string
 	token     string
 	org       string
+	localPath string
+	workers   int
+	maxFile   int64
+	prefix    string
+	archived  bool
 	dbMutex   sync.Mutex
+
+	// requests cancels the in-flight tools/call requests by request ID.
+	requests   map[string]context.CancelFunc
+	requestsMu sync.Mutex
+	calls      sync.WaitGroup
 }
 
 func NewServer(dbPath, token, org string) *Server {
 	return &Server{
-		dbPath: dbPath,
-		token:  token,
-		org:    org,
-		jobs:   make(map[string]*SyncJob),
+		dbPath:   dbPath,
+		token:    token,
+		org:      org,
+		prefix:   indexer.DefaultRepoPrefix,
+		jobs:     make(map[string]*SyncJob),
+		requests: make(map[string]context.CancelFunc),
 	}
 }
 
+// UseLocalSource indexes modules from a directory of cloned repositories
+// instead of the GitHub API.
+func (s *Server) UseLocalSource(path string) {
+	s.localPath = path
+}
+
+// SetSyncWorkers sets how many repositories a sync processes in parallel.
+func (s *Server) SetSyncWorkers(n int) {
+	s.workers = n
+}
+
+// SetMaxFileSize sets the largest file, in bytes, a sync stores.
+func (s *Server) SetMaxFileSize(n int64) {
+	s.maxFile = n
+}
+
+// SetRepoPrefix sets the name prefix of the repositories a sync indexes; an
+// empty prefix matches every repository name.
+func (s *Server) SetRepoPrefix(prefix string) {
+	s.prefix = prefix
+}
+
+// SetIncludeArchived makes syncs index archived repositories as well.
+func (s *Server) SetIncludeArchived(include bool) {
+	s.archived = include
+}
+
 type SyncJob struct {
 	ID          string
 	Type        string
@@ -75,6 +121,10 @@ type SyncJob struct {
 	CompletedAt *time.Time
 	Progress    *indexer.SyncProgress
 	Error       string
+	// RequestID is the tools/call request that started the job, which a
+	// notifications/cancelled message refers to.
+	RequestID any
+	cancel    context.CancelFunc
 }
 
 func (s *Server) ensureDB() error {
@@ -93,14 +143,27 @@ func (s *Server) ensureDB() error {
 
 	s.db = db
 	s.syncer = indexer.NewSyncer(db, s.token, s.org)
+	if s.localPath != "" {
+		s.syncer.SetLocalSource(s.localPath)
+	}
+	if s.workers > 0 {
+		s.syncer.SetWorkerCount(s.workers)
+	}
+	if s.maxFile > 0 {
+		s.syncer.SetMaxFileSize(s.maxFile)
+	}
+	s.syncer.SetRepoPrefix(s.prefix)
+	s.syncer.SetIncludeArchived(s.archived)
 	log.Println("Database initialized successfully")
 
 	return nil
 }
 
 func (s *Server) Run(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.ctx = ctx
 	s.writer = w
 	scanner := bufio.NewScanner(r)
+	defer s.calls.Wait()
 
 	for scanner.Scan() {
 		if err := ctx.Err(); err != nil {
@@ -121,6 +184,16 @@ func (s *Server) Run(ctx context.Context, r io.Reader, w io.Writer) error {
 			continue
 		}
 
+		// Tool calls run concurrently so a notifications/cancelled message
+		// can reach a call that is still running, such as a sync.
+		if msg.Method == "tools/call" {
+			s.calls.Add(1)
+			go func() {
+				defer s.calls.Done()
+				s.handleMessage(msg)
+			}()
+			continue
+		}
 		s.handleMessage(msg)
 	}
 
@@ -140,18 +213,29 @@ func (s *Server) handleMessage(msg Message) {
 	case "initialized", "notifications/initialized":
 		log.Println("Client initialized")
 		return
+	case "ping":
+		s.handlePing(msg)
 	case "tools/list":
 		s.handleToolsList(msg)
 	case "tools/call":
 		s.handleToolsCall(msg)
 	case "notifications/cancelled":
-		log.Println("Request cancelled")
+		s.handleCancelled(msg)
 		return
 	default:
 		s.sendError(-32601, "Method not found", msg.ID)
 	}
 }
 
+// handlePing answers liveness checks, which clients also send as keepalives.
+func (s *Server) handlePing(msg Message) {
+	s.sendResponse(Message{
+		JSONRPC: "2.0",
+		ID:      msg.ID,
+		Result:  map[string]any{},
+	})
+}
+
 func (s *Server) handleInitialize(msg Message) {
 	response := Message{
 		JSONRPC: "2.0",
@@ -190,15 +274,20 @@ func (s *Server) handleToolsList(msg Message) {
 		},
 		{
 			"name":        "list_modules",
-			"description": "List all available Terraform modules from local database",
+			"description": "List all available Terraform modules from local database, optionally filtered by provider",
 			"inputSchema": map[string]any{
-				"type":       "object",
-				"properties": map[string]any{},
+				"type": "object",
+				"properties": map[string]any{
+					"provider": map[string]any{
+						"type":        "string",
+						"description": "Only list modules using this provider (e.g. 'azurerm')",
+					},
+				},
 			},
 		},
 		{
 			"name":        "search_modules",
-			"description": "Search modules by name or description in local database",
+			"description": "Search modules by name, description or GitHub topic in local database",
 			"inputSchema": map[string]any{
 				"type": "object",
 				"properties": map[string]any{
@@ -224,6 +313,10 @@ func (s *Server) handleToolsList(msg Message) {
 						"type":        "string",
 						"description": "Name of the module",
 					},
+					"format": map[string]any{
+						"type":        "string",
+						"description": "Output format: 'markdown' (default) or 'json' for a single JSON code block",
+					},
 				},
 				"required": []string{"module_name"},
 			},
@@ -255,6 +348,30 @@ func (s *Server) handleToolsList(msg Message) {
 						"items":       map[string]any{"type": "string"},
 						"description": "Optional attribute presence filters (e.g., for_each, lifecycle.ignore_changes)",
 					},
+					"regex": map[string]any{
+						"type":        "boolean",
+						"description": "Treat query as a regular expression matched per line (e.g., azurerm_\\w+_network). Default: false",
+					},
+					"case_sensitive": map[string]any{
+						"type":        "boolean",
+						"description": "Match the query with exact case, e.g. to tell 'Local' from 'local'. Regex patterns are always case-sensitive unless they use (?i). Default: false",
+					},
+					"max_matches_per_file": map[string]any{
+						"type":        "number",
+						"description": "Maximum matching lines shown per file (default: 3)",
+					},
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Only search files of this module (optional)",
+					},
+					"context_lines": map[string]any{
+						"type":        "number",
+						"description": "Lines of context shown before and after each match (default: 2, max: 20)",
+					},
+					"file_type": map[string]any{
+						"type":        "string",
+						"description": "Only search files with this name (e.g., 'variables.tf', 'README.md') or extension (e.g., '.tf', '.md') (optional)",
+					},
 				},
 				"required": []string{"query"},
 			},
@@ -267,11 +384,11 @@ func (s *Server) handleToolsList(msg Message) {
 				"properties": map[string]any{
 					"module_name": map[string]any{
 						"type":        "string",
-						"description": "Name of the module (e.g., terraform-azure-aks)",
+						"description": "Name of the module (e.g., terraform-azure-aks, or terraform-azure-network//modules/subnet for a submodule)",
 					},
 					"file_path": map[string]any{
 						"type":        "string",
-						"description": "Path to the file within the module (e.g., variables.tf, main.tf, README.md)",
+						"description": "Path to the file within the module (e.g., variables.tf, main.tf, README.md); submodule paths may be relative to the submodule directory",
 					},
 				},
 				"required": []string{"module_name", "file_path"},
@@ -279,7 +396,7 @@ func (s *Server) handleToolsList(msg Message) {
 		},
 		{
 			"name":        "extract_variable_definition",
-			"description": "Extract the complete definition of a specific variable from a module's variables.tf",
+			"description": "Extract the complete definition of a specific variable from a module, whichever .tf file declares it",
 			"inputSchema": map[string]any{
 				"type": "object",
 				"properties": map[string]any{
@@ -384,7 +501,7 @@ func (s *Server) handleToolsList(msg Message) {
 		},
 		{
 			"name":        "sync_status",
-			"description": "Get status of ongoing or previous sync jobs",
+			"description": "Get status of ongoing or previous sync jobs. Without a job_id it also reports the index contents (module and file counts, database size, last sync time) and recent sync history.",
 			"inputSchema": map[string]any{
 				"type": "object",
 				"properties": map[string]any{
@@ -407,7 +524,11 @@ func (s *Server) handleToolsList(msg Message) {
 					},
 					"version": map[string]any{
 						"type":        "string",
-						"description": "Optional module version (e.g., 1.2.0). Defaults to the latest release.",
+						"description": "Optional module version (e.g., 1.2.0). Defaults to the latest release as chosen by latest_strategy.",
+					},
+					"latest_strategy": map[string]any{
+						"type":        "string",
+						"description": "How to pick the latest release: highest semantic version (semver, default) or most recent release date (date)",
 					},
 				},
 				"required": []string{"module_name"},
@@ -457,216 +578,1440 @@ func (s *Server) handleToolsList(msg Message) {
 				"required": []string{"module_name", "version"},
 			},
 		},
-	}
-
-	response := Message{
-		JSONRPC: "2.0",
-		ID:      msg.ID,
-		Result: map[string]any{
-			"tools": tools,
+		{
+			"name":        "get_terraform_version",
+			"description": "Get the Terraform CLI required_version constraint declared in a module's terraform block",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module (e.g., terraform-azure-aks)",
+					},
+				},
+				"required": []string{"module_name"},
+			},
 		},
-	}
-	s.sendResponse(response)
-}
-
-func (s *Server) handleToolsCall(msg Message) {
-	paramsBytes, err := json.Marshal(msg.Params)
-	if err != nil {
-		s.sendError(-32602, "Invalid params", msg.ID)
-		return
-	}
-
-	var params ToolCallParams
-	if err := json.Unmarshal(paramsBytes, &params); err != nil {
-		s.sendError(-32602, "Invalid params", msg.ID)
-		return
-	}
-
-	log.Printf("Tool call: %s", params.Name)
-
-	var result any
-	switch params.Name {
-	case "sync_modules":
-		result = s.handleSyncModules()
-	case "sync_updates_modules":
-		result = s.handleSyncUpdatesModules()
-	case "list_modules":
-		result = s.handleListModules()
-	case "search_modules":
-		result = s.handleSearchModules(params.Arguments)
-	case "get_module_info":
-		result = s.handleGetModuleInfo(params.Arguments)
-	case "search_code":
-		result = s.handleSearchCode(params.Arguments)
-	case "get_file_content":
-		result = s.handleGetFileContent(params.Arguments)
-	case "extract_variable_definition":
-		result = s.handleExtractVariableDefinition(params.Arguments)
-	case "compare_pattern_across_modules":
-		result = s.handleComparePatternAcrossModules(params.Arguments)
-	case "analyze_code_relationships":
-		result = s.handleAnalyzeCodeRelationships(params.Arguments)
-	case "list_module_examples":
-		result = s.handleListModuleExamples(params.Arguments)
-	case "get_example_content":
-		result = s.handleGetExampleContent(params.Arguments)
-	case "sync_status":
-		result = s.handleSyncStatus(params.Arguments)
-	case "get_release_summary":
-		result = s.handleGetReleaseSummary(params.Arguments)
-	case "get_release_snippet":
-		result = s.handleGetReleaseSnippet(params.Arguments)
-	case "backfill_release":
-		result = s.handleBackfillRelease(params.Arguments)
-	default:
-		s.sendError(-32601, "Tool not found", msg.ID)
-		return
-	}
-
-	response := Message{
-		JSONRPC: "2.0",
-		ID:      msg.ID,
-		Result:  result,
-	}
-	s.sendResponse(response)
-}
-
-func (s *Server) handleSyncModules() map[string]any {
-	if err := s.ensureDB(); err != nil {
-		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
-	}
-
-	job := s.startSyncJob("full_sync", func() (*indexer.SyncProgress, error) {
-		log.Println("Starting full repository sync (async job)...")
-		return s.syncer.SyncAll()
-	})
-
-	return map[string]any{
-		"content": []map[string]any{
-			{
-				"type": "text",
-				"text": fmt.Sprintf("Full sync started.\nJob ID: %s\nUse `sync_status` with this job ID to monitor progress.", job.ID),
+		{
+			"name":        "find_example_for_feature",
+			"description": "Find which examples of a module demonstrate a given feature (e.g., 'private endpoint', 'identity')",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module (e.g., terraform-azure-aks)",
+					},
+					"feature": map[string]any{
+						"type":        "string",
+						"description": "Feature keyword to look for in the example files (e.g., 'private endpoint')",
+					},
+				},
+				"required": []string{"module_name", "feature"},
 			},
 		},
-	}
-}
-
-func (s *Server) handleSyncUpdatesModules() map[string]any {
-	if err := s.ensureDB(); err != nil {
-		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
-	}
-
-	log.Println("Starting incremental repository sync (updates only)...")
-
-	progress, err := s.syncer.SyncUpdates()
-	if err != nil {
-		return ErrorResponse(fmt.Sprintf("Sync failed: %v", err))
-	}
-
-	text := formatter.IncrementalSyncProgress(
-		progress.TotalRepos,
-		len(progress.UpdatedRepos),
-		progress.SkippedRepos,
-		progress.UpdatedRepos,
-		progress.Errors,
-	)
-
-	if summary := s.releaseSummaryIfUpdated(progress.UpdatedRepos); summary != "" {
-		if strings.TrimSpace(text) != "" {
-			text = strings.TrimSpace(text) + "\n\n" + summary
-		} else {
-			text = summary
-		}
-	}
-
-	return SuccessResponse(text)
-}
-
-func (s *Server) handleSyncStatus(args any) map[string]any {
-	statusArgs, err := UnmarshalArgs[struct {
-		JobID string `json:"job_id"`
-	}](args)
-	if err != nil {
-		return ErrorResponse("Error: Invalid parameters")
-	}
-
-	if statusArgs.JobID != "" {
-		job, ok := s.getJob(statusArgs.JobID)
-		if !ok {
-			return ErrorResponse(fmt.Sprintf("Job '%s' not found", statusArgs.JobID))
-		}
-
-		text := s.formatJobDetails(job)
-		return SuccessResponse(text)
-	}
-
-	jobs := s.listJobs()
-	text := s.formatJobList(jobs)
-	return SuccessResponse(text)
-}
-
-func (s *Server) handleListModules() map[string]any {
-	if err := s.ensureDB(); err != nil {
-		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
-	}
-
-	modules, err := s.db.ListModules()
-	if err != nil {
-		return ErrorResponse(fmt.Sprintf("Error loading modules: %v", err))
-	}
-
-	if len(modules) == 0 {
-		return SuccessResponse("No modules found. Run sync_modules tool to fetch modules from GitHub.")
-	}
-
-	text := formatter.ModuleList(modules)
-	return SuccessResponse(text)
-}
-
-func (s *Server) handleSearchModules(args any) map[string]any {
-	if err := s.ensureDB(); err != nil {
-		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
-	}
-
-	searchArgs, err := UnmarshalArgs[struct {
-		Query string `json:"query"`
-		Limit int    `json:"limit"`
-	}](args)
-	if err != nil {
-		return ErrorResponse("Error: Invalid search query")
-	}
-
-	if searchArgs.Limit == 0 {
-		searchArgs.Limit = 10
-	}
-
-	variants := util.ExpandQueryVariants(searchArgs.Query)
-	seen := make(map[int64]struct{})
-	var merged []database.Module
-	for _, v := range variants {
-		mods, err := s.db.SearchModules(v, searchArgs.Limit)
-		if err != nil {
-			continue
-		}
-		for _, m := range mods {
-			if _, ok := seen[m.ID]; ok {
-				continue
-			}
-			seen[m.ID] = struct{}{}
-			merged = append(merged, m)
-			if searchArgs.Limit > 0 && len(merged) >= searchArgs.Limit {
-				break
-			}
-		}
-		if searchArgs.Limit > 0 && len(merged) >= searchArgs.Limit {
-			break
-		}
-	}
+		{
+			"name":        "list_data_source_types",
+			"description": "List every data source type used across the catalog with the number of modules reading it",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"provider": map[string]any{
+						"type":        "string",
+						"description": "Optional provider filter (e.g., azurerm)",
+					},
+					"min_count": map[string]any{
+						"type":        "number",
+						"description": "Optional minimum number of modules using the type (default: 1)",
+					},
+				},
+			},
+		},
+		{
+			"name":        "check_sensitive_propagation",
+			"description": "Flag module outputs that expose sensitive variables or sensitive resource attributes without being marked sensitive",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module (e.g., terraform-azure-aks)",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "catalog_overview",
+			"description": "Group all modules into high-level Azure categories (networking, compute, storage, data, identity, integration, security) based on their dominant resource types",
+			"inputSchema": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
+		{
+			"name":        "get_file_diff",
+			"description": "Get the unified diff of a single file between two release tags of a module",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+					"file_path": map[string]any{
+						"type":        "string",
+						"description": "Repository-relative file path (e.g., 'main.tf')",
+					},
+					"from_version": map[string]any{
+						"type":        "string",
+						"description": "Base version or tag (e.g., '1.2.0' or 'v1.2.0')",
+					},
+					"to_version": map[string]any{
+						"type":        "string",
+						"description": "Target version or tag",
+					},
+				},
+				"required": []string{"module_name", "file_path", "from_version", "to_version"},
+			},
+		},
+		{
+			"name":        "lint_reserved_names",
+			"description": "Find variables or outputs named with reserved or confusing Terraform identifiers (count, for_each, source, providers, depends_on, ...)",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "get_complex_variables",
+			"description": "Rank a module's variables by the structural complexity of their type (nesting depth and number of object attributes), most complex first",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+					"limit": map[string]any{
+						"type":        "number",
+						"description": "Maximum number of variables to return (default: 10)",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "get_module_versions_file",
+			"description": "Get the file declaring a module's version constraints (versions.tf, terraform.tf or providers.tf, falling back to any file with a required_providers block)",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "list_modules_by_org",
+			"description": "List indexed modules grouped by the GitHub org they were synced from",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"org": map[string]any{
+						"type":        "string",
+						"description": "Org to filter on (e.g., 'cloudnationhq'). Omit to group all modules by org.",
+					},
+				},
+			},
+		},
+		{
+			"name":        "list_variable_fields",
+			"description": "Flatten an object-type variable into dotted field paths with each field's type and optionality",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+					"variable_name": map[string]any{
+						"type":        "string",
+						"description": "Variable name (e.g., 'config')",
+					},
+				},
+				"required": []string{"module_name", "variable_name"},
+			},
+		},
+		{
+			"name":        "find_introduced_in",
+			"description": "Find the first release of a module in which a variable, resource type or data source type appears",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+					"item": map[string]any{
+						"type":        "string",
+						"description": "Variable name or resource/data source type (e.g., 'azurerm_subnet')",
+					},
+					"max_releases": map[string]any{
+						"type":        "number",
+						"description": "Maximum number of most recent releases to scan (default: 15)",
+					},
+				},
+				"required": []string{"module_name", "item"},
+			},
+		},
+		{
+			"name":        "get_example_bundle",
+			"description": "Get everything needed to reproduce a module example locally: its files, the module source address to use, and the required tfvars and inputs",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+					"example_name": map[string]any{
+						"type":        "string",
+						"description": "Example name (e.g., 'default', 'complete')",
+					},
+					"latest_strategy": map[string]any{
+						"type":        "string",
+						"description": "How to pick the latest release: highest semantic version (semver, default) or most recent release date (date)",
+					},
+				},
+				"required": []string{"module_name", "example_name"},
+			},
+		},
+		{
+			"name":        "audit_readme_inputs",
+			"description": "Compare the inputs table in a module's README (terraform-docs format) with its declared variables and report undocumented or stale entries",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "list_module_outputs_with_values",
+			"description": "List a module's outputs with their description, sensitivity and the value expression each one returns",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "module_similarity",
+			"description": "Compute a 0-1 similarity score between two modules from overlapping resource types, variable names and tags, with a breakdown of what they share",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_a": map[string]any{
+						"type":        "string",
+						"description": "First module name (e.g., 'terraform-azure-vnet')",
+					},
+					"module_b": map[string]any{
+						"type":        "string",
+						"description": "Second module name",
+					},
+				},
+				"required": []string{"module_a", "module_b"},
+			},
+		},
+		{
+			"name":        "list_resources",
+			"description": "List resource instances across the catalog or a single module, filterable by provider and resource type prefix, with pagination",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Optional: restrict to one module (e.g., 'terraform-azure-vnet')",
+					},
+					"provider": map[string]any{
+						"type":        "string",
+						"description": "Optional: provider name (e.g., 'azurerm')",
+					},
+					"resource_type": map[string]any{
+						"type":        "string",
+						"description": "Optional: resource type prefix (e.g., 'azurerm_storage_')",
+					},
+					"limit": map[string]any{
+						"type":        "number",
+						"description": "Optional: maximum number of results to return (default: 50)",
+					},
+					"offset": map[string]any{
+						"type":        "number",
+						"description": "Optional: number of results to skip for pagination (default: 0)",
+					},
+				},
+			},
+		},
+		{
+			"name":        "verify_changelog",
+			"description": "Cross-check a release's changelog entries against the files actually changed between its tags, flagging entries without code changes and significant changes without entries",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+					"version": map[string]any{
+						"type":        "string",
+						"description": "Release version to verify (e.g., 1.2.0 or v1.2.0)",
+					},
+				},
+				"required": []string{"module_name", "version"},
+			},
+		},
+		{
+			"name":        "export_module",
+			"description": "Export a single module's full indexed metadata (variables with parsed schemas, outputs, resources, data sources, providers, examples, releases) as a versioned JSON document",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "find_unpinned_providers",
+			"description": "Find modules whose required_providers version constraint is missing or has no upper bound (e.g. '>= 3.0' or '>= 0'), which risks breakage on provider major releases. Reports each module, provider, constraint and why it is considered unpinned.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Optional module to check; checks every module when omitted",
+					},
+				},
+			},
+		},
+		{
+			"name":        "get_variable_provenance",
+			"description": "Find the release that most recently changed a variable's declaration by comparing variables across recent release tags. Reports the version and the nature of the change (added, type changed, default changed, sensitive or description changed).",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g. terraform-azure-vnet or vnet)",
+					},
+					"variable_name": map[string]any{
+						"type":        "string",
+						"description": "Variable to trace (e.g. tags)",
+					},
+					"max_releases": map[string]any{
+						"type":        "number",
+						"description": "Maximum number of recent releases to scan (default 10)",
+					},
+				},
+				"required": []string{"module_name", "variable_name"},
+			},
+		},
+		{
+			"name":        "find_pinned_example_sources",
+			"description": "Scan example module calls for sources pinned to a specific version (git ?ref= or an exact registry version) and flag examples pinned to a version older than the referenced module's latest release.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Optional module whose examples to scan; scans all modules when omitted",
+					},
+				},
+			},
+		},
+		{
+			"name":        "get_public_api",
+			"description": "Get a module's public interface only: required inputs, optional inputs with defaults, and outputs, excluding internal resources and locals. Includes a hash of the API surface so interface changes can be detected cheaply between versions.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g. terraform-azure-vnet or vnet)",
+					},
+					"latest_strategy": map[string]any{
+						"type":        "string",
+						"description": "How to pick the latest release: highest semantic version (semver, default) or most recent release date (date)",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "list_all_variables",
+			"description": "List variables across all modules grouped by name, showing how many modules define each one, their types, and whether the types diverge between modules. Useful for spotting inconsistent variable typing across the catalog.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"type_filter": map[string]any{
+						"type":        "string",
+						"description": "Optional type to match, whitespace-insensitive. Plain text matches as a substring; use '...' as a wildcard for a full match (e.g. list(object(...)))",
+					},
+					"required_only": map[string]any{
+						"type":        "boolean",
+						"description": "Only include variables without a default (default: false)",
+					},
+				},
+			},
+		},
+		{
+			"name":        "list_most_active_modules",
+			"description": "Rank modules by how many releases they have had, with their latest release version and date, to gauge how actively each module is maintained.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"limit": map[string]any{
+						"type":        "number",
+						"description": "Maximum number of modules to return (default 20)",
+					},
+				},
+			},
+		},
+		{
+			"name":        "get_module_data_sources",
+			"description": "List the data sources a module reads, as data \"<type>\" \"<name>\" entries with their provider and source file. Accepts submodules in the name//modules/sub form.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g. terraform-azure-vnet, vnet, or terraform-azure-vnet//modules/subnet)",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "get_supported_regions",
+			"description": "Heuristically determine which Azure regions a module is designed for by scanning location/region inputs, region literals in their defaults, and region allowlists in validation conditions. Reports whether location is a required input.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g. terraform-azure-vnet or vnet)",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "list_variable_types",
+			"description": "List the distinct variable type constraints used across all modules with how many variables and modules use each. Types are normalized so formatting differences group together, revealing the catalog's type vocabulary and overly bespoke types.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"limit": map[string]any{
+						"type":        "number",
+						"description": "Maximum number of types to show (default 50)",
+					},
+				},
+			},
+		},
+		{
+			"name":        "list_parse_errors",
+			"description": "List Terraform files that failed HCL parsing during the last sync, with the module, file and parser diagnostic. Explains gaps in indexed variables, outputs and resources.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Optional module to check; lists errors for all modules when omitted",
+					},
+				},
+			},
+		},
+		{
+			"name":        "get_migration_notes",
+			"description": "Aggregate migration guidance for a module: moved/removed/import blocks in its code, breaking-change entries from its releases, and README sections about migrating or upgrading",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "compare_module_versions",
+			"description": "Summarize what changed in a module's interface between two versions: variables, outputs, resources and data sources added, removed or changed",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+					"from_version": map[string]any{
+						"type":        "string",
+						"description": "Older version or tag (e.g., '2.1.0' or 'v2.1.0')",
+					},
+					"to_version": map[string]any{
+						"type":        "string",
+						"description": "Newer version or tag (e.g., '3.0.0' or 'v3.0.0')",
+					},
+				},
+				"required": []string{"module_name", "from_version", "to_version"},
+			},
+		},
+		{
+			"name":        "count_pattern_across_modules",
+			"description": "Rank modules by how often a code pattern occurs (e.g., dynamic blocks, resource definitions). Returns per-module match counts, highest first, without code blocks.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"pattern": map[string]any{
+						"type":        "string",
+						"description": "The pattern to count (e.g., 'dynamic \"identity\"', 'resource \"azurerm_', 'lifecycle {')",
+					},
+					"file_type": map[string]any{
+						"type":        "string",
+						"description": "Optional: filter by file type (e.g., 'main.tf', 'variables.tf'). Leave empty for all .tf files.",
+					},
+				},
+				"required": []string{"pattern"},
+			},
+		},
+		{
+			"name":        "list_module_tags",
+			"description": "List every git tag of a module's repository with its commit SHA, newest version first, flagging tags that have no changelog entry",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+					"limit": map[string]any{
+						"type":        "number",
+						"description": "Optional: maximum number of tags to fetch (default: 300, max: 1000)",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "compare_resources",
+			"description": "Compare the resources a module declares at two versions, reporting resources added, removed, or whose type changed; these changes create, destroy or replace infrastructure on upgrade",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+					"from_version": map[string]any{
+						"type":        "string",
+						"description": "Older version or tag (e.g., '2.1.0' or 'v2.1.0')",
+					},
+					"to_version": map[string]any{
+						"type":        "string",
+						"description": "Newer version or tag (e.g., '3.0.0' or 'v3.0.0')",
+					},
+				},
+				"required": []string{"module_name", "from_version", "to_version"},
+			},
+		},
+		{
+			"name":        "get_quickstart",
+			"description": "Getting-started guide for a module: the source line pinned to the latest release, required inputs, a copy-paste minimal module block (from the simplest example, or generated), and the key outputs",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "list_submodules",
+			"description": "List the nested modules of a repository (name//modules/sub records) with their file and resource counts. Passing a submodule lists its siblings.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Parent module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "get_module_state_migrations",
+			"description": "List the moved, removed and import blocks a module declares, with from/to addresses or import ids and their source locations. Use before upgrading to see which state refactors a version applies.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "module_health_report",
+			"description": "Audit catalog documentation quality: flag root modules without examples, without a README, or declaring no variables or no outputs, with a count per check",
+			"inputSchema": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
+		{
+			"name":        "get_resource_attributes",
+			"description": "List the top-level arguments and nested block types (e.g. network_rules, dynamic identity) each resource in a module sets. Useful to check whether a module configures a given argument such as min_tls_version.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-sa')",
+					},
+					"resource_type": map[string]any{
+						"type":        "string",
+						"description": "Only show resources of this type (e.g., 'azurerm_storage_account')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "get_module_dependencies",
+			"description": "List the module blocks a module calls, with their source, source kind (local, registry, git, other), version and the catalog module each resolves to. Use to understand how a top-level module is composed.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "compare_variables_across_modules",
+			"description": "Show how every module defines a given variable (type, default, required, sensitive) side by side, and which root modules don't define it. Use to check consistency, e.g. that every `tags` variable is map(string).",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"variable_name": map[string]any{
+						"type":        "string",
+						"description": "Variable name to compare (e.g., 'tags')",
+					},
+				},
+				"required": []string{"variable_name"},
+			},
+		},
+		{
+			"name":        "list_module_releases",
+			"description": "List the indexed releases of a module, newest version first, with tag, release date and number of changelog entries. Use to pick a version for get_release_summary.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name (e.g., 'terraform-azure-vnet')",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "get_changelog_range",
+			"description": "Summarize every changelog entry of a module between two versions (inclusive), grouped by section and tagged with the release that introduced it. Use for questions like 'what changed from v1.0.0 to v1.4.0'.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module to inspect (e.g., terraform-azure-aks)",
+					},
+					"from_version": map[string]any{
+						"type":        "string",
+						"description": "Oldest version to include (e.g., 1.0.0 or v1.0.0)",
+					},
+					"to_version": map[string]any{
+						"type":        "string",
+						"description": "Newest version to include (e.g., 1.4.0 or v1.4.0)",
+					},
+				},
+				"required": []string{"module_name", "from_version", "to_version"},
+			},
+		},
+		{
+			"name":        "get_module_providers",
+			"description": "List the providers a module requires with their source address and version constraint from required_providers (object or shorthand string form). Use to check which azurerm version a module pins.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module to inspect (e.g., terraform-azure-aks)",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "search_outputs",
+			"description": "Search outputs across all modules by name or description (case-insensitive substring). Returns the module, output name, whether it is sensitive and its description. Use to find which module exposes e.g. a connection_string output.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query": map[string]any{
+						"type":        "string",
+						"description": "Text to match against output names and descriptions (e.g., connection_string)",
+					},
+					"limit": map[string]any{
+						"type":        "number",
+						"description": "Maximum number of results (default: 50)",
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+		{
+			"name":        "extract_block",
+			"description": "Return the exact HCL of one block in a module without fetching the whole file: a variable, output, resource, data source or locals block. Identifiers: resource/data as type.name (or just type for all blocks of that type), variable/output by name, locals by local value name (or empty for all locals blocks).",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module to inspect (e.g., terraform-azure-aks)",
+					},
+					"block_type": map[string]any{
+						"type":        "string",
+						"description": "Block type: variable, output, resource, data or locals",
+					},
+					"identifier": map[string]any{
+						"type":        "string",
+						"description": "Block identifier, e.g. azurerm_storage_account.this, location, or a local value name",
+					},
+				},
+				"required": []string{"module_name", "block_type"},
+			},
+		},
+		{
+			"name":        "resource_type_stats",
+			"description": "Rank every resource type declared across the catalog by the number of modules using it and its total occurrences. Shows which Azure resources the module library covers most heavily.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"provider": map[string]any{
+						"type":        "string",
+						"description": "Optional provider filter (e.g., azurerm)",
+					},
+					"min_count": map[string]any{
+						"type":        "number",
+						"description": "Optional minimum number of modules using the type (default: 1)",
+					},
+				},
+			},
+		},
+		{
+			"name":        "get_module_readme",
+			"description": "Get the full README of a module, or only the markdown section under a given heading",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module",
+					},
+					"section": map[string]any{
+						"type":        "string",
+						"description": "Optional heading whose section to return (case-insensitive; partial headings match when no heading matches exactly)",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "validate_example",
+			"description": "Check a module example against the module's variables, reporting inputs the module does not declare and required variables the example does not set",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module",
+					},
+					"example_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the example directory (e.g., default)",
+					},
+				},
+				"required": []string{"module_name", "example_name"},
+			},
+		},
+		{
+			"name":        "get_module_tree",
+			"description": "Show the file layout of a module as an indented tree, including examples and submodules",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "get_module_defaults",
+			"description": "List the default value of every variable in a module, marking required variables without a default; use format 'hcl' for name = default lines to seed a tfvars file",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module",
+					},
+					"format": map[string]any{
+						"type":        "string",
+						"description": "Output format: 'markdown' (default) table or 'hcl' assignments",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "get_module_locals",
+			"description": "Show the locals a module computes, with each name and its raw expression grouped by file",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "get_files",
+			"description": "Get the content of several files of a module in one call; missing files are reported instead of failing the call",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Module name",
+					},
+					"file_paths": map[string]any{
+						"type":        "array",
+						"items":       map[string]any{"type": "string"},
+						"description": "Paths of the files within the module (e.g. ['main.tf', 'variables.tf', 'outputs.tf'])",
+					},
+				},
+				"required": []string{"module_name", "file_paths"},
+			},
+		},
+		{
+			"name":        "find_modules_using_resource",
+			"description": "Find every module that declares a given resource type (exact match, e.g. 'azurerm_private_endpoint'), with instance counts and source files",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"resource_type": map[string]any{
+						"type":        "string",
+						"description": "Full resource type (e.g., 'azurerm_private_endpoint')",
+					},
+				},
+				"required": []string{"resource_type"},
+			},
+		},
+		{
+			"name":        "list_all_examples",
+			"description": "List every example directory across all modules with its file count, optionally filtered by example name (e.g. 'private')",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name_filter": map[string]any{
+						"type":        "string",
+						"description": "Optional: only examples whose name contains this text",
+					},
+				},
+			},
+		},
+		{
+			"name":        "compare_examples",
+			"description": "Show a unified diff between two examples of the same module, matching their files by name (e.g. what 'complete' adds over 'default')",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module",
+					},
+					"example_a": map[string]any{
+						"type":        "string",
+						"description": "Base example name (e.g., 'default')",
+					},
+					"example_b": map[string]any{
+						"type":        "string",
+						"description": "Example to compare against the base (e.g., 'complete')",
+					},
+				},
+				"required": []string{"module_name", "example_a", "example_b"},
+			},
+		},
+		{
+			"name":        "get_variable_usage",
+			"description": "Show how the module's examples set a given variable, returning the assignment snippets from module blocks in examples/",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module",
+					},
+					"variable_name": map[string]any{
+						"type":        "string",
+						"description": "Variable to look up (e.g., 'config')",
+					},
+				},
+				"required": []string{"module_name", "variable_name"},
+			},
+		},
+		{
+			"name":        "get_variable_schema",
+			"description": "Render a module's variables (types, defaults, required, descriptions) as a JSON Schema document for building forms or validating inputs",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"module_name": map[string]any{
+						"type":        "string",
+						"description": "Name of the module",
+					},
+				},
+				"required": []string{"module_name"},
+			},
+		},
+		{
+			"name":        "recently_updated_modules",
+			"description": "List modules ordered by when their repository was last updated, newest first, with the date and how long ago it was. Useful for release monitoring.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"limit": map[string]any{
+						"type":        "number",
+						"description": "Maximum number of modules to return (default 20)",
+					},
+				},
+			},
+		},
+	}
+
+	response := Message{
+		JSONRPC: "2.0",
+		ID:      msg.ID,
+		Result: map[string]any{
+			"tools": tools,
+		},
+	}
+	s.sendResponse(response)
+}
+
+func (s *Server) handleToolsCall(msg Message) {
+	paramsBytes, err := json.Marshal(msg.Params)
+	if err != nil {
+		s.sendError(-32602, "Invalid params", msg.ID)
+		return
+	}
+
+	var params ToolCallParams
+	if err := json.Unmarshal(paramsBytes, &params); err != nil {
+		s.sendError(-32602, "Invalid params", msg.ID)
+		return
+	}
+
+	log.Printf("Tool call: %s", params.Name)
+
+	ctx, done := s.beginRequest(msg.ID)
+	defer done()
+
+	var result any
+	switch params.Name {
+	case "sync_modules":
+		result = s.handleSyncModules(msg.ID, params.Meta.ProgressToken)
+	case "sync_updates_modules":
+		result = s.handleSyncUpdatesModules(ctx, params.Meta.ProgressToken)
+	case "list_modules":
+		result = s.handleListModules(params.Arguments)
+	case "search_modules":
+		result = s.handleSearchModules(params.Arguments)
+	case "get_module_info":
+		result = s.handleGetModuleInfo(params.Arguments)
+	case "search_code":
+		result = s.handleSearchCode(params.Arguments)
+	case "get_file_content":
+		result = s.handleGetFileContent(params.Arguments)
+	case "extract_variable_definition":
+		result = s.handleExtractVariableDefinition(params.Arguments)
+	case "compare_pattern_across_modules":
+		result = s.handleComparePatternAcrossModules(params.Arguments)
+	case "analyze_code_relationships":
+		result = s.handleAnalyzeCodeRelationships(params.Arguments)
+	case "list_module_examples":
+		result = s.handleListModuleExamples(params.Arguments)
+	case "get_example_content":
+		result = s.handleGetExampleContent(params.Arguments)
+	case "sync_status":
+		result = s.handleSyncStatus(params.Arguments)
+	case "get_release_summary":
+		result = s.handleGetReleaseSummary(ctx, params.Arguments)
+	case "get_release_snippet":
+		result = s.handleGetReleaseSnippet(ctx, params.Arguments)
+	case "backfill_release":
+		result = s.handleBackfillRelease(params.Arguments)
+	case "get_terraform_version":
+		result = s.handleGetTerraformVersion(params.Arguments)
+	case "find_example_for_feature":
+		result = s.handleFindExampleForFeature(params.Arguments)
+	case "list_data_source_types":
+		result = s.handleListDataSourceTypes(params.Arguments)
+	case "check_sensitive_propagation":
+		result = s.handleCheckSensitivePropagation(params.Arguments)
+	case "catalog_overview":
+		result = s.handleCatalogOverview()
+	case "get_file_diff":
+		result = s.handleGetFileDiff(ctx, params.Arguments)
+	case "lint_reserved_names":
+		result = s.handleLintReservedNames(params.Arguments)
+	case "get_complex_variables":
+		result = s.handleGetComplexVariables(params.Arguments)
+	case "get_module_versions_file":
+		result = s.handleGetModuleVersionsFile(params.Arguments)
+	case "list_modules_by_org":
+		result = s.handleListModulesByOrg(params.Arguments)
+	case "list_variable_fields":
+		result = s.handleListVariableFields(params.Arguments)
+	case "find_introduced_in":
+		result = s.handleFindIntroducedIn(ctx, params.Arguments)
+	case "get_example_bundle":
+		result = s.handleGetExampleBundle(params.Arguments)
+	case "audit_readme_inputs":
+		result = s.handleAuditReadmeInputs(params.Arguments)
+	case "list_module_outputs_with_values":
+		result = s.handleListModuleOutputsWithValues(params.Arguments)
+	case "module_similarity":
+		result = s.handleModuleSimilarity(params.Arguments)
+	case "list_resources":
+		result = s.handleListResources(params.Arguments)
+	case "verify_changelog":
+		result = s.handleVerifyChangelog(ctx, params.Arguments)
+	case "export_module":
+		result = s.handleExportModule(params.Arguments)
+	case "find_unpinned_providers":
+		result = s.handleFindUnpinnedProviders(params.Arguments)
+	case "get_variable_provenance":
+		result = s.handleGetVariableProvenance(ctx, params.Arguments)
+	case "find_pinned_example_sources":
+		result = s.handleFindPinnedExampleSources(params.Arguments)
+	case "get_public_api":
+		result = s.handleGetPublicAPI(params.Arguments)
+	case "list_all_variables":
+		result = s.handleListAllVariables(params.Arguments)
+	case "list_most_active_modules":
+		result = s.handleListMostActiveModules(params.Arguments)
+	case "get_module_data_sources":
+		result = s.handleGetModuleDataSources(params.Arguments)
+	case "get_supported_regions":
+		result = s.handleGetSupportedRegions(params.Arguments)
+	case "list_variable_types":
+		result = s.handleListVariableTypes(params.Arguments)
+	case "list_parse_errors":
+		result = s.handleListParseErrors(params.Arguments)
+	case "get_migration_notes":
+		result = s.handleGetMigrationNotes(params.Arguments)
+	case "compare_module_versions":
+		result = s.handleCompareModuleVersions(ctx, params.Arguments)
+	case "count_pattern_across_modules":
+		result = s.handleCountPatternAcrossModules(params.Arguments)
+	case "list_module_tags":
+		result = s.handleListModuleTags(ctx, params.Arguments)
+	case "compare_resources":
+		result = s.handleCompareResources(ctx, params.Arguments)
+	case "get_quickstart":
+		result = s.handleGetQuickstart(params.Arguments)
+	case "list_submodules":
+		result = s.handleListSubmodules(params.Arguments)
+	case "get_module_state_migrations":
+		result = s.handleGetModuleStateMigrations(params.Arguments)
+	case "module_health_report":
+		result = s.handleModuleHealthReport()
+	case "get_resource_attributes":
+		result = s.handleGetResourceAttributes(params.Arguments)
+	case "get_module_dependencies":
+		result = s.handleGetModuleDependencies(params.Arguments)
+	case "compare_variables_across_modules":
+		result = s.handleCompareVariablesAcrossModules(params.Arguments)
+	case "list_module_releases":
+		result = s.handleListModuleReleases(params.Arguments)
+	case "get_changelog_range":
+		result = s.handleGetChangelogRange(params.Arguments)
+	case "get_module_providers":
+		result = s.handleGetModuleProviders(params.Arguments)
+	case "search_outputs":
+		result = s.handleSearchOutputs(params.Arguments)
+	case "extract_block":
+		result = s.handleExtractBlock(params.Arguments)
+	case "resource_type_stats":
+		result = s.handleResourceTypeStats(params.Arguments)
+	case "get_module_readme":
+		result = s.handleGetModuleReadme(params.Arguments)
+	case "validate_example":
+		result = s.handleValidateExample(params.Arguments)
+	case "get_module_tree":
+		result = s.handleGetModuleTree(params.Arguments)
+	case "get_module_defaults":
+		result = s.handleGetModuleDefaults(params.Arguments)
+	case "get_module_locals":
+		result = s.handleGetModuleLocals(params.Arguments)
+	case "get_files":
+		result = s.handleGetFiles(params.Arguments)
+	case "find_modules_using_resource":
+		result = s.handleFindModulesUsingResource(params.Arguments)
+	case "list_all_examples":
+		result = s.handleListAllExamples(params.Arguments)
+	case "compare_examples":
+		result = s.handleCompareExamples(params.Arguments)
+	case "get_variable_usage":
+		result = s.handleGetVariableUsage(params.Arguments)
+	case "get_variable_schema":
+		result = s.handleGetVariableSchema(params.Arguments)
+	case "recently_updated_modules":
+		result = s.handleRecentlyUpdatedModules(params.Arguments)
+	default:
+		s.sendError(-32601, "Tool not found", msg.ID)
+		return
+	}
+
+	// The client has given up on a cancelled request and expects no answer.
+	if ctx.Err() != nil && s.baseContext().Err() == nil {
+		log.Printf("Dropping response to cancelled request %v", msg.ID)
+		return
+	}
+
+	response := Message{
+		JSONRPC: "2.0",
+		ID:      msg.ID,
+		Result:  result,
+	}
+	s.sendResponse(response)
+}
+
+func (s *Server) handleSyncModules(requestID, progressToken any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	job := s.startSyncJob("full_sync", requestID, func(ctx context.Context) (*indexer.SyncProgress, error) {
+		log.Println("Starting full repository sync (async job)...")
+		return s.syncer.SyncAll(s.withSyncProgress(ctx, progressToken))
+	})
+
+	return map[string]any{
+		"content": []map[string]any{
+			{
+				"type": "text",
+				"text": fmt.Sprintf("Full sync started.\nJob ID: %s\nUse `sync_status` with this job ID to monitor progress.", job.ID),
+			},
+		},
+	}
+}
+
+func (s *Server) handleSyncUpdatesModules(ctx context.Context, progressToken any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	log.Println("Starting incremental repository sync (updates only)...")
+
+	progress, err := s.syncer.SyncUpdates(s.withSyncProgress(ctx, progressToken))
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Sync failed: %v", err))
+	}
+
+	text := formatter.IncrementalSyncProgress(
+		progress.TotalRepos,
+		len(progress.UpdatedRepos),
+		progress.SkippedRepos,
+		progress.UpdatedRepos,
+		progress.Errors,
+	)
+
+	if summary := s.releaseSummaryIfUpdated(progress.UpdatedRepos); summary != "" {
+		if strings.TrimSpace(text) != "" {
+			text = strings.TrimSpace(text) + "\n\n" + summary
+		} else {
+			text = summary
+		}
+	}
+
+	return SuccessResponse(text)
+}
+
+// withSyncProgress makes a sync report each processed repository as an MCP
+// progress notification. Clients opt in by sending a progress token with the
+// tools/call request; without one ctx is returned unchanged. Notifications
+// stop once ctx is done, so a full sync reports for as long as its job runs
+// and not after the job is cancelled.
+func (s *Server) withSyncProgress(ctx context.Context, progressToken any) context.Context {
+	if progressToken == nil {
+		return ctx
+	}
+	return indexer.WithProgress(ctx, func(p indexer.SyncProgress) {
+		if ctx.Err() != nil {
+			return
+		}
+		params := map[string]any{
+			"progressToken": progressToken,
+			"progress":      p.ProcessedRepos,
+			"message":       fmt.Sprintf("Synced %s (%d/%d)", p.CurrentRepo, p.ProcessedRepos, p.TotalRepos),
+		}
+		if p.TotalRepos > 0 {
+			params["total"] = p.TotalRepos
+		}
+		s.sendNotification("notifications/progress", params)
+	})
+}
+
+func (s *Server) handleSyncStatus(args any) map[string]any {
+	statusArgs, err := UnmarshalArgs[struct {
+		JobID string `json:"job_id"`
+	}](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
 
-	text := formatter.SearchResults(searchArgs.Query, merged)
+	if statusArgs.JobID != "" {
+		job, ok := s.getJob(statusArgs.JobID)
+		if !ok {
+			return ErrorResponse(fmt.Sprintf("Job '%s' not found", statusArgs.JobID))
+		}
+
+		text := s.formatJobDetails(job)
+		return SuccessResponse(text)
+	}
+
+	jobs := s.listJobs()
+	text := s.formatJobList(jobs)
+	if status := s.indexStatus(); status != "" {
+		text = status + "\n" + text
+	}
+	return SuccessResponse(text)
+}
+
+// recentSyncRuns is how many past syncs sync_status lists.
+const recentSyncRuns = 5
+
+// indexStatus summarizes the local index and recent sync history, or
+// returns "" when the database cannot be opened.
+func (s *Server) indexStatus() string {
+	if err := s.ensureDB(); err != nil {
+		return ""
+	}
+	stats, err := s.db.GetIndexStats()
+	if err != nil {
+		log.Printf("Warning: failed to load index stats: %v", err)
+		return ""
+	}
+	runs, err := s.db.ListSyncRuns(recentSyncRuns)
+	if err != nil {
+		log.Printf("Warning: failed to load sync history: %v", err)
+	}
+	var size int64
+	if info, err := os.Stat(s.dbPath); err == nil {
+		size = info.Size()
+	}
+	return formatter.IndexStatus(stats, size, runs)
+}
+
+func (s *Server) handleListModules(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	listArgs, err := UnmarshalArgs[struct {
+		Provider string `json:"provider"`
+	}](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+	provider := strings.TrimSpace(listArgs.Provider)
+
+	var modules []database.Module
+	if provider != "" {
+		modules, err = s.db.ListModulesByProvider(provider)
+	} else {
+		modules, err = s.db.ListModules()
+	}
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading modules: %v", err))
+	}
+
+	if len(modules) == 0 {
+		if provider != "" {
+			return SuccessResponse(fmt.Sprintf("No modules using provider '%s' found.", provider))
+		}
+		return SuccessResponse("No modules found. Run sync_modules tool to fetch modules from GitHub.")
+	}
+
+	providers, err := s.db.GetProvidersByModule()
+	if err != nil {
+		log.Printf("Warning: failed to load module providers: %v", err)
+	}
+
+	text := formatter.ModuleList(modules, providers)
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleSearchModules(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	searchArgs, err := UnmarshalArgs[struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid search query")
+	}
+
+	if searchArgs.Limit == 0 {
+		searchArgs.Limit = 10
+	}
+
+	// Each variant ("key-vault", "key vault", "keyvault") is searched on its
+	// own; a module ranks by its best position in any variant's results, with
+	// the more literal variant winning ties.
+	variants := util.ExpandQueryVariants(searchArgs.Query)
+	best := make(map[int64]int)
+	var merged []database.Module
+	for _, v := range variants {
+		mods, err := s.db.SearchModules(v, searchArgs.Limit)
+		if err != nil {
+			continue
+		}
+		for pos, m := range mods {
+			if prev, ok := best[m.ID]; ok {
+				best[m.ID] = min(prev, pos)
+				continue
+			}
+			best[m.ID] = pos
+			merged = append(merged, m)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return best[merged[i].ID] < best[merged[j].ID]
+	})
+	if searchArgs.Limit > 0 && len(merged) > searchArgs.Limit {
+		merged = merged[:searchArgs.Limit]
+	}
+
+	terms := searchTerms(variants)
+	hits := make([]formatter.SearchHit, 0, len(merged))
+	for _, m := range merged {
+		topics, _ := s.db.GetModuleTopics(m.ID)
+		hits = append(hits, searchHit(m, topics, terms))
+	}
+
+	text := formatter.SearchResults(searchArgs.Query, hits)
 	return SuccessResponse(text)
 }
 
+// searchTerms splits the query variants into distinct lowercase words.
+func searchTerms(variants []string) []string {
+	seen := make(map[string]struct{})
+	var terms []string
+	for _, v := range variants {
+		for _, t := range strings.Fields(strings.ToLower(v)) {
+			if _, ok := seen[t]; ok {
+				continue
+			}
+			seen[t] = struct{}{}
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// searchHit records which fields of a module contain any of the search
+// terms. Topics are only shown when they matched.
+func searchHit(m database.Module, topics []string, terms []string) formatter.SearchHit {
+	hit := formatter.SearchHit{Module: m, Terms: terms}
+	contains := func(s string) bool {
+		lower := strings.ToLower(s)
+		for _, t := range terms {
+			if strings.Contains(lower, t) {
+				return true
+			}
+		}
+		return false
+	}
+	if contains(m.Name) {
+		hit.MatchedOn = append(hit.MatchedOn, "name")
+	}
+	if contains(m.Description) {
+		hit.MatchedOn = append(hit.MatchedOn, "description")
+	}
+	if contains(m.ReadmeContent) {
+		hit.MatchedOn = append(hit.MatchedOn, "readme")
+	}
+	if contains(strings.Join(topics, " ")) {
+		hit.MatchedOn = append(hit.MatchedOn, "topic")
+		hit.Topics = topics
+	}
+	return hit
+}
+
 func (s *Server) handleGetModuleInfo(args any) map[string]any {
 	if err := s.ensureDB(); err != nil {
 		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
@@ -674,26 +2019,45 @@ func (s *Server) handleGetModuleInfo(args any) map[string]any {
 
 	moduleArgs, err := UnmarshalArgs[struct {
 		ModuleName string `json:"module_name"`
+		Format     string `json:"format"`
 	}](args)
 	if err != nil {
 		return ErrorResponse("Error: Invalid module name")
 	}
 
+	format := strings.ToLower(strings.TrimSpace(moduleArgs.Format))
+	if format != "" && format != "markdown" && format != "json" {
+		return ErrorResponse(fmt.Sprintf("Unsupported format '%s'; use 'markdown' or 'json'", moduleArgs.Format))
+	}
+
 	module, err := s.resolveModule(moduleArgs.ModuleName)
 	if err != nil {
-		return ErrorResponse(fmt.Sprintf("Module '%s' not found", moduleArgs.ModuleName))
+		return moduleNotFound(moduleArgs.ModuleName, err)
 	}
 
 	variables, _ := s.db.GetModuleVariables(module.ID)
 	outputs, _ := s.db.GetModuleOutputs(module.ID)
 	resources, _ := s.db.GetModuleResources(module.ID)
 	files, _ := s.db.GetModuleFiles(module.ID)
+	topics, _ := s.db.GetModuleTopics(module.ID)
+
+	if format == "json" {
+		text, err := moduleInfoJSON(module, topics, variables, outputs, resources, files)
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Failed to encode module info: %v", err))
+		}
+		return SuccessResponse(text)
+	}
 
 	summary, _ := s.db.SummarizeModuleStructure(module.ID)
-	text := formatter.ModuleInfo(module, variables, outputs, resources, files)
+	providers, _ := s.db.GetModuleProviders(module.ID)
+	text := formatter.ModuleInfo(module, topics, variables, outputs, resources, files)
 	if summary != nil {
 		text += formatter.StructuralSummaryValues(summary.ResourceCount, summary.LifecycleCount, summary.ResourcesWithIgnoreChanges, summary.TopResourceTypes, summary.DynamicLabels)
 	}
+	if len(providers) > 0 {
+		text += formatter.ProvidersSection(providers)
+	}
 	return SuccessResponse(text)
 }
 
@@ -703,38 +2067,86 @@ func (s *Server) handleSearchCode(args any) map[string]any {
 	}
 
 	searchArgs, err := UnmarshalArgs[struct {
-		Query      string   `json:"query"`
-		Limit      int      `json:"limit"`
-		Kind       string   `json:"kind"`
-		TypePrefix string   `json:"type_prefix"`
-		Has        []string `json:"has"`
+		Query         string   `json:"query"`
+		Limit         int      `json:"limit"`
+		Kind          string   `json:"kind"`
+		TypePrefix    string   `json:"type_prefix"`
+		Has           []string `json:"has"`
+		Regex         bool     `json:"regex"`
+		MaxPerFile    int      `json:"max_matches_per_file"`
+		ModuleName    string   `json:"module_name"`
+		ContextLines  int      `json:"context_lines"`
+		FileType      string   `json:"file_type"`
+		CaseSensitive bool     `json:"case_sensitive"`
 	}](args)
 	if err != nil {
 		return ErrorResponse("Error: Invalid search query")
 	}
 
+	var moduleID int64
+	if name := strings.TrimSpace(searchArgs.ModuleName); name != "" {
+		module, err := s.resolveModule(name)
+		if err != nil {
+			return moduleNotFound(name, err)
+		}
+		moduleID = module.ID
+	}
+
 	if searchArgs.Limit == 0 {
 		searchArgs.Limit = 20
 	}
-
-	variants := util.ExpandQueryVariants(searchArgs.Query)
-	if len(variants) == 0 {
-		variants = []string{searchArgs.Query}
+	if searchArgs.MaxPerFile <= 0 {
+		searchArgs.MaxPerFile = 3
+	}
+	if searchArgs.ContextLines <= 0 {
+		searchArgs.ContextLines = defaultSearchContextLines
 	}
+	searchArgs.ContextLines = min(searchArgs.ContextLines, maxSearchContextLines)
 
 	seen := make(map[int64]struct{})
-	var merged []database.ModuleFile
-	var files []database.ModuleFile
-	if len(variants) == 1 {
-		files, _ = s.db.SearchFiles(variants[0], searchArgs.Limit)
+	var merged []database.FileSearchHit
+	var files []database.FileSearchHit
+	lineMatch := formatter.SubstringMatcher(searchArgs.Query)
+	switch {
+	case searchArgs.Regex:
+		re, err := compileSearchPattern(searchArgs.Query)
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Invalid regex pattern: %v", err))
+		}
+		lineMatch = regexLineMatcher(re)
+	case searchArgs.CaseSensitive:
+		lineMatch = formatter.CaseSensitiveMatcher(searchArgs.Query)
+	}
+
+	// Regex and case-sensitive queries can't be answered by the
+	// case-insensitive text index, so every file is scanned line by line.
+	if searchArgs.Regex || searchArgs.CaseSensitive {
+		var all []database.ModuleFile
+		if moduleID != 0 {
+			all, err = s.db.GetModuleFiles(moduleID)
+		} else {
+			all, err = s.db.ListAllFiles()
+		}
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Search failed: %v", err))
+		}
+		for _, f := range all {
+			if !matchesFileType(f.FileName, searchArgs.FileType) {
+				continue
+			}
+			if hasMatchingLine(f.Content, lineMatch) {
+				files = append(files, database.FileSearchHit{ModuleFile: f})
+			}
+		}
 	} else {
-		parts := make([]string, 0, len(variants))
-		for _, v := range variants {
-			escaped := strings.ReplaceAll(v, "\"", "\"\"")
-			parts = append(parts, fmt.Sprintf("\"%s\"", escaped))
+		variants := util.ExpandQueryVariants(searchArgs.Query)
+		if len(variants) == 0 {
+			variants = []string{searchArgs.Query}
+		}
+		files, err = s.db.SearchFiles(variants, moduleID, searchArgs.FileType, searchArgs.Limit)
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Search failed: %v", err))
 		}
-		match := strings.Join(parts, " OR ")
-		files, _ = s.db.SearchFilesFTS(match, searchArgs.Limit)
 	}
 
 	for _, f := range files {
@@ -766,10 +2178,64 @@ func (s *Server) handleSearchCode(args any) map[string]any {
 		return "unknown"
 	}
 
-	text := formatter.CodeSearchResults(searchArgs.Query, merged, getModuleName)
+	text := formatter.CodeSearchResults(searchArgs.Query, merged, getModuleName, lineMatch, searchArgs.MaxPerFile, searchArgs.ContextLines)
 	return SuccessResponse(text)
 }
 
+// matchesFileType reports whether a file passes the search_code file_type
+// filter: an extension such as ".tf" or an exact file name, ignoring case.
+func matchesFileType(fileName, fileType string) bool {
+	fileType = strings.ToLower(strings.TrimSpace(fileType))
+	if fileType == "" {
+		return true
+	}
+	fileName = strings.ToLower(fileName)
+	if strings.HasPrefix(fileType, ".") {
+		return strings.HasSuffix(fileName, fileType)
+	}
+	return fileName == fileType
+}
+
+// Lines of context shown around each search_code match.
+const (
+	defaultSearchContextLines = 2
+	maxSearchContextLines     = 20
+)
+
+// maxSearchPatternLength bounds regex queries. Go's RE2 engine matches in
+// linear time, so there is no catastrophic backtracking to guard against;
+// the cap keeps compiled programs and per-line matching cost small.
+const maxSearchPatternLength = 256
+
+func compileSearchPattern(pattern string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("pattern is empty")
+	}
+	if len(pattern) > maxSearchPatternLength {
+		return nil, fmt.Errorf("pattern is longer than %d characters", maxSearchPatternLength)
+	}
+	return regexp.Compile(pattern)
+}
+
+func regexLineMatcher(re *regexp.Regexp) formatter.LineMatcher {
+	return func(line string) (int, int, bool) {
+		loc := re.FindStringIndex(line)
+		if loc == nil {
+			return 0, 0, false
+		}
+		return loc[0], loc[1], true
+	}
+}
+
+func hasMatchingLine(content string, match formatter.LineMatcher) bool {
+	for line := range strings.SplitSeq(content, "\n") {
+		if _, _, ok := match(line); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Server) handleGetFileContent(args any) map[string]any {
 	if err := s.ensureDB(); err != nil {
 		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
@@ -785,7 +2251,7 @@ func (s *Server) handleGetFileContent(args any) map[string]any {
 
 	module, err := s.resolveModule(fileArgs.ModuleName)
 	if err != nil {
-		return ErrorResponse(fmt.Sprintf("Module '%s' not found", fileArgs.ModuleName))
+		return moduleNotFound(fileArgs.ModuleName, err)
 	}
 	file, err := s.db.GetFile(module.Name, fileArgs.FilePath)
 	if err != nil {
@@ -796,6 +2262,63 @@ func (s *Server) handleGetFileContent(args any) map[string]any {
 	return SuccessResponse(text)
 }
 
+// maxFilesPerCall caps get_files so a single response stays readable.
+const maxFilesPerCall = 20
+
+func (s *Server) handleGetFiles(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	fileArgs, err := UnmarshalArgs[struct {
+		ModuleName string   `json:"module_name"`
+		FilePaths  []string `json:"file_paths"`
+	}](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+
+	var paths []string
+	seen := make(map[string]struct{})
+	for _, p := range fileArgs.FilePaths {
+		p = strings.TrimSpace(p)
+		if _, ok := seen[p]; ok || p == "" {
+			continue
+		}
+		seen[p] = struct{}{}
+		paths = append(paths, p)
+	}
+	if len(paths) == 0 {
+		return ErrorResponse("file_paths must list at least one file")
+	}
+	if len(paths) > maxFilesPerCall {
+		return ErrorResponse(fmt.Sprintf("At most %d files can be requested at once, got %d", maxFilesPerCall, len(paths)))
+	}
+
+	module, err := s.resolveModule(fileArgs.ModuleName)
+	if err != nil {
+		return moduleNotFound(fileArgs.ModuleName, err)
+	}
+
+	var (
+		files   []database.ModuleFile
+		missing []string
+	)
+	for _, p := range paths {
+		file, err := s.db.GetFile(module.Name, p)
+		if err != nil {
+			missing = append(missing, p)
+			continue
+		}
+		files = append(files, *file)
+	}
+	if len(files) == 0 {
+		return ErrorResponse(fmt.Sprintf("None of the requested files were found in module '%s': %s", module.Name, strings.Join(missing, ", ")))
+	}
+
+	return SuccessResponse(formatter.FileContents(module.Name, files, missing))
+}
+
 func (s *Server) handleExtractVariableDefinition(args any) map[string]any {
 	if err := s.ensureDB(); err != nil {
 		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
@@ -811,50 +2334,98 @@ func (s *Server) handleExtractVariableDefinition(args any) map[string]any {
 
 	module, err := s.resolveModule(varArgs.ModuleName)
 	if err != nil {
-		return ErrorResponse(fmt.Sprintf("Module '%s' not found", varArgs.ModuleName))
+		return moduleNotFound(varArgs.ModuleName, err)
 	}
-	file, err := s.db.GetFile(module.Name, "variables.tf")
-	if err != nil {
-		return ErrorResponse(fmt.Sprintf("variables.tf not found in module '%s'", module.Name))
+	var (
+		variableBlock string
+		validations   []database.VariableValidation
+	)
+	if variables, err := s.db.GetModuleVariables(module.ID); err == nil {
+		// The module's own declaration wins over an example redeclaring
+		// the same name; example-only variables are still found.
+		for _, v := range append(ownVariables(module, variables), variables...) {
+			if v.Name == varArgs.VariableName {
+				variableBlock = strings.TrimSpace(v.Block)
+				validations = v.Validations
+				break
+			}
+		}
 	}
 
-	variableBlock := extractVariableBlock(file.Content, varArgs.VariableName)
+	// Databases indexed before block sources were stored fall back to
+	// re-parsing variables.tf.
+	if variableBlock == "" {
+		file, err := s.db.GetFile(module.Name, "variables.tf")
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("variables.tf not found in module '%s'", module.Name))
+		}
+		variableBlock = extractVariableBlock(file.Content, varArgs.VariableName)
+	}
 	if variableBlock == "" {
 		return ErrorResponse(fmt.Sprintf("Variable '%s' not found in %s", varArgs.VariableName, varArgs.ModuleName))
 	}
 
-	text := formatter.VariableDefinition(module.Name, varArgs.VariableName, variableBlock)
+	text := formatter.VariableDefinition(module.Name, varArgs.VariableName, variableBlock, validations)
 	return SuccessResponse(text)
 }
 
+// extractVariableBlock returns the source of a variable block, using the
+// parsed block range so braces inside strings, heredocs or comments cannot
+// cut it short. Syntax errors elsewhere in the file are tolerated through the
+// recovered body; when the block itself does not parse, the brace scan is
+// used instead.
 func extractVariableBlock(content, variableName string) string {
-	variablePattern := fmt.Sprintf(`variable "%s"`, variableName)
-	startIdx := strings.Index(content, variablePattern)
+	file, diags := hclsyntax.ParseConfig([]byte(content), "temp.tf", hcl.Pos{Line: 1, Column: 1})
+	if body, ok := file.Body.(*hclsyntax.Body); ok {
+		for _, block := range body.Blocks {
+			if block.Type == "variable" && len(block.Labels) == 1 && block.Labels[0] == variableName {
+				if rng := block.Range(); !hasErrorWithin(diags, rng) {
+					return sliceRange(content, rng)
+				}
+				break
+			}
+		}
+	}
+	if diags.HasErrors() {
+		return scanVariableBlock(content, variableName)
+	}
+	return ""
+}
+
+// hasErrorWithin reports whether any error diagnostic starts inside rng.
+func hasErrorWithin(diags hcl.Diagnostics, rng hcl.Range) bool {
+	for _, d := range diags {
+		if d.Severity == hcl.DiagError && d.Subject != nil &&
+			d.Subject.Start.Byte >= rng.Start.Byte && d.Subject.Start.Byte <= rng.End.Byte {
+			return true
+		}
+	}
+	return false
+}
+
+// scanVariableBlock finds a variable block by counting braces from its
+// header, for files the HCL parser cannot recover.
+func scanVariableBlock(content, variableName string) string {
+	startIdx := strings.Index(content, fmt.Sprintf(`variable "%s"`, variableName))
 	if startIdx == -1 {
 		return ""
 	}
 
 	braceCount := 0
 	inBlock := false
-	endIdx := startIdx
-
-Loop:
 	for i := startIdx; i < len(content); i++ {
-		char := content[i]
-		switch char {
+		switch content[i] {
 		case '{':
 			braceCount++
 			inBlock = true
 		case '}':
 			braceCount--
 			if inBlock && braceCount == 0 {
-				endIdx = i + 1
-				break Loop
+				return content[startIdx : i+1]
 			}
 		}
 	}
-
-	return content[startIdx:endIdx]
+	return content[startIdx:]
 }
 
 func (s *Server) handleComparePatternAcrossModules(args any) map[string]any {
@@ -897,6 +2468,58 @@ func (s *Server) handleComparePatternAcrossModules(args any) map[string]any {
 	return SuccessResponse(text)
 }
 
+func (s *Server) handleCountPatternAcrossModules(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	patternArgs, err := UnmarshalArgs[struct {
+		Pattern  string `json:"pattern"`
+		FileType string `json:"file_type"`
+	}](args)
+	if err != nil || strings.TrimSpace(patternArgs.Pattern) == "" {
+		return ErrorResponse("pattern is required")
+	}
+
+	modules, err := s.db.ListModules()
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading modules: %v", err))
+	}
+
+	names := make(map[int64]string, len(modules))
+	for _, m := range modules {
+		names[m.ID] = m.Name
+	}
+
+	results := s.findPatternMatches(modules, patternArgs.Pattern, patternArgs.FileType)
+	byModule := make(map[int64]*formatter.PatternCount)
+	files := make(map[int64]map[string]struct{})
+	for _, r := range results {
+		c, ok := byModule[r.ModuleID]
+		if !ok {
+			c = &formatter.PatternCount{ModuleName: names[r.ModuleID]}
+			byModule[r.ModuleID] = c
+			files[r.ModuleID] = make(map[string]struct{})
+		}
+		c.Matches++
+		files[r.ModuleID][r.FileName] = struct{}{}
+	}
+
+	counts := make([]formatter.PatternCount, 0, len(byModule))
+	for id, c := range byModule {
+		c.Files = len(files[id])
+		counts = append(counts, *c)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Matches != counts[j].Matches {
+			return counts[i].Matches > counts[j].Matches
+		}
+		return counts[i].ModuleName < counts[j].ModuleName
+	})
+
+	return SuccessResponse(formatter.PatternCounts(patternArgs.Pattern, counts, len(results)))
+}
+
 func (s *Server) handleAnalyzeCodeRelationships(args any) map[string]any {
 	if err := s.ensureDB(); err != nil {
 		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
@@ -932,7 +2555,7 @@ func (s *Server) handleAnalyzeCodeRelationships(args any) map[string]any {
 	if module == nil && moduleName != "" {
 		module, err = s.resolveModule(moduleName)
 		if err != nil {
-			return ErrorResponse(fmt.Sprintf("Module '%s' not found", moduleName))
+			return moduleNotFound(moduleName, err)
 		}
 	}
 
@@ -1356,6 +2979,7 @@ func (s *Server) findPatternMatches(modules []database.Module, pattern, fileType
 					displayName = fmt.Sprintf("%s #%d", module.Name, i+1)
 				}
 				results = append(results, formatter.PatternMatch{
+					ModuleID:   module.ID,
 					ModuleName: displayName,
 					FileName:   file.FileName,
 					Match:      match.Code,
@@ -1455,6 +3079,7 @@ func (s *Server) findPatternMatchesIndexed(pattern, fileType string) []formatter
 		code := strings.TrimSpace(f.Content[start:end])
 
 		results = append(results, formatter.PatternMatch{
+			ModuleID:   module.ID,
 			ModuleName: module.Name,
 			FileName:   f.FileName,
 			Match:      code,
@@ -1477,30 +3102,13 @@ func extractASTPatternMatches(content, pattern string) []astMatch {
 		return nil
 	}
 
-	parser := hclparse.NewParser()
-	file, diags := parser.ParseHCL([]byte(content), "temp.tf")
-	if diags.HasErrors() {
-		return nil
-	}
-	body, ok := file.Body.(*hclsyntax.Body)
-	if !ok {
+	body := parseHCLBody(content)
+	if body == nil {
 		return nil
 	}
 
 	sliceBlock := func(b *hclsyntax.Block) string {
-		rng := b.Range()
-		start := rng.Start.Byte
-		end := rng.End.Byte
-		if start < 0 {
-			start = 0
-		}
-		if end > len(content) {
-			end = len(content)
-		}
-		if end < start {
-			end = start
-		}
-		return strings.TrimSpace(content[start:end])
+		return sliceRange(content, b.Range())
 	}
 
 	var out []astMatch
@@ -1636,60 +3244,105 @@ func summarizeAttributes(kind string, bl *hclsyntax.Block) string {
 	return fmt.Sprintf("%s attributes: %s", kind, strings.Join(keys, ", "))
 }
 
+// extractPatternMatches returns the code around each literal occurrence of
+// pattern. When the match sits on the first line of a block or attribute the
+// whole construct is returned, using the parsed HCL ranges; otherwise just
+// the matching line.
 func extractPatternMatches(content, pattern string) []string {
+	if pattern == "" {
+		return nil
+	}
+
+	body := parseHCLBody(content)
 	var matches []string
-	searchContent := content
-	offset := 0
+	seen := make(map[[2]int]struct{})
 
-	for {
-		idx := strings.Index(searchContent, pattern)
+	for offset := 0; offset < len(content); {
+		idx := strings.Index(content[offset:], pattern)
 		if idx == -1 {
 			break
 		}
+		idx += offset
+		offset = idx + len(pattern)
 
-		actualIdx := offset + idx
-		startIdx := actualIdx
-		for startIdx > 0 && content[startIdx] != '\n' {
-			startIdx--
+		start := strings.LastIndexByte(content[:idx], '\n') + 1
+		end := lineEnd(content, idx)
+		if body != nil {
+			if rng, ok := syntaxRangeOnLine(body, start, end); ok {
+				end = max(end, lineEnd(content, rng.End.Byte))
+			}
 		}
 
-		endIdx := findBlockEnd(content, actualIdx)
-		if endIdx > startIdx {
-			matches = append(matches, strings.TrimSpace(content[startIdx:endIdx]))
+		key := [2]int{start, end}
+		if _, dup := seen[key]; dup {
+			continue
 		}
-
-		offset = actualIdx + len(pattern)
-		if offset >= len(content) {
-			break
+		seen[key] = struct{}{}
+		if code := strings.TrimSpace(content[start:end]); code != "" {
+			matches = append(matches, code)
 		}
-		searchContent = content[offset:]
 	}
 
 	return matches
 }
 
-func findBlockEnd(content string, startPos int) int {
-	braceCount := 0
-	inBlock := false
-
-	for i := startPos; i < len(content); i++ {
-		char := content[i]
-		switch char {
-		case '{':
-			braceCount++
-			inBlock = true
-		case '}':
-			braceCount--
-			if inBlock && braceCount == 0 {
-				endIdx := i + 1
-				for endIdx < len(content) && content[endIdx] != '\n' {
-					endIdx++
-				}
-				return endIdx
+// syntaxRangeOnLine returns the range of the outermost block or attribute
+// that starts within [lineStart, lineEnd).
+func syntaxRangeOnLine(body *hclsyntax.Body, lineStart, lineEnd int) (hcl.Range, bool) {
+	onLine := func(rng hcl.Range) bool {
+		return rng.Start.Byte >= lineStart && rng.Start.Byte < lineEnd
+	}
+	for _, attr := range body.Attributes {
+		if onLine(attr.SrcRange) {
+			return attr.SrcRange, true
+		}
+	}
+	for _, block := range body.Blocks {
+		rng := block.Range()
+		if onLine(rng) {
+			return rng, true
+		}
+		if rng.Start.Byte < lineStart && rng.End.Byte > lineStart && block.Body != nil {
+			if inner, ok := syntaxRangeOnLine(block.Body, lineStart, lineEnd); ok {
+				return inner, true
 			}
 		}
 	}
-	return startPos
+	return hcl.Range{}, false
+}
+
+func lineEnd(content string, pos int) int {
+	pos = min(max(pos, 0), len(content))
+	if i := strings.IndexByte(content[pos:], '\n'); i >= 0 {
+		return pos + i
+	}
+	return len(content)
+}
+
+// parseHCLBody parses Terraform source, returning nil when it does not parse
+// cleanly.
+func parseHCLBody(content string) *hclsyntax.Body {
+	file, diags := hclsyntax.ParseConfig([]byte(content), "temp.tf", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+	return body
+}
+
+// sliceRange returns the trimmed source text covered by rng, clamped to the
+// bounds of content.
+func sliceRange(content string, rng hcl.Range) string {
+	return sliceSource(content, rng.Start.Byte, rng.End.Byte)
+}
+
+func sliceSource(content string, start, end int) string {
+	start = min(max(start, 0), len(content))
+	end = min(max(end, start), len(content))
+	return strings.TrimSpace(content[start:end])
 }
 
 func paginateResults(results []formatter.PatternMatch, offset, limit int) []formatter.PatternMatch {
@@ -1716,7 +3369,7 @@ func (s *Server) handleListModuleExamples(args any) map[string]any {
 
 	module, err := s.resolveModule(moduleArgs.ModuleName)
 	if err != nil {
-		return ErrorResponse(fmt.Sprintf("Module '%s' not found", moduleArgs.ModuleName))
+		return moduleNotFound(moduleArgs.ModuleName, err)
 	}
 
 	files, err := s.db.GetModuleFiles(module.ID)
@@ -1725,7 +3378,7 @@ func (s *Server) handleListModuleExamples(args any) map[string]any {
 	}
 
 	exampleMap := buildExampleMap(files)
-	text := formatter.ExampleList(module.Name, exampleMap)
+	text := formatter.ExampleList(module.Name, exampleMap, exampleDescriptions(files))
 	return SuccessResponse(text)
 }
 
@@ -1743,6 +3396,32 @@ func buildExampleMap(files []database.ModuleFile) map[string][]string {
 	return exampleMap
 }
 
+// exampleReadme finds the README directly inside examples/<name>/.
+func exampleReadme(files []database.ModuleFile, exampleName string) (database.ModuleFile, bool) {
+	for _, file := range files {
+		if strings.EqualFold(file.FilePath, "examples/"+exampleName+"/README.md") {
+			return file, true
+		}
+	}
+	return database.ModuleFile{}, false
+}
+
+// exampleDescriptions maps example names to the first paragraph of their
+// README, for examples that have one.
+func exampleDescriptions(files []database.ModuleFile) map[string]string {
+	descriptions := make(map[string]string)
+	for _, file := range files {
+		parts := strings.Split(file.FilePath, "/")
+		if len(parts) != 3 || parts[0] != "examples" || !strings.EqualFold(parts[2], "README.md") {
+			continue
+		}
+		if summary := readmeFirstParagraph(file.Content); summary != "" {
+			descriptions[parts[1]] = summary
+		}
+	}
+	return descriptions
+}
+
 func (s *Server) handleGetExampleContent(args any) map[string]any {
 	if err := s.ensureDB(); err != nil {
 		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
@@ -1758,7 +3437,7 @@ func (s *Server) handleGetExampleContent(args any) map[string]any {
 
 	module, err := s.resolveModule(exampleArgs.ModuleName)
 	if err != nil {
-		return ErrorResponse(fmt.Sprintf("Module '%s' not found", exampleArgs.ModuleName))
+		return moduleNotFound(exampleArgs.ModuleName, err)
 	}
 
 	files, err := s.db.GetModuleFiles(module.ID)
@@ -1771,8 +3450,17 @@ func (s *Server) handleGetExampleContent(args any) map[string]any {
 		return ErrorResponse(fmt.Sprintf("Example '%s' not found in module '%s'", exampleArgs.ExampleName, exampleArgs.ModuleName))
 	}
 
+	// The example README is rendered ahead of the code rather than as one of
+	// the files.
+	readme, hasReadme := exampleReadme(exampleFiles, exampleArgs.ExampleName)
+	if hasReadme {
+		exampleFiles = slices.DeleteFunc(exampleFiles, func(f database.ModuleFile) bool {
+			return f.FilePath == readme.FilePath
+		})
+	}
+
 	sortedFiles := sortExampleFiles(exampleFiles)
-	text := formatter.ExampleContent(module.Name, exampleArgs.ExampleName, sortedFiles)
+	text := formatter.ExampleContent(module.Name, exampleArgs.ExampleName, readme.Content, sortedFiles)
 	return SuccessResponse(text)
 }
 
@@ -1803,13 +3491,80 @@ func sortExampleFiles(files []database.ModuleFile) []database.ModuleFile {
 	return sortedFiles
 }
 
-func (s *Server) startSyncJob(jobType string, runner func() (*indexer.SyncProgress, error)) *SyncJob {
+// baseContext is the context of the running server, which is cancelled when
+// the server shuts down.
+func (s *Server) baseContext() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// handleCancelled stops the request a notifications/cancelled message refers
+// to, along with any sync jobs it started.
+func (s *Server) handleCancelled(msg Message) {
+	var params struct {
+		RequestID any    `json:"requestId"`
+		Reason    string `json:"reason"`
+	}
+	paramsBytes, err := json.Marshal(msg.Params)
+	if err == nil {
+		err = json.Unmarshal(paramsBytes, &params)
+	}
+	if err != nil || params.RequestID == nil {
+		log.Println("Request cancelled")
+		return
+	}
+
+	log.Printf("Request %v cancelled: %s", params.RequestID, params.Reason)
+
+	requestKey := fmt.Sprint(params.RequestID)
+	s.requestsMu.Lock()
+	if cancel, ok := s.requests[requestKey]; ok {
+		cancel()
+	}
+	s.requestsMu.Unlock()
+
+	s.jobsMutex.RLock()
+	defer s.jobsMutex.RUnlock()
+	for _, job := range s.jobs {
+		if job.Status == "running" && job.cancel != nil && fmt.Sprint(job.RequestID) == requestKey {
+			log.Printf("Cancelling sync job %s", job.ID)
+			job.cancel()
+		}
+	}
+}
+
+// beginRequest registers an in-flight tools/call so handleCancelled can stop
+// it. The returned func unregisters the request and releases its context.
+func (s *Server) beginRequest(id any) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(s.baseContext())
+	if id == nil {
+		return ctx, cancel
+	}
+
+	key := fmt.Sprint(id)
+	s.requestsMu.Lock()
+	s.requests[key] = cancel
+	s.requestsMu.Unlock()
+	return ctx, func() {
+		s.requestsMu.Lock()
+		delete(s.requests, key)
+		s.requestsMu.Unlock()
+		cancel()
+	}
+}
+
+func (s *Server) startSyncJob(jobType string, requestID any, runner func(ctx context.Context) (*indexer.SyncProgress, error)) *SyncJob {
+	ctx, cancel := context.WithCancel(s.baseContext())
 	jobID := fmt.Sprintf("%s-%d", jobType, time.Now().UnixNano())
 	job := &SyncJob{
 		ID:        jobID,
 		Type:      jobType,
 		Status:    "running",
 		StartedAt: time.Now(),
+		RequestID: requestID,
+		cancel:    cancel,
 	}
 
 	s.jobsMutex.Lock()
@@ -1818,6 +3573,7 @@ func (s *Server) startSyncJob(jobType string, runner func() (*indexer.SyncProgre
 
 	go func() {
 		headline := fmt.Sprintf("Sync job %s (%s)", jobID, jobType)
+		defer cancel()
 		defer func() {
 			if r := recover(); r != nil {
 				errMsg := fmt.Sprintf("panic: %v", r)
@@ -1826,7 +3582,12 @@ func (s *Server) startSyncJob(jobType string, runner func() (*indexer.SyncProgre
 			}
 		}()
 
-		progress, err := runner()
+		progress, err := runner(ctx)
+		if err != nil && ctx.Err() != nil {
+			log.Printf("%s cancelled", headline)
+			s.completeJobCancelled(jobID, progress, err.Error())
+			return
+		}
 		if err != nil {
 			log.Printf("%s failed: %v", headline, err)
 			s.completeJobWithError(jobID, err.Error())
@@ -1851,6 +3612,20 @@ func (s *Server) completeJobWithError(jobID, errMsg string) {
 	s.jobsMutex.Unlock()
 }
 
+// completeJobCancelled keeps the partial progress of an interrupted job so
+// sync_status can show how far it got.
+func (s *Server) completeJobCancelled(jobID string, progress *indexer.SyncProgress, errMsg string) {
+	now := time.Now()
+	s.jobsMutex.Lock()
+	if job, ok := s.jobs[jobID]; ok {
+		job.Status = "cancelled"
+		job.Progress = progress
+		job.Error = errMsg
+		job.CompletedAt = &now
+	}
+	s.jobsMutex.Unlock()
+}
+
 func (s *Server) completeJobWithSuccess(jobID string, progress *indexer.SyncProgress) {
 	now := time.Now()
 	s.jobsMutex.Lock()
@@ -1920,6 +3695,31 @@ func (s *Server) sendResponse(response Message) {
 		return
 	}
 
+	s.writeMessage(data)
+}
+
+// sendNotification writes a JSON-RPC notification, which carries no ID and
+// expects no response. It is safe to call from sync jobs.
+func (s *Server) sendNotification(method string, params any) {
+	data, err := json.Marshal(Message{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		log.Printf("Failed to marshal notification: %v", err)
+		return
+	}
+
+	s.writeMessage(data)
+}
+
+// writeMessage emits one line of output. Responses and notifications from
+// background jobs share the writer, so writes are serialized.
+func (s *Server) writeMessage(data []byte) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	if s.writer == nil {
 		log.Printf("No writer configured, dropping response: %s", string(data))
 		return
@@ -1944,6 +3744,33 @@ func (s *Server) sendError(code int, message string, id any) {
 	s.sendResponse(response)
 }
 
+// moduleNotFoundError carries the closest module names so the caller can
+// offer them when a lookup fails.
+type moduleNotFoundError struct {
+	name        string
+	suggestions []string
+}
+
+func (e *moduleNotFoundError) Error() string {
+	if len(e.suggestions) == 0 {
+		return fmt.Sprintf("Module '%s' not found", e.name)
+	}
+	return fmt.Sprintf("Module '%s' not found. Did you mean: %s?", e.name, strings.Join(e.suggestions, ", "))
+}
+
+// moduleNotFound reports a failed resolveModule lookup, including the
+// suggested names when there are any.
+func moduleNotFound(name string, err error) map[string]any {
+	var notFound *moduleNotFoundError
+	if errors.As(err, &notFound) {
+		return ErrorResponse(notFound.Error())
+	}
+	return ErrorResponse(fmt.Sprintf("Module '%s' not found", name))
+}
+
+// resolveModule finds a module by exact name, alias, normalized name (with
+// and without the terraform-azure- prefix), a close spelling, and finally
+// full-text search. Ambiguous spellings fail with up to three suggestions.
 func (s *Server) resolveModule(nameOrAlias string) (*database.Module, error) {
 	if m, err := s.db.GetModule(nameOrAlias); err == nil {
 		return m, nil
@@ -1951,13 +3778,76 @@ func (s *Server) resolveModule(nameOrAlias string) (*database.Module, error) {
 	if m, err := s.db.ResolveModuleByAlias(nameOrAlias); err == nil {
 		return m, nil
 	}
+
+	normalized := util.NormalizeQuery(nameOrAlias)
+	if normalized != "" && !strings.Contains(nameOrAlias, "//") {
+		candidates := []string{normalized}
+		if s.prefix != "" && !strings.HasPrefix(normalized, s.prefix) {
+			candidates = append(candidates, s.prefix+normalized)
+		}
+		for _, candidate := range candidates {
+			if m, err := s.db.GetModule(candidate); err == nil {
+				return m, nil
+			}
+		}
+	}
+
 	if m, err := s.db.ResolveModuleByAliasPrefix(nameOrAlias); err == nil {
 		return m, nil
 	}
+
+	modules, _ := s.db.ListModuleNames()
+	closest := closestModules(normalized, s.prefix, modules)
+	if len(closest) == 1 || (len(closest) > 1 && closest[0].distance < closest[1].distance) {
+		if best := closest[0]; best.distance <= fuzzyThreshold(normalized) {
+			if m, err := s.db.GetModuleByID(best.module.ID); err == nil {
+				return m, nil
+			}
+		}
+	}
+
 	mods, err := s.db.SearchModules(nameOrAlias, 1)
 	if err == nil && len(mods) > 0 {
 		m := mods[0]
 		return &m, nil
 	}
-	return nil, fmt.Errorf("module not found for '%s'", nameOrAlias)
+
+	notFound := &moduleNotFoundError{name: nameOrAlias}
+	for i, c := range closest {
+		if i == 3 || c.distance > 2*fuzzyThreshold(normalized) {
+			break
+		}
+		notFound.suggestions = append(notFound.suggestions, c.module.Name)
+	}
+	return nil, notFound
+}
+
+type moduleDistance struct {
+	module   database.Module
+	distance int
+}
+
+// closestModules ranks modules by edit distance to query, comparing against
+// both the full name and the name without the repository prefix.
+func closestModules(query, prefix string, modules []database.Module) []moduleDistance {
+	if query == "" {
+		return nil
+	}
+	ranked := make([]moduleDistance, 0, len(modules))
+	for _, m := range modules {
+		name := strings.ToLower(m.Name)
+		distance := util.Levenshtein(query, name)
+		if short, ok := strings.CutPrefix(name, prefix); ok && prefix != "" && !strings.Contains(short, "//") {
+			distance = min(distance, util.Levenshtein(query, short))
+		}
+		ranked = append(ranked, moduleDistance{module: m, distance: distance})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].distance < ranked[j].distance })
+	return ranked
+}
+
+// fuzzyThreshold is the largest edit distance accepted as a typo: one edit
+// per four characters, at least one.
+func fuzzyThreshold(query string) int {
+	return max(1, len(query)/4)
 }
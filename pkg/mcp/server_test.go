@@ -0,0 +1,40 @@
+package mcp
+
+import "testing"
+
+func TestExtractVariableBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "brace in default",
+			content: "variable \"name\" {\n  default = \"}\"\n}\n",
+			want:    "variable \"name\" {\n  default = \"}\"\n}",
+		},
+		{
+			name:    "error in another block",
+			content: "variable \"name\" {\n  type = string\n}\n\nresource \"x\" \"y\" {\n  a = [1,\n}\n",
+			want:    "variable \"name\" {\n  type = string\n}",
+		},
+		{
+			name:    "error in the block itself",
+			content: "variable \"name\" {\n  type = \n}\n",
+			want:    "variable \"name\" {\n  type = \n}",
+		},
+		{
+			name:    "missing",
+			content: "variable \"other\" {}\n",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractVariableBlock(tt.content, "name"); got != tt.want {
+				t.Errorf("extractVariableBlock() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,613 @@
+package mcp
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/formatter"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/schema"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/util"
+)
+
+type typeListingArgs struct {
+	Provider string `json:"provider"`
+	MinCount int    `json:"min_count"`
+}
+
+type moduleSimilarityArgs struct {
+	ModuleA string `json:"module_a"`
+	ModuleB string `json:"module_b"`
+}
+
+// similarityWeights sets how much each overlap contributes to the score.
+var similarityWeights = []struct {
+	name   string
+	weight float64
+}{
+	{"resource types", 0.5},
+	{"variable names", 0.3},
+	{"tags", 0.2},
+}
+
+type listResourcesArgs struct {
+	ModuleName   string `json:"module_name"`
+	Provider     string `json:"provider"`
+	ResourceType string `json:"resource_type"`
+	Limit        int    `json:"limit"`
+	Offset       int    `json:"offset"`
+}
+
+type orgArgs struct {
+	Org string `json:"org"`
+}
+
+func (s *Server) handleListModulesByOrg(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[orgArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+
+	org := strings.TrimSpace(params.Org)
+	var modules []database.Module
+	if org == "" {
+		modules, err = s.db.ListModules()
+	} else {
+		modules, err = s.db.ListModulesByOrg(org)
+	}
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading modules: %v", err))
+	}
+
+	if len(modules) == 0 {
+		if org != "" {
+			return SuccessResponse(fmt.Sprintf("No modules indexed from org '%s'.", org))
+		}
+		return SuccessResponse("No modules found. Run sync_modules tool to fetch modules from GitHub.")
+	}
+
+	groups := make(map[string][]string)
+	var orgs []string
+	for _, m := range modules {
+		key := m.Org
+		if key == "" {
+			key = "(unknown)"
+		}
+		if _, ok := groups[key]; !ok {
+			orgs = append(orgs, key)
+		}
+		groups[key] = append(groups[key], m.Name)
+	}
+	sort.Strings(orgs)
+
+	text := formatter.ModulesByOrg(orgs, groups)
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleListResources(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[listResourcesArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+
+	filter := database.ResourceFilter{
+		Provider:   strings.ToLower(strings.TrimSpace(params.Provider)),
+		TypePrefix: strings.TrimSpace(params.ResourceType),
+		Limit:      params.Limit,
+		Offset:     max(0, params.Offset),
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+
+	scope := "all modules"
+	if name := strings.TrimSpace(params.ModuleName); name != "" {
+		module, err := s.resolveModule(name)
+		if err != nil {
+			return moduleNotFound(name, err)
+		}
+		filter.ModuleID = module.ID
+		scope = module.Name
+	}
+
+	items, total, err := s.db.ListResources(filter)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading resources: %v", err))
+	}
+
+	text := formatter.ResourceInventory(scope, items, filter.Offset, filter.Limit, total)
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleListDataSourceTypes(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[typeListingArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+
+	if params.MinCount <= 0 {
+		params.MinCount = 1
+	}
+
+	provider := strings.ToLower(strings.TrimSpace(params.Provider))
+	usages, err := s.db.ListDataSourceTypes(provider, params.MinCount)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading data source types: %v", err))
+	}
+
+	text := formatter.TypeUsageTable("Data Source Types", usages, provider, params.MinCount)
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleResourceTypeStats(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[typeListingArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+
+	if params.MinCount <= 0 {
+		params.MinCount = 1
+	}
+
+	provider := strings.ToLower(strings.TrimSpace(params.Provider))
+	usages, err := s.db.ListResourceTypes(provider, params.MinCount)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading resource types: %v", err))
+	}
+
+	text := formatter.TypeUsageTable("Resource Types", usages, provider, params.MinCount)
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleFindModulesUsingResource(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[struct {
+		ResourceType string `json:"resource_type"`
+	}](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+	resourceType := strings.ToLower(strings.TrimSpace(params.ResourceType))
+	if resourceType == "" {
+		return ErrorResponse("resource_type is required")
+	}
+
+	users, err := s.db.ListModulesUsingResourceType(resourceType)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading resources: %v", err))
+	}
+
+	var suggestion string
+	if len(users) == 0 {
+		if usages, err := s.db.ListResourceTypes("", 1); err == nil {
+			best := len(resourceType)/3 + 1
+			for _, u := range usages {
+				if d := util.Levenshtein(resourceType, u.Type); d > 0 && d < best {
+					suggestion, best = u.Type, d
+				}
+			}
+		}
+	}
+
+	return SuccessResponse(formatter.ResourceTypeUsers(resourceType, users, suggestion))
+}
+
+func (s *Server) handleCatalogOverview() map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	modules, err := s.db.ListModules()
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading modules: %v", err))
+	}
+
+	if len(modules) == 0 {
+		return SuccessResponse("No modules found. Run sync_modules tool to fetch modules from GitHub.")
+	}
+
+	groups := make(map[string][]formatter.CategorizedModule)
+	for _, module := range modules {
+		if strings.Contains(module.Name, "//") {
+			continue
+		}
+		types, err := s.db.GetModuleResourceTypes(module.ID)
+		if err != nil {
+			log.Printf("Warning: failed to load resource types for %s: %v", module.Name, err)
+			continue
+		}
+		category := util.DominantCategory(types)
+		groups[category] = append(groups[category], formatter.CategorizedModule{
+			Name:        module.Name,
+			Description: module.Description,
+		})
+	}
+
+	text := formatter.CatalogOverview(util.CategoryOrder, groups)
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleModuleSimilarity(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleSimilarityArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleA) == "" || strings.TrimSpace(params.ModuleB) == "" {
+		return ErrorResponse("module_a and module_b are required")
+	}
+
+	moduleA, err := s.resolveModule(params.ModuleA)
+	if err != nil {
+		return moduleNotFound(params.ModuleA, err)
+	}
+	moduleB, err := s.resolveModule(params.ModuleB)
+	if err != nil {
+		return moduleNotFound(params.ModuleB, err)
+	}
+
+	featuresA, err := s.similarityFeatures(moduleA)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load %s: %v", moduleA.Name, err))
+	}
+	featuresB, err := s.similarityFeatures(moduleB)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load %s: %v", moduleB.Name, err))
+	}
+
+	var (
+		overlaps    []formatter.SimilarityOverlap
+		score       float64
+		totalWeight float64
+	)
+	for i, dim := range similarityWeights {
+		shared, union := setOverlap(featuresA[i], featuresB[i])
+		overlap := formatter.SimilarityOverlap{Name: dim.name, Shared: shared, Union: union}
+		if union > 0 {
+			overlap.Score = float64(len(shared)) / float64(union)
+			score += dim.weight * overlap.Score
+			totalWeight += dim.weight
+		}
+		overlaps = append(overlaps, overlap)
+	}
+	// Dimensions both modules lack (e.g. no tags) don't count against them.
+	if totalWeight > 0 {
+		score /= totalWeight
+	}
+
+	text := formatter.ModuleSimilarity(moduleA.Name, moduleB.Name, score, overlaps)
+	return SuccessResponse(text)
+}
+
+// similarityFeatures returns the feature sets in similarityWeights order,
+// built from the module's own files so shared example scaffolding doesn't
+// make modules look alike.
+func (s *Server) similarityFeatures(module *database.Module) ([]map[string]struct{}, error) {
+	resourceTypes, err := s.db.GetModuleResourceTypes(module.ID)
+	if err != nil {
+		return nil, err
+	}
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return nil, err
+	}
+	variables = ownVariables(module, variables)
+	tags, err := s.db.GetModuleTags(module.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceSet := make(map[string]struct{})
+	for _, rt := range resourceTypes {
+		resourceSet[rt] = struct{}{}
+	}
+	variableSet := make(map[string]struct{})
+	for _, v := range variables {
+		variableSet[v.Name] = struct{}{}
+	}
+	tagSet := make(map[string]struct{})
+	for _, t := range tags {
+		tagSet[t.Tag] = struct{}{}
+	}
+	return []map[string]struct{}{resourceSet, variableSet, tagSet}, nil
+}
+
+func setOverlap(a, b map[string]struct{}) ([]string, int) {
+	var shared []string
+	union := len(b)
+	for key := range a {
+		if _, ok := b[key]; ok {
+			shared = append(shared, key)
+		} else {
+			union++
+		}
+	}
+	sort.Strings(shared)
+	return shared, union
+}
+
+type listAllVariablesArgs struct {
+	TypeFilter   string `json:"type_filter"`
+	RequiredOnly bool   `json:"required_only"`
+}
+
+func (s *Server) handleListAllVariables(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[listAllVariablesArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+
+	usages, err := s.db.ListAllVariables(params.RequiredOnly)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to list variables: %v", err))
+	}
+
+	match := variableTypeMatcher(params.TypeFilter)
+	groups := make(map[string]*formatter.VariableGroup)
+	var order []string
+	for _, u := range usages {
+		if !match(u.Type) {
+			continue
+		}
+		group, ok := groups[u.Name]
+		if !ok {
+			group = &formatter.VariableGroup{Name: u.Name}
+			groups[u.Name] = group
+			order = append(order, u.Name)
+		}
+		typ := collapseWhitespace(u.Type)
+		if typ == "" {
+			typ = "any"
+		}
+		shape := typ
+		if parsed, err := schema.Parse(u.Type); err == nil {
+			shape = parsed.Shape()
+		}
+		group.Modules = append(group.Modules, formatter.VariableGroupModule{ModuleName: u.ModuleName, Type: typ, Shape: shape})
+	}
+
+	result := make([]formatter.VariableGroup, 0, len(order))
+	for _, name := range order {
+		group := groups[name]
+		for _, m := range group.Modules[1:] {
+			if m.Type != group.Modules[0].Type {
+				group.TypesDiverge = true
+				break
+			}
+		}
+		result = append(result, *group)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return len(result[i].Modules) > len(result[j].Modules)
+	})
+
+	return SuccessResponse(formatter.AllVariables(strings.TrimSpace(params.TypeFilter), params.RequiredOnly, result))
+}
+
+// variableTypeMatcher builds a whitespace-insensitive matcher for a type
+// filter. A filter containing "..." must match the whole type with "..."
+// standing for any text, e.g. list(object(...)); otherwise it matches as a
+// substring.
+func variableTypeMatcher(filter string) func(string) bool {
+	normalize := func(v string) string {
+		return strings.ToLower(strings.Join(strings.Fields(v), ""))
+	}
+	filter = normalize(filter)
+	if filter == "" {
+		return func(string) bool { return true }
+	}
+	if !strings.Contains(filter, "...") {
+		return func(t string) bool { return strings.Contains(normalize(t), filter) }
+	}
+
+	parts := strings.Split(filter, "...")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	pattern := regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+	return func(t string) bool { return pattern.MatchString(normalize(t)) }
+}
+
+type compareVariablesArgs struct {
+	VariableName string `json:"variable_name"`
+}
+
+func (s *Server) handleCompareVariablesAcrossModules(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[compareVariablesArgs](args)
+	name := strings.TrimSpace(params.VariableName)
+	if err != nil || name == "" {
+		return ErrorResponse("variable_name is required")
+	}
+
+	definitions, err := s.db.ListVariableDefinitions(name)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load variables: %v", err))
+	}
+	modules, err := s.db.ListModules()
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading modules: %v", err))
+	}
+
+	defined := make(map[string]struct{}, len(definitions))
+	for _, d := range definitions {
+		defined[d.ModuleName] = struct{}{}
+	}
+	// Submodules only declare what they need, so only root modules count as missing.
+	var missing []string
+	for _, m := range modules {
+		if _, ok := defined[m.Name]; !ok && !strings.Contains(m.Name, "//") {
+			missing = append(missing, m.Name)
+		}
+	}
+
+	return SuccessResponse(formatter.VariableComparison(name, definitions, missing))
+}
+
+type searchOutputsArgs struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+}
+
+func (s *Server) handleSearchOutputs(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[searchOutputsArgs](args)
+	query := strings.TrimSpace(params.Query)
+	if err != nil || query == "" {
+		return ErrorResponse("query is required")
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	outputs, err := s.db.SearchOutputs(query, limit)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to search outputs: %v", err))
+	}
+
+	return SuccessResponse(formatter.OutputSearch(query, outputs))
+}
+
+type limitArgs struct {
+	Limit int `json:"limit"`
+}
+
+func (s *Server) handleListMostActiveModules(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[limitArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	activity, err := s.db.ListModuleReleaseActivity(limit)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load release activity: %v", err))
+	}
+	for i := range activity {
+		if rel, err := s.resolveLatestRelease(activity[i].ModuleID, latestBySemver); err == nil {
+			activity[i].LatestVersion = rel.Version
+		}
+	}
+
+	return SuccessResponse(formatter.MostActiveModules(activity))
+}
+
+func (s *Server) handleRecentlyUpdatedModules(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[limitArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	modules, err := s.db.ListRecentlyUpdatedModules(limit)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading modules: %v", err))
+	}
+
+	return SuccessResponse(formatter.RecentlyUpdatedModules(modules, time.Now()))
+}
+
+func (s *Server) handleListVariableTypes(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[limitArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	usages, err := s.db.ListAllVariables(false)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to list variables: %v", err))
+	}
+
+	counts := make(map[string]*formatter.VariableTypeCount)
+	modules := make(map[string]map[string]struct{})
+	for _, u := range usages {
+		key := normalizeVariableType(u.Type)
+		entry, ok := counts[key]
+		if !ok {
+			entry = &formatter.VariableTypeCount{Type: key}
+			counts[key] = entry
+			modules[key] = make(map[string]struct{})
+		}
+		entry.Variables++
+		modules[key][u.ModuleName] = struct{}{}
+	}
+
+	types := make([]formatter.VariableTypeCount, 0, len(counts))
+	for key, entry := range counts {
+		entry.Modules = len(modules[key])
+		types = append(types, *entry)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Variables != types[j].Variables {
+			return types[i].Variables > types[j].Variables
+		}
+		return types[i].Type < types[j].Type
+	})
+
+	return SuccessResponse(formatter.VariableTypes(types, len(usages), limit))
+}
+
+// normalizeVariableType renders a type constraint canonically so layout
+// differences do not split groups. Untyped variables count as any.
+func normalizeVariableType(expr string) string {
+	if parsed, err := schema.Parse(expr); err == nil {
+		return parsed.Expr()
+	}
+	return collapseWhitespace(expr)
+}
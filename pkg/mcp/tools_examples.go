@@ -0,0 +1,937 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/formatter"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/indexer"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/schema"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/util"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type exampleFeatureArgs struct {
+	ModuleName string `json:"module_name"`
+	Feature    string `json:"feature"`
+}
+
+type exampleBundleArgs struct {
+	ModuleName     string `json:"module_name"`
+	ExampleName    string `json:"example_name"`
+	LatestStrategy string `json:"latest_strategy"`
+}
+
+type validateExampleArgs struct {
+	ModuleName  string `json:"module_name"`
+	ExampleName string `json:"example_name"`
+}
+
+type pinnedExampleSourcesArgs struct {
+	ModuleName     string `json:"module_name"`
+	LatestStrategy string `json:"latest_strategy"`
+}
+
+func (s *Server) handleGetExampleBundle(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[exampleBundleArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" || strings.TrimSpace(params.ExampleName) == "" {
+		return ErrorResponse("module_name and example_name are required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting files: %v", err))
+	}
+
+	exampleFiles := sortExampleFiles(filterExampleFiles(files, params.ExampleName))
+	if len(exampleFiles) == 0 {
+		return ErrorResponse(fmt.Sprintf("Example '%s' not found in module '%s'", params.ExampleName, module.Name))
+	}
+
+	strategy, err := parseLatestStrategy(params.LatestStrategy)
+	if err != nil {
+		return ErrorResponse(err.Error())
+	}
+
+	ref := ""
+	if release, err := s.resolveLatestRelease(module.ID, strategy); err == nil {
+		ref = release.Tag
+	}
+
+	bundle := formatter.ExampleBundle{
+		Source:         moduleGitSource(module, ref),
+		Ref:            ref,
+		Files:          exampleFiles,
+		TFVars:         requiredVariableNames(exampleFiles),
+		RequiredInputs: requiredVariableNames(moduleOwnFiles(files, moduleRootPrefix(module.Name))),
+	}
+	text := formatter.ExampleBundleManifest(module.Name, params.ExampleName, bundle)
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleValidateExample(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[validateExampleArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" || strings.TrimSpace(params.ExampleName) == "" {
+		return ErrorResponse("module_name and example_name are required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting files: %v", err))
+	}
+
+	exampleFiles := sortExampleFiles(filterExampleFiles(files, params.ExampleName))
+	if len(exampleFiles) == 0 {
+		return ErrorResponse(fmt.Sprintf("Example '%s' not found in module '%s'", params.ExampleName, module.Name))
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting variables: %v", err))
+	}
+	variables = ownVariables(module, variables)
+
+	var calls []formatter.ExampleCallCheck
+	for _, f := range exampleFiles {
+		body := parseHCLBody(f.Content)
+		if body == nil || f.FileType != "terraform" {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "module" || len(block.Labels) == 0 || !s.exampleCallsModule(block, module) {
+				continue
+			}
+			call := checkExampleCall(block, variables)
+			call.FilePath = f.FilePath
+			calls = append(calls, call)
+		}
+	}
+
+	return SuccessResponse(formatter.ExampleValidation(module.Name, params.ExampleName, len(variables), calls))
+}
+
+func (s *Server) handleGetVariableUsage(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[struct {
+		ModuleName   string `json:"module_name"`
+		VariableName string `json:"variable_name"`
+	}](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" || strings.TrimSpace(params.VariableName) == "" {
+		return ErrorResponse("module_name and variable_name are required")
+	}
+	variableName := strings.TrimSpace(params.VariableName)
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting variables: %v", err))
+	}
+	variables = ownVariables(module, variables)
+	if !slices.ContainsFunc(variables, func(v database.ModuleVariable) bool { return v.Name == variableName }) {
+		msg := fmt.Sprintf("Variable '%s' not found in module '%s'", variableName, module.Name)
+		if suggestion := closestVariableName(variableName, variables); suggestion != "" {
+			msg += fmt.Sprintf(". Did you mean '%s'?", suggestion)
+		}
+		return ErrorResponse(msg)
+	}
+
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting files: %v", err))
+	}
+
+	var (
+		usages   []formatter.ExampleVariableUsage
+		examples = make(map[string]struct{})
+	)
+	for _, f := range files {
+		parts := strings.Split(f.FilePath, "/")
+		if len(parts) < 3 || parts[0] != "examples" || f.FileType != "terraform" {
+			continue
+		}
+		examples[parts[1]] = struct{}{}
+		body := parseHCLBody(f.Content)
+		if body == nil {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "module" || len(block.Labels) == 0 || !s.exampleCallsModule(block, module) {
+				continue
+			}
+			attr, ok := block.Body.Attributes[variableName]
+			if !ok {
+				continue
+			}
+			usages = append(usages, formatter.ExampleVariableUsage{
+				Example:  parts[1],
+				FilePath: f.FilePath,
+				Call:     block.Labels[0],
+				Line:     attr.SrcRange.Start.Line,
+				Snippet:  attributeSnippet(f.Content, attr.SrcRange),
+			})
+		}
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].FilePath != usages[j].FilePath {
+			return usages[i].FilePath < usages[j].FilePath
+		}
+		return usages[i].Line < usages[j].Line
+	})
+	return SuccessResponse(formatter.VariableUsageInExamples(module.Name, variableName, len(examples), usages))
+}
+
+// attributeSnippet returns the source of an attribute starting at the
+// beginning of its line, with the attribute's own indentation removed from
+// every line.
+func attributeSnippet(content string, rng hcl.Range) string {
+	start := min(max(rng.Start.Byte, 0), len(content))
+	end := min(max(rng.End.Byte, start), len(content))
+	lineStart := strings.LastIndexByte(content[:start], '\n') + 1
+	indent := content[lineStart:start]
+	if strings.TrimSpace(indent) != "" {
+		return strings.TrimSpace(content[start:end])
+	}
+
+	lines := strings.Split(content[lineStart:end], "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, indent)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// moduleMetaArguments are module block arguments handled by Terraform itself
+// rather than passed to the module as inputs.
+var moduleMetaArguments = map[string]bool{
+	"source":     true,
+	"version":    true,
+	"providers":  true,
+	"count":      true,
+	"for_each":   true,
+	"depends_on": true,
+}
+
+// exampleCallsModule reports whether a module block in an example calls the
+// module itself. Relative and unrecognized sources count as the module; only
+// sources naming another indexed module are left out.
+func (s *Server) exampleCallsModule(block *hclsyntax.Block, module *database.Module) bool {
+	source := staticAttributeString(block.Body.Attributes["source"])
+	if source == "" || strings.HasPrefix(source, ".") {
+		return true
+	}
+
+	target := registryModuleName(source)
+	if m := exampleSourceRepoPattern.FindStringSubmatch(source); m != nil {
+		target = m[1]
+	}
+	rootName, _, _ := strings.Cut(module.Name, "//")
+	if target == "" || target == rootName {
+		return true
+	}
+	_, err := s.db.GetModule(target)
+	return err != nil
+}
+
+// checkExampleCall compares the inputs a module block sets with the
+// variables the module declares.
+func checkExampleCall(block *hclsyntax.Block, variables []database.ModuleVariable) formatter.ExampleCallCheck {
+	declared := make(map[string]database.ModuleVariable, len(variables))
+	for _, v := range variables {
+		declared[v.Name] = v
+	}
+
+	check := formatter.ExampleCallCheck{Call: block.Labels[0]}
+	set := make(map[string]bool)
+	for name := range block.Body.Attributes {
+		if moduleMetaArguments[name] {
+			continue
+		}
+		set[name] = true
+		check.Inputs++
+		if _, ok := declared[name]; ok {
+			continue
+		}
+		check.Unknown = append(check.Unknown, formatter.UnknownInput{
+			Name:       name,
+			Suggestion: closestVariableName(name, variables),
+		})
+	}
+	for _, v := range variables {
+		if v.Required && !set[v.Name] {
+			check.Missing = append(check.Missing, v.Name)
+		}
+	}
+
+	sort.Slice(check.Unknown, func(i, j int) bool { return check.Unknown[i].Name < check.Unknown[j].Name })
+	sort.Strings(check.Missing)
+	return check
+}
+
+// closestVariableName suggests a declared variable for a misspelled input,
+// or returns "" when nothing is close enough.
+func closestVariableName(name string, variables []database.ModuleVariable) string {
+	best, bestDistance := "", len(name)/3+1
+	for _, v := range variables {
+		if d := util.Levenshtein(name, v.Name); d < bestDistance {
+			best, bestDistance = v.Name, d
+		}
+	}
+	return best
+}
+
+// moduleGitSource builds a git source address usable outside the repository,
+// replacing the relative source examples use to reach the module.
+func moduleGitSource(module *database.Module, ref string) string {
+	source := fmt.Sprintf("git::https://github.com/%s.git", module.FullName)
+	if _, sub, ok := strings.Cut(module.Name, "//"); ok {
+		source += "//" + sub
+	}
+	if ref != "" {
+		source += "?ref=" + ref
+	}
+	return source
+}
+
+// moduleOwnFiles keeps the Terraform files directly in the module directory,
+// excluding examples and nested modules.
+func moduleOwnFiles(files []database.ModuleFile, rootPrefix string) []database.ModuleFile {
+	var own []database.ModuleFile
+	for _, f := range files {
+		if f.FileType != "terraform" || !strings.HasPrefix(f.FilePath, rootPrefix) {
+			continue
+		}
+		if strings.Contains(strings.TrimPrefix(f.FilePath, rootPrefix), "/") {
+			continue
+		}
+		own = append(own, f)
+	}
+	return own
+}
+
+// requiredVariableNames lists variables declared without a default.
+func requiredVariableNames(files []database.ModuleFile) []string {
+	var names []string
+	for _, block := range variableBlocks(files) {
+		if _, hasDefault := block.Body.Attributes["default"]; !hasDefault {
+			names = append(names, block.Labels[0])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// variableBlocks parses the given files and returns their labelled variable
+// blocks, skipping files that fail to parse.
+func variableBlocks(files []database.ModuleFile) []*hclsyntax.Block {
+	var blocks []*hclsyntax.Block
+	for _, f := range files {
+		if f.FileType != "terraform" {
+			continue
+		}
+		file, diags := hclsyntax.ParseConfig([]byte(f.Content), f.FilePath, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			continue
+		}
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type == "variable" && len(block.Labels) > 0 {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+	return blocks
+}
+
+func (s *Server) handleListAllExamples(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[struct {
+		NameFilter string `json:"name_filter"`
+	}](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+	filter := strings.ToLower(strings.TrimSpace(params.NameFilter))
+
+	examples, err := s.db.ListAllExamples()
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error loading examples: %v", err))
+	}
+	if filter != "" {
+		matched := examples[:0]
+		for _, e := range examples {
+			if strings.Contains(strings.ToLower(e.Name), filter) {
+				matched = append(matched, e)
+			}
+		}
+		examples = matched
+	}
+
+	return SuccessResponse(formatter.AllExamples(examples, filter))
+}
+
+func (s *Server) handleCompareExamples(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[struct {
+		ModuleName string `json:"module_name"`
+		ExampleA   string `json:"example_a"`
+		ExampleB   string `json:"example_b"`
+	}](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+	exampleA, exampleB := strings.TrimSpace(params.ExampleA), strings.TrimSpace(params.ExampleB)
+	if exampleA == "" || exampleB == "" {
+		return ErrorResponse("example_a and example_b are required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting files: %v", err))
+	}
+
+	filesA := exampleFilesByPath(filterExampleFiles(files, exampleA), exampleA)
+	filesB := exampleFilesByPath(filterExampleFiles(files, exampleB), exampleB)
+	if len(filesA) == 0 {
+		return ErrorResponse(fmt.Sprintf("Example '%s' not found in module '%s'", exampleA, module.Name))
+	}
+	if len(filesB) == 0 {
+		return ErrorResponse(fmt.Sprintf("Example '%s' not found in module '%s'", exampleB, module.Name))
+	}
+
+	paths := make([]string, 0, len(filesA)+len(filesB))
+	for p := range filesA {
+		paths = append(paths, p)
+	}
+	for p := range filesB {
+		if _, ok := filesA[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	diffs := make([]formatter.ExampleFileDiff, 0, len(paths))
+	for _, p := range paths {
+		contentA, inA := filesA[p]
+		contentB, inB := filesB[p]
+		nameA, nameB := "examples/"+exampleA+"/"+p, "examples/"+exampleB+"/"+p
+		if !inA {
+			nameA = "/dev/null"
+		}
+		if !inB {
+			nameB = "/dev/null"
+		}
+		diffs = append(diffs, formatter.ExampleFileDiff{
+			Path:  p,
+			InA:   inA,
+			InB:   inB,
+			Patch: util.UnifiedDiff(nameA, nameB, contentA, contentB, 3),
+		})
+	}
+
+	return SuccessResponse(formatter.ExampleDiff(module.Name, exampleA, exampleB, diffs))
+}
+
+// exampleFilesByPath maps the paths of an example's files, relative to the
+// example directory, to their content.
+func exampleFilesByPath(files []database.ModuleFile, exampleName string) map[string]string {
+	byPath := make(map[string]string, len(files))
+	for _, f := range files {
+		byPath[strings.TrimPrefix(f.FilePath, "examples/"+exampleName+"/")] = f.Content
+	}
+	return byPath
+}
+
+func (s *Server) handleFindExampleForFeature(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[exampleFeatureArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" || strings.TrimSpace(params.Feature) == "" {
+		return ErrorResponse("module_name and feature are required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting files: %v", err))
+	}
+
+	matches := findExampleFeatureMatches(files, featureSearchTerms(params.Feature))
+	text := formatter.ExampleFeatureMatches(module.Name, params.Feature, matches)
+	return SuccessResponse(text)
+}
+
+// featureSearchTerms expands a feature keyword into the spellings it usually
+// takes in HCL, e.g. "private endpoint" -> private_endpoint, privateendpoint.
+func featureSearchTerms(feature string) []string {
+	var terms []string
+	for _, v := range util.ExpandQueryVariants(feature) {
+		terms = append(terms, v, strings.ReplaceAll(v, " ", "_"))
+	}
+	terms = uniqueStrings(terms)
+	sort.Slice(terms, func(i, j int) bool {
+		return len(terms[i]) > len(terms[j])
+	})
+	return terms
+}
+
+func findExampleFeatureMatches(files []database.ModuleFile, terms []string) []formatter.ExampleFeatureMatch {
+	var matches []formatter.ExampleFeatureMatch
+	for _, file := range files {
+		exampleName := exampleNameFromPath(file.FilePath)
+		if exampleName == "" {
+			continue
+		}
+
+		lower := strings.ToLower(file.Content)
+		for _, term := range terms {
+			if strings.Contains(lower, term) {
+				matches = append(matches, formatter.ExampleFeatureMatch{
+					ExampleName: exampleName,
+					FilePath:    file.FilePath,
+					Snippet:     formatter.ExtractCodeContext(file.Content, term),
+				})
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].ExampleName == matches[j].ExampleName {
+			return matches[i].FilePath < matches[j].FilePath
+		}
+		return matches[i].ExampleName < matches[j].ExampleName
+	})
+	return matches
+}
+
+func exampleNameFromPath(filePath string) string {
+	parts := strings.Split(filePath, "/")
+	if len(parts) < 3 || parts[0] != "examples" {
+		return ""
+	}
+	return parts[1]
+}
+
+// exampleRefPattern captures the ref query parameter of a git module source.
+var exampleRefPattern = regexp.MustCompile(`[?&]ref=([^&]+)`)
+
+// exampleSourceRepoPattern captures the repository name of a git module source.
+var exampleSourceRepoPattern = regexp.MustCompile(`github\.com[:/][^/]+/([^/?.]+)`)
+
+func (s *Server) handleFindPinnedExampleSources(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[pinnedExampleSourcesArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+	strategy, err := parseLatestStrategy(params.LatestStrategy)
+	if err != nil {
+		return ErrorResponse(err.Error())
+	}
+
+	var modules []database.Module
+	if name := strings.TrimSpace(params.ModuleName); name != "" {
+		module, err := s.resolveModule(name)
+		if err != nil {
+			return moduleNotFound(name, err)
+		}
+		modules = []database.Module{*module}
+	} else {
+		all, err := s.db.ListModules()
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Failed to list modules: %v", err))
+		}
+		for _, m := range all {
+			if m.HasExamples {
+				modules = append(modules, m)
+			}
+		}
+	}
+
+	latest := make(map[string]string)
+	latestVersion := func(moduleName string) string {
+		if v, ok := latest[moduleName]; ok {
+			return v
+		}
+		v := ""
+		if m, err := s.db.GetModule(moduleName); err == nil {
+			if rel, err := s.resolveLatestRelease(m.ID, strategy); err == nil {
+				v = rel.Version
+			}
+		}
+		latest[moduleName] = v
+		return v
+	}
+
+	var pins []formatter.PinnedExampleSource
+	for _, module := range modules {
+		files, err := s.db.GetModuleFiles(module.ID)
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Error getting files for %s: %v", module.Name, err))
+		}
+		for _, pin := range pinnedExampleSources(files) {
+			pin.ModuleName = module.Name
+			if pin.TargetModule == "" {
+				pin.TargetModule = module.Name
+			}
+			pin.Latest = latestVersion(pin.TargetModule)
+			pin.Outdated = pin.Latest != "" && util.CompareVersions(pin.Pinned, pin.Latest) < 0
+			pins = append(pins, pin)
+		}
+	}
+
+	return SuccessResponse(formatter.PinnedExampleSources(len(modules), pins))
+}
+
+// pinnedExampleSources finds module calls in example files whose source is
+// pinned to a git ref or whose registry version is an exact version.
+func pinnedExampleSources(files []database.ModuleFile) []formatter.PinnedExampleSource {
+	var pins []formatter.PinnedExampleSource
+	for _, f := range files {
+		exampleName := exampleNameFromPath(f.FilePath)
+		if exampleName == "" || f.FileType != "terraform" {
+			continue
+		}
+		file, diags := hclsyntax.ParseConfig([]byte(f.Content), f.FilePath, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			continue
+		}
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "module" || len(block.Labels) == 0 {
+				continue
+			}
+			source := staticAttributeString(block.Body.Attributes["source"])
+			if source == "" {
+				continue
+			}
+			pin := formatter.PinnedExampleSource{
+				Example:  exampleName,
+				Call:     block.Labels[0],
+				FilePath: f.FilePath,
+				Source:   source,
+			}
+			if m := exampleRefPattern.FindStringSubmatch(source); m != nil {
+				pin.Pinned = m[1]
+				if repo := exampleSourceRepoPattern.FindStringSubmatch(source); repo != nil {
+					pin.TargetModule = repo[1]
+				}
+			} else if version := exactVersion(staticAttributeString(block.Body.Attributes["version"])); version != "" {
+				pin.Pinned = version
+				pin.TargetModule = registryModuleName(source)
+			} else {
+				continue
+			}
+			pins = append(pins, pin)
+		}
+	}
+	return pins
+}
+
+func staticAttributeString(attr *hclsyntax.Attribute) string {
+	if attr == nil {
+		return ""
+	}
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !val.IsKnown() || val.IsNull() || val.Type() != cty.String {
+		return ""
+	}
+	return strings.TrimSpace(val.AsString())
+}
+
+// exactVersion returns the version of a constraint that allows exactly one
+// release ("1.2.0" or "= 1.2.0"), or "" for ranges.
+func exactVersion(constraint string) string {
+	version := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(constraint), "="))
+	if version == "" || strings.ContainsAny(version, "<>~!,") {
+		return ""
+	}
+	return version
+}
+
+// registryModuleName maps a registry source such as cloudnationhq/vnet/azure
+// to the repository naming convention terraform-azure-vnet.
+func registryModuleName(source string) string {
+	parts := strings.Split(source, "/")
+	if len(parts) != 3 {
+		return ""
+	}
+	return fmt.Sprintf("terraform-%s-%s", parts[2], parts[1])
+}
+
+func (s *Server) handleGetQuickstart(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load variables: %v", err))
+	}
+	outputs, err := s.db.GetModuleOutputs(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load outputs: %v", err))
+	}
+	variables, outputs = ownVariables(module, variables), ownOutputs(module, outputs)
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting files: %v", err))
+	}
+
+	quickstart := formatter.Quickstart{Description: module.Description}
+	if release, err := s.resolveLatestRelease(module.ID, latestBySemver); err == nil {
+		quickstart.Version = release.Version
+		quickstart.Ref = release.Tag
+	}
+	quickstart.Source = moduleGitSource(module, quickstart.Ref)
+
+	var required []database.ModuleVariable
+	for _, v := range variables {
+		if v.Required {
+			required = append(required, v)
+		}
+	}
+	sort.Slice(required, func(i, j int) bool { return required[i].Name < required[j].Name })
+	for _, v := range required {
+		shape := collapseWhitespace(v.Type)
+		if parsed, err := schema.Parse(v.Type); err == nil {
+			shape = parsed.Shape()
+		}
+		quickstart.Required = append(quickstart.Required, formatter.QuickstartInput{Name: v.Name, Shape: shape, Description: v.Description})
+	}
+	quickstart.Outputs = outputs
+
+	if usage, ok := simplestExampleUsage(files, module, quickstart.Source); ok {
+		quickstart.ExampleName = usage.example
+		quickstart.Label = usage.label
+		quickstart.Usage = usage.code
+		for _, v := range required {
+			if _, set := usage.inputs[v.Name]; !set {
+				quickstart.MissingInputs = append(quickstart.MissingInputs, v.Name)
+			}
+		}
+	} else {
+		quickstart.Label = moduleBlockLabel(module.Name, s.prefix)
+		quickstart.Usage = generatedUsage(quickstart.Label, quickstart.Source, required)
+	}
+
+	return SuccessResponse(formatter.QuickstartGuide(module.Name, quickstart))
+}
+
+// moduleBlockLabel derives a module block label by stripping the repository
+// prefix, e.g. terraform-azure-vnet becomes vnet. Without a configured prefix
+// the default naming convention is assumed.
+func moduleBlockLabel(moduleName, prefix string) string {
+	if prefix == "" {
+		prefix = indexer.DefaultRepoPrefix
+	}
+	name := moduleName
+	if _, sub, ok := strings.Cut(name, "//"); ok {
+		name = sub[strings.LastIndex(sub, "/")+1:]
+	}
+	name = strings.TrimPrefix(name, prefix)
+	name = strings.TrimPrefix(name, "terraform-")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+type exampleUsage struct {
+	example string
+	label   string
+	code    string
+	inputs  map[string]struct{}
+}
+
+// simplestExampleUsage picks the shortest example that calls the module and
+// returns that module block with its source pointed at source.
+func simplestExampleUsage(files []database.ModuleFile, module *database.Module, source string) (exampleUsage, bool) {
+	var best exampleUsage
+	bestLines := 0
+	for _, f := range files {
+		if f.FileType != "terraform" || !strings.HasPrefix(f.FilePath, "examples/") {
+			continue
+		}
+		parts := strings.Split(f.FilePath, "/")
+		if len(parts) != 3 {
+			continue
+		}
+
+		usage, ok := exampleModuleBlock(f, module, source)
+		if !ok {
+			continue
+		}
+		lines := strings.Count(f.Content, "\n")
+		if best.code == "" || lines < bestLines {
+			usage.example = parts[1]
+			best, bestLines = usage, lines
+		}
+	}
+	return best, best.code != ""
+}
+
+// exampleModuleBlock finds the block in an example file that calls the module
+// and rewrites its source. When no block's source identifies the module, a
+// file with a single module block is assumed to call it.
+func exampleModuleBlock(f database.ModuleFile, module *database.Module, source string) (exampleUsage, bool) {
+	parsed, diags := hclwrite.ParseConfig([]byte(f.Content), f.FilePath, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return exampleUsage{}, false
+	}
+
+	var candidates, matches []*hclwrite.Block
+	for _, block := range parsed.Body().Blocks() {
+		if block.Type() != "module" || len(block.Labels()) == 0 {
+			continue
+		}
+		candidates = append(candidates, block)
+		attr := block.Body().GetAttribute("source")
+		if attr == nil {
+			continue
+		}
+		value := strings.Trim(strings.TrimSpace(string(attr.Expr().BuildTokens(nil).Bytes())), `"`)
+		if strings.HasPrefix(value, "../") || registryModuleName(value) == module.Name || (module.FullName != "" && strings.Contains(value, module.FullName)) {
+			matches = append(matches, block)
+		}
+	}
+
+	var block *hclwrite.Block
+	switch {
+	case len(matches) > 0:
+		block = matches[0]
+	case len(candidates) == 1:
+		block = candidates[0]
+	default:
+		return exampleUsage{}, false
+	}
+
+	body := block.Body()
+	body.SetAttributeValue("source", cty.StringVal(source))
+	body.RemoveAttribute("version")
+
+	inputs := make(map[string]struct{})
+	for name := range body.Attributes() {
+		inputs[name] = struct{}{}
+	}
+	return exampleUsage{
+		label:  block.Labels()[0],
+		code:   strings.TrimSpace(string(hclwrite.Format(block.BuildTokens(nil).Bytes()))),
+		inputs: inputs,
+	}, true
+}
+
+// generatedUsage writes a module block that sets every required variable to a
+// placeholder matching its type.
+func generatedUsage(label, source string, required []database.ModuleVariable) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("module %q {\n", label))
+	b.WriteString(fmt.Sprintf("  source = %q\n", source))
+	if len(required) > 0 {
+		b.WriteString("\n")
+	}
+	for _, v := range required {
+		b.WriteString(fmt.Sprintf("  %s = %s\n", v.Name, placeholderValue(v.Type, "  ")))
+	}
+	b.WriteString("}")
+	return string(hclwrite.Format([]byte(b.String())))
+}
+
+func placeholderValue(typeExpr, indent string) string {
+	t, err := schema.Parse(typeExpr)
+	if err != nil {
+		return `""`
+	}
+	return placeholderFor(t, indent)
+}
+
+func placeholderFor(t *schema.Type, indent string) string {
+	switch t.Kind {
+	case "number":
+		return "0"
+	case "bool":
+		return "false"
+	case "list", "set", "tuple":
+		return "[]"
+	case "map":
+		return "{}"
+	case "object":
+		var fields []string
+		for _, field := range t.Fields {
+			if field.Optional {
+				continue
+			}
+			fields = append(fields, fmt.Sprintf("%s  %s = %s", indent, field.Name, placeholderFor(field.Type, indent+"  ")))
+		}
+		if len(fields) == 0 {
+			return "{}"
+		}
+		return "{\n" + strings.Join(fields, "\n") + "\n" + indent + "}"
+	}
+	return `""`
+}
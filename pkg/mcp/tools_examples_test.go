@@ -0,0 +1,19 @@
+package mcp
+
+import "testing"
+
+func TestModuleBlockLabel(t *testing.T) {
+	tests := []struct {
+		name, prefix, want string
+	}{
+		{"terraform-azure-vnet", "terraform-azure-", "vnet"},
+		{"terraform-azure-vnet", "", "vnet"},
+		{"tf-custom-private-dns", "tf-custom-", "private_dns"},
+		{"tf-custom-net//modules/sub-net", "tf-custom-", "sub_net"},
+	}
+	for _, tt := range tests {
+		if got := moduleBlockLabel(tt.name, tt.prefix); got != tt.want {
+			t.Errorf("moduleBlockLabel(%q, %q) = %q, want %q", tt.name, tt.prefix, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/schema"
+)
+
+// moduleExportSchemaVersion is bumped whenever the export document changes
+// shape so consumers can detect incompatible output.
+const moduleExportSchemaVersion = "1.0"
+
+type moduleExport struct {
+	SchemaVersion string                   `json:"schema_version"`
+	Module        moduleExportMetadata     `json:"module"`
+	Variables     []moduleExportVariable   `json:"variables"`
+	Outputs       []moduleExportOutput     `json:"outputs"`
+	Resources     []moduleExportResource   `json:"resources"`
+	DataSources   []moduleExportResource   `json:"data_sources"`
+	Providers     []moduleExportProvider   `json:"providers"`
+	Examples      []moduleExportExample    `json:"examples"`
+	Releases      []moduleExportRelease    `json:"releases"`
+	Terraform     []moduleExportConstraint `json:"terraform_required_version"`
+}
+
+type moduleExportMetadata struct {
+	Name        string `json:"name"`
+	FullName    string `json:"full_name"`
+	Org         string `json:"org,omitempty"`
+	Description string `json:"description,omitempty"`
+	RepoURL     string `json:"repo_url"`
+	LastUpdated string `json:"last_updated,omitempty"`
+	SyncedAt    string `json:"synced_at"`
+	HasExamples bool   `json:"has_examples"`
+	Archived    bool   `json:"archived,omitempty"`
+	NoTerraform bool   `json:"no_terraform,omitempty"`
+}
+
+type moduleExportVariable struct {
+	Name        string       `json:"name"`
+	Type        string       `json:"type,omitempty"`
+	Schema      *schema.Type `json:"schema,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Default     string       `json:"default,omitempty"`
+	Required    bool         `json:"required"`
+	Sensitive   bool         `json:"sensitive"`
+
+	OptionalAttributes []moduleExportOptionalAttribute `json:"optional_attributes,omitempty"`
+}
+
+type moduleExportOptionalAttribute struct {
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	Default string `json:"default,omitempty"`
+}
+
+type moduleExportOutput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Sensitive   bool   `json:"sensitive"`
+}
+
+type moduleExportResource struct {
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Provider   string `json:"provider,omitempty"`
+	SourceFile string `json:"source_file,omitempty"`
+}
+
+type moduleExportProvider struct {
+	Name    string `json:"name"`
+	Source  string `json:"source,omitempty"`
+	Version string `json:"version,omitempty"`
+	Primary bool   `json:"primary"`
+}
+
+type moduleExportExample struct {
+	Name  string   `json:"name"`
+	Files []string `json:"files"`
+}
+
+type moduleExportRelease struct {
+	Version string   `json:"version"`
+	Tag     string   `json:"tag"`
+	Date    string   `json:"date,omitempty"`
+	Entries []string `json:"entries,omitempty"`
+}
+
+type moduleExportConstraint struct {
+	Constraint string `json:"constraint"`
+	SourceFile string `json:"source_file,omitempty"`
+}
+
+func (s *Server) handleExportModule(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load variables: %v", err))
+	}
+	outputs, err := s.db.GetModuleOutputs(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load outputs: %v", err))
+	}
+	resources, err := s.db.GetModuleResources(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load resources: %v", err))
+	}
+	dataSources, err := s.db.GetModuleDataSources(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load data sources: %v", err))
+	}
+	providers, err := s.db.GetModuleProviders(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load providers: %v", err))
+	}
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load files: %v", err))
+	}
+	releases, err := s.db.GetModuleReleases(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load releases: %v", err))
+	}
+	versions, err := s.db.GetModuleTerraformVersions(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load terraform version: %v", err))
+	}
+
+	doc := moduleExport{
+		SchemaVersion: moduleExportSchemaVersion,
+		Module:        exportMetadata(module),
+		Variables:     exportVariables(variables),
+		Outputs:       exportOutputs(outputs),
+		Resources:     exportResources(resources),
+		DataSources:   []moduleExportResource{},
+		Providers:     []moduleExportProvider{},
+		Examples:      []moduleExportExample{},
+		Releases:      []moduleExportRelease{},
+		Terraform:     []moduleExportConstraint{},
+	}
+
+	for _, d := range dataSources {
+		doc.DataSources = append(doc.DataSources, moduleExportResource{
+			Type:       d.DataType,
+			Name:       d.DataName,
+			Provider:   d.Provider,
+			SourceFile: d.SourceFile,
+		})
+	}
+
+	for _, p := range providers {
+		doc.Providers = append(doc.Providers, moduleExportProvider{
+			Name:    p.Name,
+			Source:  p.Source,
+			Version: p.Version,
+			Primary: p.IsPrimary,
+		})
+	}
+
+	exampleMap := buildExampleMap(files)
+	exampleNames := make([]string, 0, len(exampleMap))
+	for name := range exampleMap {
+		exampleNames = append(exampleNames, name)
+	}
+	sort.Strings(exampleNames)
+	for _, name := range exampleNames {
+		doc.Examples = append(doc.Examples, moduleExportExample{Name: name, Files: exampleMap[name]})
+	}
+
+	for _, rel := range releases {
+		exported := moduleExportRelease{
+			Version: rel.Version,
+			Tag:     rel.Tag,
+			Date:    rel.ReleaseDate.String,
+		}
+		if entries, err := s.db.GetModuleReleaseEntries(rel.ID); err == nil {
+			for _, entry := range entries {
+				exported.Entries = append(exported.Entries, entry.Title)
+			}
+		}
+		doc.Releases = append(doc.Releases, exported)
+	}
+
+	for _, v := range versions {
+		doc.Terraform = append(doc.Terraform, moduleExportConstraint{
+			Constraint: v.RequiredVersion,
+			SourceFile: v.SourceFile,
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to encode module export: %v", err))
+	}
+	return SuccessResponse(string(data))
+}
+
+// moduleInfoDocument is the JSON form of get_module_info.
+type moduleInfoDocument struct {
+	Module    moduleExportMetadata   `json:"module"`
+	Topics    []string               `json:"topics"`
+	Variables []moduleExportVariable `json:"variables"`
+	Outputs   []moduleExportOutput   `json:"outputs"`
+	Resources []moduleExportResource `json:"resources"`
+	Files     []moduleInfoFile       `json:"files"`
+}
+
+type moduleInfoFile struct {
+	Path      string `json:"path"`
+	Type      string `json:"type,omitempty"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+func moduleInfoJSON(module *database.Module, topics []string, variables []database.ModuleVariable, outputs []database.ModuleOutput, resources []database.ModuleResource, files []database.ModuleFile) (string, error) {
+	doc := moduleInfoDocument{
+		Module:    exportMetadata(module),
+		Topics:    []string{},
+		Variables: exportVariables(variables),
+		Outputs:   exportOutputs(outputs),
+		Resources: exportResources(resources),
+		Files:     []moduleInfoFile{},
+	}
+	doc.Topics = append(doc.Topics, topics...)
+	for _, f := range files {
+		doc.Files = append(doc.Files, moduleInfoFile{Path: f.FilePath, Type: f.FileType, SizeBytes: f.SizeBytes})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return "```json\n" + string(data) + "\n```", nil
+}
+
+func exportMetadata(module *database.Module) moduleExportMetadata {
+	return moduleExportMetadata{
+		Name:        module.Name,
+		FullName:    module.FullName,
+		Org:         module.Org,
+		Description: module.Description,
+		RepoURL:     module.RepoURL,
+		LastUpdated: module.LastUpdated,
+		SyncedAt:    module.SyncedAt.Format("2006-01-02T15:04:05Z07:00"),
+		HasExamples: module.HasExamples,
+		Archived:    module.Archived,
+		NoTerraform: module.NoTerraform,
+	}
+}
+
+func exportVariables(variables []database.ModuleVariable) []moduleExportVariable {
+	exported := []moduleExportVariable{}
+	for _, v := range variables {
+		item := moduleExportVariable{
+			Name:        v.Name,
+			Type:        v.Type,
+			Description: v.Description,
+			Default:     v.DefaultValue,
+			Required:    v.Required,
+			Sensitive:   v.Sensitive,
+		}
+		if parsed, err := schema.Parse(v.Type); err == nil {
+			item.Schema = parsed
+		}
+		for _, attr := range v.OptionalAttributes {
+			item.OptionalAttributes = append(item.OptionalAttributes, moduleExportOptionalAttribute(attr))
+		}
+		exported = append(exported, item)
+	}
+	return exported
+}
+
+func exportOutputs(outputs []database.ModuleOutput) []moduleExportOutput {
+	exported := []moduleExportOutput{}
+	for _, o := range outputs {
+		exported = append(exported, moduleExportOutput{
+			Name:        o.Name,
+			Description: o.Description,
+			Value:       o.Value,
+			Sensitive:   o.Sensitive,
+		})
+	}
+	return exported
+}
+
+func exportResources(resources []database.ModuleResource) []moduleExportResource {
+	exported := []moduleExportResource{}
+	for _, r := range resources {
+		exported = append(exported, moduleExportResource{
+			Type:       r.ResourceType,
+			Name:       r.ResourceName,
+			Provider:   r.Provider,
+			SourceFile: r.SourceFile,
+		})
+	}
+	return exported
+}
+
+func (s *Server) handleGetVariableSchema(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting variables: %v", err))
+	}
+	variables = ownVariables(module, variables)
+
+	data, err := json.MarshalIndent(variablesJSONSchema(module.Name, variables), "", "  ")
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to encode variable schema: %v", err))
+	}
+	return SuccessResponse("```json\n" + string(data) + "\n```")
+}
+
+// variablesJSONSchema describes a module's inputs as one JSON Schema object.
+// Types that cannot be parsed fall back to the empty schema, keeping the
+// original constraint under x-terraform-type.
+func variablesJSONSchema(moduleName string, variables []database.ModuleVariable) map[string]any {
+	properties := make(map[string]any, len(variables))
+	required := []string{}
+	for _, v := range variables {
+		prop := map[string]any{}
+		if parsed, err := schema.Parse(v.Type); err == nil {
+			prop = parsed.JSONSchema()
+		} else {
+			prop["x-terraform-type"] = v.Type
+		}
+		if v.Description != "" {
+			prop["description"] = v.Description
+		}
+		if v.DefaultValue != "" {
+			if value, ok := schema.LiteralJSON(v.DefaultValue); ok {
+				prop["default"] = value
+				// A null default is only valid if the schema admits null.
+				if typ, isString := prop["type"].(string); isString && string(value) == "null" {
+					prop["type"] = []string{typ, "null"}
+				}
+			}
+		}
+		if v.Sensitive {
+			prop["writeOnly"] = true
+		}
+		properties[v.Name] = prop
+		if v.Required {
+			required = append(required, v.Name)
+		}
+	}
+
+	return map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                moduleName,
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
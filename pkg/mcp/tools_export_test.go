@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+)
+
+func TestVariablesJSONSchemaOwnVariables(t *testing.T) {
+	module := &database.Module{Name: "terraform-azure-demo"}
+	variables := []database.ModuleVariable{
+		{Name: "location", Type: "string", Required: true, SourceFile: "examples/complete/variables.tf"},
+		{Name: "example_only", Type: "string", Required: true, SourceFile: "examples/complete/variables.tf"},
+		{Name: "location", Type: "string", Required: true, SourceFile: "variables.tf"},
+		{Name: "name", Type: "string", Required: true, SourceFile: "variables.tf"},
+		{Name: "name", Type: "string", Required: true, SourceFile: "main.tf"},
+		{Name: "subnet", Type: "string", Required: true, SourceFile: "modules/sub/variables.tf"},
+	}
+
+	doc := variablesJSONSchema(module.Name, ownVariables(module, variables))
+
+	required := doc["required"].([]string)
+	if want := []string{"location", "name"}; !slices.Equal(required, want) {
+		t.Errorf("required = %v, want %v", required, want)
+	}
+	properties := doc["properties"].(map[string]any)
+	for _, name := range []string{"example_only", "subnet"} {
+		if _, ok := properties[name]; ok {
+			t.Errorf("property %q from outside the module was included", name)
+		}
+	}
+}
+
+func TestOwnVariablesSubmodule(t *testing.T) {
+	module := &database.Module{Name: "terraform-azure-demo//modules/sub"}
+	variables := []database.ModuleVariable{
+		{Name: "name", SourceFile: "modules/sub/variables.tf"},
+		{Name: "nested", SourceFile: "modules/sub/modules/inner/variables.tf"},
+		{Name: "root", SourceFile: "variables.tf"},
+	}
+
+	got := ownVariables(module, variables)
+	if len(got) != 1 || got[0].Name != "name" {
+		t.Errorf("ownVariables = %+v, want only name", got)
+	}
+}
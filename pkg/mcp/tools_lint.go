@@ -0,0 +1,517 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/formatter"
+)
+
+// sensitiveAttributeNames lists resource attributes the azurerm provider marks
+// as sensitive. Matching is on the final traversal segment only to keep the
+// check conservative.
+var sensitiveAttributeNames = map[string]struct{}{
+	"primary_access_key":                {},
+	"secondary_access_key":              {},
+	"primary_connection_string":         {},
+	"secondary_connection_string":       {},
+	"primary_blob_connection_string":    {},
+	"secondary_blob_connection_string":  {},
+	"primary_key":                       {},
+	"secondary_key":                     {},
+	"primary_shared_key":                {},
+	"secondary_shared_key":              {},
+	"default_primary_connection_string": {},
+	"connection_string":                 {},
+	"administrator_login_password":      {},
+	"admin_password":                    {},
+	"client_secret":                     {},
+	"client_certificate":                {},
+	"client_key":                        {},
+	"kube_config":                       {},
+	"kube_config_raw":                   {},
+	"kube_admin_config":                 {},
+	"kube_admin_config_raw":             {},
+	"instrumentation_key":               {},
+	"private_key_pem":                   {},
+	"private_key_openssh":               {},
+}
+
+// reservedNames maps identifiers Terraform rejects or that read ambiguously as
+// declaration names to the reason they are flagged.
+var reservedNames = map[string]string{
+	"source":     "reserved meta-argument; Terraform rejects it as a variable name",
+	"version":    "reserved meta-argument; Terraform rejects it as a variable name",
+	"providers":  "reserved meta-argument; Terraform rejects it as a variable name",
+	"count":      "reserved meta-argument; Terraform rejects it as a variable name",
+	"for_each":   "reserved meta-argument; Terraform rejects it as a variable name",
+	"lifecycle":  "reserved meta-argument; Terraform rejects it as a variable name",
+	"depends_on": "reserved meta-argument; Terraform rejects it as a variable name",
+	"locals":     "reserved block name; Terraform rejects it as a variable name",
+	"provider":   "shadows a meta-argument and reads ambiguously",
+	"module":     "shadows the module namespace",
+	"terraform":  "shadows the terraform namespace",
+	"data":       "shadows the data source namespace",
+	"var":        "shadows the variable namespace",
+	"local":      "shadows the local value namespace",
+	"each":       "shadows the for_each iterator",
+	"self":       "shadows the provisioner self reference",
+	"path":       "shadows the path namespace",
+}
+
+// fatalReservedNames are only rejected by Terraform for variables; outputs
+// using them are still flagged as confusing.
+var fatalReservedNames = map[string]struct{}{
+	"source": {}, "version": {}, "providers": {}, "count": {},
+	"for_each": {}, "lifecycle": {}, "depends_on": {}, "locals": {},
+}
+
+func (s *Server) handleLintReservedNames(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load variables: %v", err))
+	}
+	outputs, err := s.db.GetModuleOutputs(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load outputs: %v", err))
+	}
+	variables, outputs = ownVariables(module, variables), ownOutputs(module, outputs)
+
+	findings := findReservedNames(variables, outputs)
+	text := formatter.ReservedNames(module.Name, len(variables)+len(outputs), findings)
+	return SuccessResponse(text)
+}
+
+func findReservedNames(variables []database.ModuleVariable, outputs []database.ModuleOutput) []formatter.ReservedNameFinding {
+	var findings []formatter.ReservedNameFinding
+	for _, v := range variables {
+		if reason, ok := reservedNames[strings.ToLower(v.Name)]; ok {
+			findings = append(findings, formatter.ReservedNameFinding{Kind: "variable", Name: v.Name, Reason: reason})
+		}
+	}
+	for _, o := range outputs {
+		name := strings.ToLower(o.Name)
+		reason, ok := reservedNames[name]
+		if !ok {
+			continue
+		}
+		if _, fatal := fatalReservedNames[name]; fatal {
+			reason = "meta-argument name; confusing when read as module." + o.Name
+		}
+		findings = append(findings, formatter.ReservedNameFinding{Kind: "output", Name: o.Name, Reason: reason})
+	}
+	return findings
+}
+
+var (
+	readmeInputsHeading = regexp.MustCompile(`(?i)^#{1,6}\s*inputs\s*$`)
+	readmeInputLink     = regexp.MustCompile(`\[([^\]]+)\]\(#input`)
+	htmlTag             = regexp.MustCompile(`<[^>]*>`)
+)
+
+func (s *Server) handleAuditReadmeInputs(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting files: %v", err))
+	}
+
+	rootPrefix := moduleRootPrefix(module.Name)
+	readme := module.ReadmeContent
+	if strings.TrimSpace(readme) == "" {
+		for _, f := range files {
+			if strings.EqualFold(f.FilePath, rootPrefix+"README.md") {
+				readme = f.Content
+				break
+			}
+		}
+	}
+
+	documented, found := parseReadmeInputs(readme)
+	if !found {
+		return ErrorResponse(fmt.Sprintf("No inputs table found in the README of module '%s'", module.Name))
+	}
+
+	var declared []string
+	for _, block := range variableBlocks(moduleOwnFiles(files, rootPrefix)) {
+		declared = append(declared, block.Labels[0])
+	}
+
+	audit := diffReadmeInputs(declared, documented)
+	text := formatter.ReadmeInputsAudit(module.Name, audit)
+	return SuccessResponse(text)
+}
+
+// parseReadmeInputs extracts input names from the first table under an
+// "Inputs" heading, as generated by terraform-docs.
+func parseReadmeInputs(readme string) ([]string, bool) {
+	var names []string
+	inSection := false
+	found := false
+	for _, line := range strings.Split(readme, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			if inSection {
+				break
+			}
+			inSection = readmeInputsHeading.MatchString(trimmed)
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "|") {
+			if found && trimmed != "" {
+				break
+			}
+			continue
+		}
+
+		cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+		first := strings.TrimSpace(cells[0])
+		if !found {
+			// Header row; the separator row follows.
+			found = true
+			continue
+		}
+		if strings.Trim(first, "-: ") == "" {
+			continue
+		}
+
+		name := first
+		if m := readmeInputLink.FindStringSubmatch(first); m != nil {
+			name = m[1]
+		} else {
+			name = htmlTag.ReplaceAllString(name, "")
+		}
+		name = strings.Trim(strings.ReplaceAll(name, `\_`, "_"), "` ")
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, found
+}
+
+func diffReadmeInputs(declared, documented []string) formatter.ReadmeInputsDiff {
+	declaredSet := make(map[string]struct{}, len(declared))
+	for _, name := range declared {
+		declaredSet[name] = struct{}{}
+	}
+	documentedSet := make(map[string]struct{}, len(documented))
+	for _, name := range documented {
+		documentedSet[name] = struct{}{}
+	}
+
+	diff := formatter.ReadmeInputsDiff{Declared: len(declaredSet), Documented: len(documentedSet)}
+	for name := range declaredSet {
+		if _, ok := documentedSet[name]; !ok {
+			diff.Undocumented = append(diff.Undocumented, name)
+		}
+	}
+	for name := range documentedSet {
+		if _, ok := declaredSet[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	sort.Strings(diff.Undocumented)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+func (s *Server) handleCheckSensitivePropagation(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load variables: %v", err))
+	}
+	outputs, err := s.db.GetModuleOutputs(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load outputs: %v", err))
+	}
+	rels, err := s.db.GetModuleRelationshipsByBlockType(module.ID, "output")
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load output references: %v", err))
+	}
+	variables, outputs = ownVariables(module, variables), ownOutputs(module, outputs)
+	rels = ownRelationships(module, rels)
+
+	findings := findSensitiveLeaks(variables, outputs, rels)
+	text := formatter.SensitivePropagation(module.Name, len(outputs), findings)
+	return SuccessResponse(text)
+}
+
+func findSensitiveLeaks(variables []database.ModuleVariable, outputs []database.ModuleOutput, rels []database.HCLRelationship) []formatter.SensitiveLeak {
+	sensitiveVars := make(map[string]struct{})
+	for _, v := range variables {
+		if v.Sensitive {
+			sensitiveVars[v.Name] = struct{}{}
+		}
+	}
+
+	protected := make(map[string]bool, len(outputs))
+	for _, o := range outputs {
+		protected[o.Name] = protected[o.Name] || o.Sensitive
+	}
+
+	seen := make(map[string]struct{})
+	var findings []formatter.SensitiveLeak
+	for _, rel := range rels {
+		outputName := rel.BlockLabels
+		if sensitive, known := protected[outputName]; !known || sensitive {
+			continue
+		}
+
+		segments := strings.Split(rel.ReferenceName, ".")
+		reason := ""
+		switch rel.ReferenceType {
+		case "variable":
+			if len(segments) > 1 {
+				if _, ok := sensitiveVars[segments[1]]; ok {
+					reason = fmt.Sprintf("sensitive variable `%s`", segments[1])
+				}
+			}
+		case "resource", "data_source":
+			last := segments[len(segments)-1]
+			if _, ok := sensitiveAttributeNames[last]; ok && len(segments) > 2 {
+				reason = fmt.Sprintf("sensitive attribute `%s`", last)
+			}
+		}
+		if reason == "" {
+			continue
+		}
+
+		key := outputName + "|" + rel.ReferenceName
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		findings = append(findings, formatter.SensitiveLeak{
+			OutputName: outputName,
+			Reference:  rel.ReferenceName,
+			Reason:     reason,
+			FilePath:   rel.FilePath,
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].OutputName < findings[j].OutputName
+	})
+	return findings
+}
+
+type unpinnedProvidersArgs struct {
+	ModuleName string `json:"module_name"`
+}
+
+func (s *Server) handleFindUnpinnedProviders(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[unpinnedProvidersArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+
+	providers, err := s.db.ListModuleProviders()
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load providers: %v", err))
+	}
+
+	scope := ""
+	if name := strings.TrimSpace(params.ModuleName); name != "" {
+		module, err := s.resolveModule(name)
+		if err != nil {
+			return moduleNotFound(name, err)
+		}
+		scope = module.Name
+		filtered := providers[:0]
+		for _, p := range providers {
+			if p.ModuleID == module.ID {
+				filtered = append(filtered, p)
+			}
+		}
+		providers = filtered
+	}
+
+	modules := make(map[string]struct{})
+	var findings []formatter.UnpinnedProviderFinding
+	for _, p := range providers {
+		modules[p.ModuleName] = struct{}{}
+		reason, loose := providerConstraintLaxity(p.Version)
+		if !loose {
+			continue
+		}
+		findings = append(findings, formatter.UnpinnedProviderFinding{
+			ModuleName: p.ModuleName,
+			Provider:   p.Name,
+			Source:     p.Source,
+			Constraint: p.Version,
+			Reason:     reason,
+		})
+	}
+
+	return SuccessResponse(formatter.UnpinnedProviders(scope, len(modules), len(providers), findings))
+}
+
+// providerConstraintLaxity reports whether a required_providers version
+// constraint lets a new major provider release in, and why.
+func providerConstraintLaxity(constraint string) (string, bool) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return "no version constraint", true
+	}
+
+	hasUpper := false
+	zeroFloor := false
+	for clause := range strings.SplitSeq(constraint, ",") {
+		clause = strings.TrimSpace(clause)
+		op, version := splitConstraintOperator(clause)
+		switch op {
+		case "<", "<=", "~>", "=", "":
+			hasUpper = true
+		case ">=", ">":
+			if strings.Trim(version, "0.") == "" {
+				zeroFloor = true
+			}
+		}
+	}
+
+	switch {
+	case hasUpper:
+		return "", false
+	case zeroFloor:
+		return "lower bound of 0 accepts any version", true
+	default:
+		return "no upper bound; the next major release is picked up automatically", true
+	}
+}
+
+func splitConstraintOperator(clause string) (string, string) {
+	for _, op := range []string{">=", "<=", "~>", "!=", ">", "<", "="} {
+		if rest, ok := strings.CutPrefix(clause, op); ok {
+			return op, strings.TrimSpace(rest)
+		}
+	}
+	return "", clause
+}
+
+func (s *Server) handleListParseErrors(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil {
+		return ErrorResponse("Error: Invalid parameters")
+	}
+
+	var moduleID int64
+	scope := ""
+	if name := strings.TrimSpace(params.ModuleName); name != "" {
+		module, err := s.resolveModule(name)
+		if err != nil {
+			return moduleNotFound(name, err)
+		}
+		moduleID = module.ID
+		scope = module.Name
+	}
+
+	parseErrors, err := s.db.ListParseErrors(moduleID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load parse errors: %v", err))
+	}
+
+	return SuccessResponse(formatter.ParseErrors(scope, parseErrors))
+}
+
+// moduleHealthChecks are evaluated in order; the labels appear verbatim in the
+// report.
+var moduleHealthChecks = []struct {
+	label  string
+	failed func(database.ModuleHealth) bool
+}{
+	{"no examples", func(h database.ModuleHealth) bool { return !h.HasExamples }},
+	{"no README", func(h database.ModuleHealth) bool { return !h.HasReadme }},
+	{"no variables", func(h database.ModuleHealth) bool { return h.VariableCount == 0 }},
+	{"no outputs", func(h database.ModuleHealth) bool { return h.OutputCount == 0 }},
+}
+
+func (s *Server) handleModuleHealthReport() map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	health, err := s.db.ListModuleHealth()
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load modules: %v", err))
+	}
+	if len(health) == 0 {
+		return SuccessResponse("No modules found. Run sync_modules tool to fetch modules from GitHub.")
+	}
+
+	checkCounts := make([]formatter.HealthCheckCount, len(moduleHealthChecks))
+	for i, check := range moduleHealthChecks {
+		checkCounts[i].Check = check.label
+	}
+
+	var findings []formatter.ModuleHealthFinding
+	for _, h := range health {
+		var failed []string
+		for i, check := range moduleHealthChecks {
+			if check.failed(h) {
+				failed = append(failed, check.label)
+				checkCounts[i].Modules++
+			}
+		}
+		if len(failed) > 0 {
+			findings = append(findings, formatter.ModuleHealthFinding{ModuleName: h.ModuleName, Failed: failed})
+		}
+	}
+
+	return SuccessResponse(formatter.ModuleHealthReport(len(health), checkCounts, findings))
+}
@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+)
+
+func TestFindSensitiveLeaksOwnFiles(t *testing.T) {
+	module := &database.Module{Name: "terraform-azure-demo"}
+	variables := []database.ModuleVariable{
+		{Name: "admin_password", Sensitive: true, SourceFile: "variables.tf"},
+	}
+	outputs := []database.ModuleOutput{
+		{Name: "password", Sensitive: true, SourceFile: "examples/complete/outputs.tf"},
+		{Name: "password", SourceFile: "outputs.tf"},
+		{Name: "example_password", SourceFile: "examples/complete/outputs.tf"},
+	}
+	rels := []database.HCLRelationship{
+		{FilePath: "examples/complete/outputs.tf", BlockLabels: "password", ReferenceType: "variable", ReferenceName: "var.admin_password"},
+		{FilePath: "outputs.tf", BlockLabels: "password", ReferenceType: "variable", ReferenceName: "var.admin_password"},
+		{FilePath: "examples/complete/outputs.tf", BlockLabels: "example_password", ReferenceType: "variable", ReferenceName: "var.admin_password"},
+	}
+
+	leaks := findSensitiveLeaks(ownVariables(module, variables), ownOutputs(module, outputs), ownRelationships(module, rels))
+
+	if len(leaks) != 1 {
+		t.Fatalf("leaks = %+v, want one", leaks)
+	}
+	if leaks[0].OutputName != "password" || leaks[0].FilePath != "outputs.tf" {
+		t.Errorf("leak = %+v, want password in outputs.tf", leaks[0])
+	}
+}
@@ -0,0 +1,994 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/formatter"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/schema"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/util"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type moduleNameArgs struct {
+	ModuleName string `json:"module_name"`
+}
+
+type variableFieldsArgs struct {
+	ModuleName   string `json:"module_name"`
+	VariableName string `json:"variable_name"`
+}
+
+type resourceAttributesArgs struct {
+	ModuleName   string `json:"module_name"`
+	ResourceType string `json:"resource_type"`
+}
+
+type complexVariablesArgs struct {
+	ModuleName string `json:"module_name"`
+	Limit      int    `json:"limit"`
+}
+
+func (s *Server) handleGetTerraformVersion(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	versions, err := s.db.GetModuleTerraformVersions(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load terraform version: %v", err))
+	}
+
+	// Constraints declared by the module itself win over the ones in examples.
+	sort.SliceStable(versions, func(i, j int) bool {
+		return !isExamplePath(versions[i].SourceFile) && isExamplePath(versions[j].SourceFile)
+	})
+
+	text := formatter.TerraformVersion(module.Name, versions)
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleListVariableFields(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[variableFieldsArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" || strings.TrimSpace(params.VariableName) == "" {
+		return ErrorResponse("module_name and variable_name are required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load variables: %v", err))
+	}
+	variables = ownVariables(module, variables)
+
+	var variable *database.ModuleVariable
+	for i := range variables {
+		if variables[i].Name == params.VariableName {
+			variable = &variables[i]
+			break
+		}
+	}
+	if variable == nil {
+		return ErrorResponse(fmt.Sprintf("Variable '%s' not found in module '%s'", params.VariableName, module.Name))
+	}
+
+	parsed, err := schema.Parse(variable.Type)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to parse type of variable '%s': %v", variable.Name, err))
+	}
+
+	text := formatter.VariableFields(module.Name, variable.Name, parsed.String(), parsed.Flatten(variable.Name))
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleListModuleOutputsWithValues(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	outputs, err := s.db.GetModuleOutputs(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load outputs: %v", err))
+	}
+
+	text := formatter.OutputsWithValues(module.Name, ownOutputs(module, outputs))
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleGetPublicAPI(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load variables: %v", err))
+	}
+	outputs, err := s.db.GetModuleOutputs(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load outputs: %v", err))
+	}
+	variables, outputs = ownVariables(module, variables), ownOutputs(module, outputs)
+
+	sort.Slice(variables, func(i, j int) bool { return variables[i].Name < variables[j].Name })
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i].Name < outputs[j].Name })
+
+	text := formatter.PublicAPI(module.Name, publicAPIHash(variables, outputs), variables, outputs)
+	return SuccessResponse(text)
+}
+
+// publicAPIHash fingerprints the parts of the interface callers depend on:
+// variable names, types, defaults, sensitivity and nullability, and output
+// names and sensitivity. Descriptions are left out so doc edits keep the hash
+// stable. Inputs must be sorted by name.
+func publicAPIHash(variables []database.ModuleVariable, outputs []database.ModuleOutput) string {
+	h := sha256.New()
+	for _, v := range variables {
+		fmt.Fprintf(h, "variable\x00%s\x00%s\x00%t\x00%s\x00%t\x00%t\n",
+			v.Name, collapseWhitespace(v.Type), v.Required, collapseWhitespace(v.DefaultValue), v.Sensitive, v.Nullable)
+	}
+	for _, o := range outputs {
+		fmt.Fprintf(h, "output\x00%s\x00%t\n", o.Name, o.Sensitive)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *Server) handleGetModuleDataSources(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	dataSources, err := s.db.GetModuleDataSources(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load data sources: %v", err))
+	}
+
+	sort.Slice(dataSources, func(i, j int) bool {
+		if dataSources[i].DataType != dataSources[j].DataType {
+			return dataSources[i].DataType < dataSources[j].DataType
+		}
+		return dataSources[i].DataName < dataSources[j].DataName
+	})
+
+	text := formatter.DataSources(module.Name, dataSources)
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleListSubmodules(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	// Submodules hang off the repository root, so a submodule name lists its siblings.
+	parent, _, _ := strings.Cut(module.Name, "//")
+	submodules, err := s.db.ListSubmodules(parent)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load submodules: %v", err))
+	}
+
+	text := formatter.Submodules(parent, submodules)
+	return SuccessResponse(text)
+}
+
+func (s *Server) handleGetResourceAttributes(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[resourceAttributesArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	resources, err := s.db.GetModuleResources(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load resources: %v", err))
+	}
+
+	resourceType := strings.TrimSpace(params.ResourceType)
+	if resourceType != "" {
+		filtered := resources[:0]
+		for _, r := range resources {
+			if r.ResourceType == resourceType {
+				filtered = append(filtered, r)
+			}
+		}
+		resources = filtered
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].ResourceType != resources[j].ResourceType {
+			return resources[i].ResourceType < resources[j].ResourceType
+		}
+		return resources[i].ResourceName < resources[j].ResourceName
+	})
+
+	return SuccessResponse(formatter.ResourceAttributes(module.Name, resourceType, resources))
+}
+
+type extractBlockArgs struct {
+	ModuleName string `json:"module_name"`
+	BlockType  string `json:"block_type"`
+	Identifier string `json:"identifier"`
+}
+
+func (s *Server) handleExtractBlock(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[extractBlockArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" || strings.TrimSpace(params.BlockType) == "" {
+		return ErrorResponse("module_name and block_type are required")
+	}
+
+	blockType := strings.ToLower(strings.TrimSpace(params.BlockType))
+	typeLabel, nameLabel, address, err := parseBlockIdentifier(blockType, strings.TrimSpace(params.Identifier))
+	if err != nil {
+		return ErrorResponse(err.Error())
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	// Locals blocks carry no label; the local value name is matched below.
+	labelFilter := nameLabel
+	if blockType == "locals" {
+		labelFilter = ""
+	}
+	found, err := s.db.FindModuleBlocks(module.ID, blockType, typeLabel, labelFilter)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load blocks: %v", err))
+	}
+
+	files := make(map[string]*database.ModuleFile)
+	var blocks []formatter.SourceBlock
+	for _, b := range found {
+		if blockType == "locals" && nameLabel != "" && !definesLocal(b, nameLabel) {
+			continue
+		}
+		file, ok := files[b.FilePath]
+		if !ok {
+			if file, err = s.db.GetFile(module.Name, b.FilePath); err != nil {
+				continue
+			}
+			files[b.FilePath] = file
+		}
+		code := sliceSource(file.Content, int(b.StartByte), int(b.EndByte))
+		blocks = append(blocks, formatter.SourceBlock{FilePath: b.FilePath, Code: code})
+	}
+	if len(blocks) == 0 {
+		return ErrorResponse(fmt.Sprintf("%s not found in %s (re-sync if the module was indexed by an older version)", address, module.Name))
+	}
+
+	return SuccessResponse(formatter.ExtractedBlocks(module.Name, address, blocks))
+}
+
+// parseBlockIdentifier turns an extract_block identifier into type and name
+// labels. Resources and data sources take "type.name" (or just "type" for all
+// blocks of that type), variables and outputs their name, and locals an
+// optional local value name. Terraform reference prefixes such as var. or
+// data. are accepted.
+func parseBlockIdentifier(blockType, identifier string) (typeLabel, nameLabel, address string, err error) {
+	switch blockType {
+	case "resource", "data":
+		identifier = strings.TrimPrefix(identifier, "data.")
+		if identifier == "" {
+			return "", "", "", fmt.Errorf("identifier is required for %s blocks (e.g., azurerm_storage_account.this)", blockType)
+		}
+		typeLabel, nameLabel, _ = strings.Cut(identifier, ".")
+		address = fmt.Sprintf("%s %q", blockType, typeLabel)
+		if nameLabel != "" {
+			address += fmt.Sprintf(" %q", nameLabel)
+		}
+		return typeLabel, nameLabel, address, nil
+	case "variable", "output":
+		identifier = strings.TrimPrefix(strings.TrimPrefix(identifier, "var."), "output.")
+		if identifier == "" {
+			return "", "", "", fmt.Errorf("identifier is required for %s blocks", blockType)
+		}
+		return "", identifier, fmt.Sprintf("%s %q", blockType, identifier), nil
+	case "locals":
+		identifier = strings.TrimPrefix(identifier, "local.")
+		if identifier == "" {
+			return "", "", "locals", nil
+		}
+		return "", identifier, "local." + identifier, nil
+	}
+	return "", "", "", fmt.Errorf("unsupported block_type %q (expected variable, output, resource, data or locals)", blockType)
+}
+
+// definesLocal reports whether a locals block assigns the named local value.
+func definesLocal(b database.HCLBlock, name string) bool {
+	for path := range strings.SplitSeq(b.AttrPaths.String, "\n") {
+		if path == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleGetModuleProviders(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	providers, err := s.db.GetModuleProviders(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load providers: %v", err))
+	}
+
+	return SuccessResponse(formatter.ModuleProviders(module.Name, providers))
+}
+
+type moduleReadmeArgs struct {
+	ModuleName string `json:"module_name"`
+	Section    string `json:"section"`
+}
+
+func (s *Server) handleGetModuleReadme(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleReadmeArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	readme := module.ReadmeContent
+	if strings.TrimSpace(readme) == "" {
+		if file, err := s.db.GetFile(module.Name, "README.md"); err == nil {
+			readme = file.Content
+		}
+	}
+	if strings.TrimSpace(readme) == "" {
+		return ErrorResponse(fmt.Sprintf("Module '%s' has no README", module.Name))
+	}
+
+	section := strings.TrimSpace(params.Section)
+	if section == "" {
+		return SuccessResponse(formatter.ModuleReadme(module.Name, readme))
+	}
+
+	// An exact heading wins over headings that merely contain the text.
+	sections := readmeSections(readme, func(heading string) bool {
+		return strings.EqualFold(heading, section)
+	})
+	if len(sections) == 0 {
+		sections = readmeSections(readme, func(heading string) bool {
+			return strings.Contains(strings.ToLower(heading), strings.ToLower(section))
+		})
+	}
+	if len(sections) == 0 {
+		return ErrorResponse(formatter.ReadmeSectionNotFound(module.Name, section, readmeHeadings(readme)))
+	}
+
+	return SuccessResponse(formatter.ReadmeSections(module.Name, sections))
+}
+
+// readmeHeadings lists the headings of a README outside fenced code blocks.
+func readmeHeadings(readme string) []string {
+	var (
+		headings []string
+		inFence  bool
+	)
+	for _, line := range strings.Split(readme, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := readmeHeading.FindStringSubmatch(trimmed); m != nil {
+			headings = append(headings, m[2])
+		}
+	}
+	return headings
+}
+
+// readmeFirstParagraph returns the first prose paragraph of a README joined
+// into one line, skipping headings, images, HTML and fenced code.
+func readmeFirstParagraph(readme string) string {
+	var (
+		paragraph []string
+		inFence   bool
+	)
+	for _, line := range strings.Split(readme, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if trimmed == "" || readmeHeading.MatchString(trimmed) ||
+			strings.HasPrefix(trimmed, "![") || strings.HasPrefix(trimmed, "[![") || strings.HasPrefix(trimmed, "<") {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+	return strings.Join(paragraph, " ")
+}
+
+func (s *Server) handleGetModuleTree(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting files: %v", err))
+	}
+
+	// Submodule files are stored on their own records, so the repository
+	// root pulls them back in to show the full layout.
+	if !strings.Contains(module.Name, "//") {
+		submodules, err := s.db.ListSubmodules(module.Name)
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Failed to load submodules: %v", err))
+		}
+		for _, sub := range submodules {
+			subFiles, err := s.db.GetModuleFiles(sub.ID)
+			if err != nil {
+				return ErrorResponse(fmt.Sprintf("Error getting files for %s: %v", sub.Name, err))
+			}
+			files = append(files, subFiles...)
+		}
+	}
+
+	rootPrefix := moduleRootPrefix(module.Name)
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		paths = append(paths, strings.TrimPrefix(f.FilePath, rootPrefix))
+	}
+
+	return SuccessResponse(formatter.ModuleTree(module.Name, paths))
+}
+
+type moduleDefaultsArgs struct {
+	ModuleName string `json:"module_name"`
+	Format     string `json:"format"`
+}
+
+func (s *Server) handleGetModuleDefaults(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleDefaultsArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	format := strings.ToLower(strings.TrimSpace(params.Format))
+	if format != "" && format != "markdown" && format != "hcl" {
+		return ErrorResponse(fmt.Sprintf("Unsupported format '%s'; use 'markdown' or 'hcl'", params.Format))
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting variables: %v", err))
+	}
+	variables = ownVariables(module, variables)
+
+	if format == "hcl" {
+		return SuccessResponse(formatter.ModuleDefaultsHCL(module.Name, variables))
+	}
+	return SuccessResponse(formatter.ModuleDefaults(module.Name, variables))
+}
+
+func (s *Server) handleGetModuleLocals(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	locals, err := s.db.GetModuleLocals(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load locals: %v", err))
+	}
+
+	return SuccessResponse(formatter.ModuleLocals(module.Name, locals))
+}
+
+func (s *Server) handleGetModuleDependencies(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	dependencies, err := s.db.GetModuleDependencies(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load dependencies: %v", err))
+	}
+
+	calls := make([]formatter.ModuleCall, 0, len(dependencies))
+	for _, d := range dependencies {
+		call := formatter.ModuleCall{ModuleDependency: d}
+		if target := dependencyTarget(module.Name, d); target != "" {
+			if _, err := s.db.GetModule(target); err == nil {
+				call.Target = target
+			}
+		}
+		calls = append(calls, call)
+	}
+
+	return SuccessResponse(formatter.ModuleDependencies(module.Name, calls))
+}
+
+// dependencyTarget maps a module call to the catalog name it would resolve
+// to: a sibling submodule for local sources, the repository for git and
+// registry sources.
+func dependencyTarget(moduleName string, d database.ModuleDependency) string {
+	switch d.SourceKind {
+	case "local":
+		parent, subPath, _ := strings.Cut(moduleName, "//")
+		target := path.Clean(path.Join(subPath, d.Source))
+		if !strings.HasPrefix(target, "modules/") {
+			return ""
+		}
+		return parent + "//" + target
+	case "git":
+		if m := exampleSourceRepoPattern.FindStringSubmatch(d.Source); m != nil {
+			return m[1]
+		}
+	case "registry":
+		source, _, _ := strings.Cut(d.Source, "//")
+		if parts := strings.Split(source, "/"); len(parts) == 4 {
+			source = strings.Join(parts[1:], "/")
+		}
+		return registryModuleName(source)
+	}
+	return ""
+}
+
+// quotedLiteralPattern captures double-quoted string literals in HCL source.
+var quotedLiteralPattern = regexp.MustCompile(`"([^"\n]*)"`)
+
+func (s *Server) handleGetSupportedRegions(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting files: %v", err))
+	}
+
+	report := supportedRegions(moduleOwnFiles(files, moduleRootPrefix(module.Name)))
+	text := formatter.SupportedRegions(module.Name, report)
+	return SuccessResponse(text)
+}
+
+// supportedRegions inspects variable declarations for location inputs,
+// region literals in their defaults and region allowlists in their
+// validation conditions.
+func supportedRegions(files []database.ModuleFile) formatter.SupportedRegionsReport {
+	var report formatter.SupportedRegionsReport
+	allowed := make(map[string]struct{})
+
+	for _, f := range files {
+		file, diags := hclsyntax.ParseConfig([]byte(f.Content), f.FilePath, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			continue
+		}
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		src := []byte(f.Content)
+
+		for _, block := range body.Blocks {
+			if block.Type != "variable" || len(block.Labels) == 0 {
+				continue
+			}
+			name := block.Labels[0]
+
+			if isLocationName(name) {
+				input := formatter.RegionInput{Path: name}
+				if attr, ok := block.Body.Attributes["default"]; ok {
+					input.Default = regionLiteral(string(attr.Expr.Range().SliceBytes(src)))
+				} else {
+					input.Required = true
+				}
+				report.Inputs = append(report.Inputs, input)
+			}
+
+			if attr, ok := block.Body.Attributes["type"]; ok {
+				if typ, err := schema.Parse(string(attr.Expr.Range().SliceBytes(src))); err == nil {
+					_, hasDefault := block.Body.Attributes["default"]
+					for _, field := range typ.Flatten(name) {
+						if !isLocationName(lastPathSegment(field.Path)) {
+							continue
+						}
+						report.Inputs = append(report.Inputs, formatter.RegionInput{
+							Path:     field.Path,
+							Required: !field.Optional && !hasDefault,
+							Default:  regionLiteral(field.Default),
+						})
+					}
+				}
+			}
+
+			for _, inner := range block.Body.Blocks {
+				if inner.Type != "validation" {
+					continue
+				}
+				cond, ok := inner.Body.Attributes["condition"]
+				if !ok {
+					continue
+				}
+				regions := regionLiterals(string(cond.Expr.Range().SliceBytes(src)))
+				if len(regions) == 0 {
+					continue
+				}
+				report.ValidatedBy = append(report.ValidatedBy, name)
+				for _, region := range regions {
+					allowed[region] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for region := range allowed {
+		report.Allowlist = append(report.Allowlist, region)
+	}
+	sort.Strings(report.Allowlist)
+	return report
+}
+
+func isLocationName(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "location") || strings.Contains(name, "region")
+}
+
+func lastPathSegment(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+// regionLiteral returns the Azure region named by a default expression, or ""
+// when the default is not a single region literal.
+func regionLiteral(expr string) string {
+	return util.NormalizeAzureRegion(strings.Trim(strings.TrimSpace(expr), `"`))
+}
+
+func regionLiterals(src string) []string {
+	var regions []string
+	seen := make(map[string]struct{})
+	for _, m := range quotedLiteralPattern.FindAllStringSubmatch(src, -1) {
+		region := util.NormalizeAzureRegion(m[1])
+		if region == "" {
+			continue
+		}
+		if _, ok := seen[region]; ok {
+			continue
+		}
+		seen[region] = struct{}{}
+		regions = append(regions, region)
+	}
+	return regions
+}
+
+// versionsFileCandidates are the conventional names for the file holding the
+// terraform block, checked in order.
+var versionsFileCandidates = []string{"versions.tf", "terraform.tf", "providers.tf"}
+
+func (s *Server) handleGetModuleVersionsFile(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load files: %v", err))
+	}
+
+	file, matchedBy := findVersionsFile(files, moduleRootPrefix(module.Name))
+	if file == nil {
+		return ErrorResponse(fmt.Sprintf("No file declaring version constraints found in module '%s'", module.Name))
+	}
+
+	text := formatter.VersionsFile(module.Name, file.FilePath, matchedBy, file.Content)
+	return SuccessResponse(text)
+}
+
+// moduleRootPrefix returns the repository-relative directory of a module's
+// own files: empty for root modules, "modules/<name>/" for submodules.
+func moduleRootPrefix(moduleName string) string {
+	if _, sub, ok := strings.Cut(moduleName, "//"); ok {
+		return sub + "/"
+	}
+	return ""
+}
+
+// ownVariables keeps the variables declared in the module's own files,
+// dropping the example and nested module declarations indexed alongside
+// them. Rows without a source file predate its indexing and are kept.
+func ownVariables(module *database.Module, variables []database.ModuleVariable) []database.ModuleVariable {
+	rootPrefix := moduleRootPrefix(module.Name)
+	own := make([]database.ModuleVariable, 0, len(variables))
+	seen := make(map[string]struct{}, len(variables))
+	for _, v := range variables {
+		if v.SourceFile != "" && !isOwnTerraformFile(v.SourceFile, rootPrefix) {
+			continue
+		}
+		if _, dup := seen[v.Name]; dup {
+			continue
+		}
+		seen[v.Name] = struct{}{}
+		own = append(own, v)
+	}
+	return own
+}
+
+// ownOutputs is the ownVariables counterpart for outputs.
+func ownOutputs(module *database.Module, outputs []database.ModuleOutput) []database.ModuleOutput {
+	rootPrefix := moduleRootPrefix(module.Name)
+	own := make([]database.ModuleOutput, 0, len(outputs))
+	seen := make(map[string]struct{}, len(outputs))
+	for _, o := range outputs {
+		if o.SourceFile != "" && !isOwnTerraformFile(o.SourceFile, rootPrefix) {
+			continue
+		}
+		if _, dup := seen[o.Name]; dup {
+			continue
+		}
+		seen[o.Name] = struct{}{}
+		own = append(own, o)
+	}
+	return own
+}
+
+// ownRelationships keeps the references made from the module's own files.
+func ownRelationships(module *database.Module, rels []database.HCLRelationship) []database.HCLRelationship {
+	rootPrefix := moduleRootPrefix(module.Name)
+	own := make([]database.HCLRelationship, 0, len(rels))
+	for _, rel := range rels {
+		if rel.FilePath != "" && !isOwnTerraformFile(rel.FilePath, rootPrefix) {
+			continue
+		}
+		own = append(own, rel)
+	}
+	return own
+}
+
+func findVersionsFile(files []database.ModuleFile, rootPrefix string) (*database.ModuleFile, string) {
+	for _, candidate := range versionsFileCandidates {
+		for i := range files {
+			if files[i].FilePath == rootPrefix+candidate {
+				return &files[i], "conventional file name"
+			}
+		}
+	}
+
+	for i := range files {
+		f := &files[i]
+		if f.FileType != "terraform" || !strings.HasPrefix(f.FilePath, rootPrefix) {
+			continue
+		}
+		if strings.Contains(strings.TrimPrefix(f.FilePath, rootPrefix), "/") {
+			continue
+		}
+		if strings.Contains(f.Content, "required_providers") {
+			return f, "required_providers block"
+		}
+	}
+
+	return nil, ""
+}
+
+func isExamplePath(filePath string) bool {
+	return strings.HasPrefix(filePath, "examples/")
+}
+
+func (s *Server) handleGetComplexVariables(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[complexVariablesArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	variables, err := s.db.GetModuleVariables(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load variables: %v", err))
+	}
+
+	variables = ownVariables(module, variables)
+	var (
+		ranked   []formatter.VariableComplexity
+		unparsed []formatter.UnparsedType
+	)
+	for _, v := range variables {
+		parsed, err := schema.Parse(v.Type)
+		if err != nil {
+			unparsed = append(unparsed, formatter.UnparsedType{Name: v.Name, Type: v.Type})
+			continue
+		}
+		if parsed.IsPrimitive() {
+			continue
+		}
+		ranked = append(ranked, formatter.VariableComplexity{
+			Name:       v.Name,
+			Score:      parsed.Complexity(),
+			Depth:      parsed.Depth(),
+			FieldCount: parsed.FieldCount(),
+			Shape:      parsed.Shape(),
+		})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	text := formatter.ComplexVariables(module.Name, len(variables), ranked, unparsed)
+	return SuccessResponse(text)
+}
@@ -1,23 +1,35 @@
 package mcp
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/formatter"
 	"github.com/cloudnationhq/az-cn-go-wammcp/internal/indexer"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/util"
 )
 
 type releaseSummaryArgs struct {
-	ModuleName string `json:"module_name"`
-	Version    string `json:"version"`
+	ModuleName     string `json:"module_name"`
+	Version        string `json:"version"`
+	LatestStrategy string `json:"latest_strategy"`
 }
 
+// Strategies for resolving a module's "latest" release.
+const (
+	latestBySemver = "semver"
+	latestByDate   = "date"
+)
+
 type releaseSnippetArgs struct {
 	ModuleName    string `json:"module_name"`
 	Version       string `json:"version"`
@@ -26,12 +38,49 @@ type releaseSnippetArgs struct {
 	FallbackMatch string `json:"fallback_match"`
 }
 
+type fileDiffArgs struct {
+	ModuleName  string `json:"module_name"`
+	FilePath    string `json:"file_path"`
+	FromVersion string `json:"from_version"`
+	ToVersion   string `json:"to_version"`
+}
+
+type introducedInArgs struct {
+	ModuleName  string `json:"module_name"`
+	Item        string `json:"item"`
+	MaxReleases int    `json:"max_releases"`
+}
+
+type variableProvenanceArgs struct {
+	ModuleName   string `json:"module_name"`
+	VariableName string `json:"variable_name"`
+	MaxReleases  int    `json:"max_releases"`
+}
+
+type verifyChangelogArgs struct {
+	ModuleName string `json:"module_name"`
+	Version    string `json:"version"`
+}
+
+// changelogStopwords are words too generic to tie a changelog entry to a file.
+var changelogStopwords = map[string]struct{}{
+	"add": {}, "added": {}, "adds": {}, "support": {}, "update": {}, "updated": {},
+	"fix": {}, "fixed": {}, "fixes": {}, "remove": {}, "removed": {}, "change": {},
+	"changed": {}, "feature": {}, "features": {}, "module": {}, "modules": {},
+	"with": {}, "from": {}, "into": {}, "when": {}, "that": {}, "this": {},
+	"bump": {}, "version": {}, "azurerm": {}, "terraform": {}, "resource": {},
+}
+
+// minUnmentionedChangeLines is the diff size from which an unmentioned file
+// change is considered significant.
+const minUnmentionedChangeLines = 5
+
 type backfillReleaseArgs struct {
 	ModuleName string `json:"module_name"`
 	Version    string `json:"version"`
 }
 
-func (s *Server) handleGetReleaseSummary(args any) map[string]any {
+func (s *Server) handleGetReleaseSummary(ctx context.Context, args any) map[string]any {
 	if err := s.ensureDB(); err != nil {
 		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
 	}
@@ -43,7 +92,7 @@ func (s *Server) handleGetReleaseSummary(args any) map[string]any {
 
 	module, err := s.resolveModule(params.ModuleName)
 	if err != nil {
-		return ErrorResponse(fmt.Sprintf("Module '%s' not found", params.ModuleName))
+		return moduleNotFound(params.ModuleName, err)
 	}
 
 	var (
@@ -51,9 +100,17 @@ func (s *Server) handleGetReleaseSummary(args any) map[string]any {
 		entries []database.ModuleReleaseEntry
 	)
 
+	strategy, err := parseLatestStrategy(params.LatestStrategy)
+	if err != nil {
+		return ErrorResponse(err.Error())
+	}
+
 	version := strings.TrimSpace(params.Version)
 	if version == "" {
-		release, entries, err = s.db.GetLatestModuleReleaseWithEntries(module.ID)
+		release, err = s.resolveLatestRelease(module.ID, strategy)
+		if err == nil {
+			entries, err = s.db.GetModuleReleaseEntries(release.ID)
+		}
 	} else {
 		versionOnly := strings.TrimPrefix(version, "v")
 		release, entries, err = s.db.GetModuleReleaseWithEntriesByVersion(module.ID, versionOnly)
@@ -81,11 +138,16 @@ func (s *Server) handleGetReleaseSummary(args any) map[string]any {
 		name = module.Name
 	}
 
+	s.ensureReleaseContributors(ctx, module.FullName, release)
+
 	summary := formatter.ReleaseSummary(name, release, entries)
+	if version == "" {
+		summary += fmt.Sprintf("\n%s\n", latestStrategyNote(strategy))
+	}
 	return SuccessResponse(summary)
 }
 
-func (s *Server) handleGetReleaseSnippet(args any) map[string]any {
+func (s *Server) handleGetReleaseSnippet(ctx context.Context, args any) map[string]any {
 	if err := s.ensureDB(); err != nil {
 		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
 	}
@@ -100,7 +162,7 @@ func (s *Server) handleGetReleaseSnippet(args any) map[string]any {
 
 	module, err := s.resolveModule(params.ModuleName)
 	if err != nil {
-		return ErrorResponse(fmt.Sprintf("Module '%s' not found", params.ModuleName))
+		return moduleNotFound(params.ModuleName, err)
 	}
 
 	release, entries, err := s.lookupModuleRelease(module.ID, params.Version)
@@ -121,7 +183,7 @@ func (s *Server) handleGetReleaseSnippet(args any) map[string]any {
 		return ErrorResponse("Syncer is not initialized; run a sync first")
 	}
 
-	compare, err := s.syncer.CompareTags(module.FullName, release.PreviousTag.String, release.Tag)
+	compare, err := s.syncer.CompareTags(ctx, module.FullName, release.PreviousTag.String, release.Tag)
 	if err != nil {
 		return ErrorResponse(fmt.Sprintf("Failed to fetch GitHub compare diff: %v", err))
 	}
@@ -157,7 +219,7 @@ func (s *Server) handleBackfillRelease(args any) map[string]any {
 
 	module, err := s.resolveModule(params.ModuleName)
 	if err != nil {
-		return ErrorResponse(fmt.Sprintf("Module '%s' not found", params.ModuleName))
+		return moduleNotFound(params.ModuleName, err)
 	}
 
 	file, err := s.getModuleChangelog(module)
@@ -202,361 +264,1513 @@ func (s *Server) handleBackfillRelease(args any) map[string]any {
 	return SuccessResponse(fmt.Sprintf("Backfilled release %s for %s with %d entries", tag, module.Name, len(entries)))
 }
 
-func (s *Server) lookupModuleRelease(moduleID int64, versionInput string) (*database.ModuleRelease, []database.ModuleReleaseEntry, error) {
-	version := strings.TrimSpace(versionInput)
-	if version == "" {
-		return nil, nil, fmt.Errorf("version is required")
+func (s *Server) handleGetFileDiff(ctx context.Context, args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
 	}
 
-	relVersion := strings.TrimPrefix(version, "v")
-	release, entries, err := s.db.GetModuleReleaseWithEntriesByVersion(moduleID, relVersion)
-	if err == nil {
-		return release, entries, nil
+	params, err := UnmarshalArgs[fileDiffArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" || strings.TrimSpace(params.FilePath) == "" {
+		return ErrorResponse("module_name and file_path are required")
 	}
-	tag := version
-	if !strings.HasPrefix(strings.ToLower(tag), "v") {
-		tag = "v" + tag
+	if strings.TrimSpace(params.FromVersion) == "" || strings.TrimSpace(params.ToVersion) == "" {
+		return ErrorResponse("from_version and to_version are required")
 	}
-	release, entries, err = s.db.GetModuleReleaseWithEntriesByTag(moduleID, tag)
+
+	module, err := s.resolveModule(params.ModuleName)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil, fmt.Errorf("no release metadata found for version %s", version)
-		}
-		return nil, nil, fmt.Errorf("failed to load release metadata: %w", err)
+		return moduleNotFound(params.ModuleName, err)
 	}
-	return release, entries, nil
-}
 
-func (s *Server) getModuleChangelog(module *database.Module) (*database.ModuleFile, error) {
-	candidates := []string{"CHANGELOG.md", "changelog.md", "docs/CHANGELOG.md", "docs/changelog.md"}
-	for _, candidate := range candidates {
-		file, err := s.db.GetFile(module.Name, candidate)
-		if err == nil {
-			return file, nil
+	if s.syncer == nil {
+		return ErrorResponse("Syncer is not initialized; run a sync first")
+	}
+
+	fromTag := versionTag(params.FromVersion)
+	toTag := versionTag(params.ToVersion)
+	compare, err := s.syncer.CompareTags(ctx, module.FullName, fromTag, toTag)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to fetch GitHub compare diff: %v", err))
+	}
+
+	filePath := strings.TrimPrefix(strings.TrimSpace(params.FilePath), "/")
+	file := findCompareFile(compare, filePath)
+
+	moduleName := module.FullName
+	if moduleName == "" {
+		moduleName = module.Name
+	}
+	var status, previousPath, patch string
+	if file != nil {
+		status, previousPath, patch = file.Status, file.PreviousFilename, file.Patch
+	} else {
+		// The compare only lists changed files, so look the file up at the
+		// target tag to tell an unchanged file from one that never existed.
+		exists, err := s.syncer.FileExistsAt(ctx, module.FullName, filePath, toTag)
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Failed to check %s at %s: %v", filePath, toTag, err))
 		}
-		if errors.Is(err, sql.ErrNoRows) {
-			continue
+		status = "unchanged"
+		if !exists {
+			status = "absent"
 		}
-		return nil, err
 	}
-	return nil, sql.ErrNoRows
+	text := formatter.FileDiff(moduleName, filePath, fromTag, toTag, status, previousPath, patch)
+	return SuccessResponse(text)
 }
 
-func (s *Server) releaseSummaryIfUpdated(updated []string) string {
-	if len(updated) != 1 {
-		return ""
+func (s *Server) handleFindIntroducedIn(ctx context.Context, args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
 	}
-	moduleName := updated[0]
-	module, err := s.db.GetModule(moduleName)
-	if err != nil {
-		if !errors.Is(err, sql.ErrNoRows) {
-			log.Printf("Warning: unable to load module metadata for release summary: %v", err)
-		}
-		return ""
+
+	params, err := UnmarshalArgs[introducedInArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" || strings.TrimSpace(params.Item) == "" {
+		return ErrorResponse("module_name and item are required")
+	}
+
+	maxReleases := params.MaxReleases
+	if maxReleases <= 0 {
+		maxReleases = 15
 	}
-	release, entries, err := s.db.GetLatestModuleReleaseWithEntries(module.ID)
+
+	module, err := s.resolveModule(params.ModuleName)
 	if err != nil {
-		if !errors.Is(err, sql.ErrNoRows) {
-			log.Printf("Warning: failed to load latest release summary: %v", err)
-		}
-		return ""
+		return moduleNotFound(params.ModuleName, err)
 	}
-	name := module.FullName
-	if name == "" {
-		name = module.Name
+
+	if s.syncer == nil {
+		return ErrorResponse("Syncer is not initialized; run a sync first")
 	}
-	return formatter.ReleaseSummary(name, release, entries)
-}
 
-func selectReleaseEntry(entries []database.ModuleReleaseEntry, query string, fallback string) *database.ModuleReleaseEntry {
-	normalized := strings.ToLower(strings.TrimSpace(query))
-	slugged := slugifyToken(normalized)
-	if normalized == "" {
-		return nil
+	releases, err := s.db.GetModuleReleases(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load releases: %v", err))
+	}
+	if len(releases) == 0 {
+		return ErrorResponse(fmt.Sprintf("No release metadata available for %s. Run a sync first.", module.Name))
 	}
 
-	for idx := range entries {
-		entry := &entries[idx]
-		if entry.Identifier.Valid {
-			if strings.EqualFold(entry.Identifier.String, normalized) || slugifyToken(entry.Identifier.String) == slugged {
-				return entry
-			}
+	// Releases are newest first; keep the newest ones within the cap and walk
+	// them oldest to newest.
+	truncated := len(releases) > maxReleases
+	if truncated {
+		releases = releases[:maxReleases]
+	}
+
+	item := strings.TrimSpace(params.Item)
+	rootPrefix := moduleRootPrefix(module.Name)
+	result := formatter.IntroducedInResult{Item: item, Truncated: truncated}
+	previousSkipped := false
+	for i := len(releases) - 1; i >= 0; i-- {
+		rel := releases[i]
+		snapshot, err := s.syncer.SnapshotAtTag(ctx, module.FullName, rel.Tag, rootPrefix)
+		if ctx.Err() != nil {
+			return ErrorResponse(fmt.Sprintf("Cancelled: %v", ctx.Err()))
+		}
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s (%v)", rel.Tag, err))
+			previousSkipped = true
+			continue
 		}
+		result.Scanned++
+		if kind := snapshotItemKind(snapshot, item); kind != "" {
+			result.Kind = kind
+			result.Version = rel.Version
+			result.Tag = rel.Tag
+			result.FirstScanned = i == len(releases)-1
+			result.PreviousSkipped = previousSkipped
+			break
+		}
+		previousSkipped = false
 	}
 
-	if fallback != "" {
-		fallbackLower := strings.ToLower(fallback)
-		for idx := range entries {
-			entry := &entries[idx]
-			if strings.Contains(strings.ToLower(entry.Title), fallbackLower) {
-				return entry
-			}
+	text := formatter.IntroducedIn(module.Name, result)
+	return SuccessResponse(text)
+}
+
+func snapshotItemKind(snapshot *indexer.TagSnapshot, item string) string {
+	for _, v := range snapshot.Variables {
+		if v.Name == item {
+			return "variable"
 		}
 	}
-
-	for idx := range entries {
-		entry := &entries[idx]
-		if strings.Contains(strings.ToLower(entry.Title), normalized) {
-			return entry
+	for _, r := range snapshot.Resources {
+		if r.ResourceType == item {
+			return "resource"
 		}
 	}
-
-	return nil
+	for _, d := range snapshot.DataSources {
+		if d.DataType == item {
+			return "data source"
+		}
+	}
+	return ""
 }
 
-type releaseEntryTargets struct {
-	filenameTokens       []string
-	contentTokens        []string
-	fallbackContentToken string
-}
+func (s *Server) handleGetVariableProvenance(ctx context.Context, args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
 
-func buildReleaseEntryTargets(entry *database.ModuleReleaseEntry, query string) releaseEntryTargets {
-	targets := releaseEntryTargets{}
+	params, err := UnmarshalArgs[variableProvenanceArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" || strings.TrimSpace(params.VariableName) == "" {
+		return ErrorResponse("module_name and variable_name are required")
+	}
 
-	if entry.Identifier.Valid {
-		id := strings.ToLower(entry.Identifier.String)
-		targets.filenameTokens = append(targets.filenameTokens, id)
-		targets.contentTokens = append(targets.contentTokens, id)
-		for _, part := range tokenizeIdentifier(id) {
-			targets.filenameTokens = append(targets.filenameTokens, part)
-			targets.contentTokens = append(targets.contentTokens, part)
-		}
+	maxReleases := params.MaxReleases
+	if maxReleases <= 0 {
+		maxReleases = 10
 	}
 
-	if query != "" {
-		for _, token := range tokenizeIdentifier(strings.ToLower(query)) {
-			targets.filenameTokens = append(targets.filenameTokens, token)
-			targets.contentTokens = append(targets.contentTokens, token)
-		}
-		targets.fallbackContentToken = strings.ToLower(query)
-	} else if entry.Title != "" {
-		targets.fallbackContentToken = strings.ToLower(entry.Title)
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
 	}
 
-	targets.filenameTokens = uniqueStrings(targets.filenameTokens)
-	targets.contentTokens = uniqueStrings(targets.contentTokens)
-	return targets
-}
+	if s.syncer == nil {
+		return ErrorResponse("Syncer is not initialized; run a sync first")
+	}
 
-func locatePatchForEntry(compare *indexer.GitHubCompareResult, entry *database.ModuleReleaseEntry, query string) (string, string) {
-	if compare == nil {
-		return "", ""
+	releases, err := s.db.GetModuleReleases(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load releases: %v", err))
+	}
+	if len(releases) == 0 {
+		return ErrorResponse(fmt.Sprintf("No release metadata available for %s. Run a sync first.", module.Name))
 	}
 
-	targets := buildReleaseEntryTargets(entry, query)
-	bestScore := -1 << 30
-	bestFile := ""
-	bestPatch := ""
+	truncated := len(releases) > maxReleases
+	if truncated {
+		releases = releases[:maxReleases]
+	}
+
+	name := strings.TrimSpace(params.VariableName)
+	rootPrefix := moduleRootPrefix(module.Name)
+	result := formatter.VariableProvenanceResult{Variable: name, Truncated: truncated}
+
+	// Walk releases newest to oldest; the first release whose declaration
+	// differs from the one before it is the most recent change.
+	var newer *database.ModuleVariable
+	var newerRelease *database.ModuleRelease
+	for i := range releases {
+		rel := &releases[i]
+		snapshot, err := s.syncer.SnapshotAtTag(ctx, module.FullName, rel.Tag, rootPrefix)
+		if ctx.Err() != nil {
+			return ErrorResponse(fmt.Sprintf("Cancelled: %v", ctx.Err()))
+		}
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s (%v)", rel.Tag, err))
+			continue
+		}
+		result.Scanned++
+		current := snapshotVariable(snapshot, name)
 
-	for _, file := range compare.Files {
-		if file.Patch == "" {
+		if newerRelease == nil {
+			if current == nil {
+				result.MissingInLatest = rel.Tag
+				break
+			}
+			newer, newerRelease = current, rel
+			result.OldestScanned = rel.Tag
 			continue
 		}
-		score := scorePatchCandidate(file.Filename, file.Patch, targets)
-		if score > bestScore {
-			bestScore = score
-			bestFile = file.Filename
-			bestPatch = file.Patch
+
+		changes := variableDeclarationChanges(current, newer)
+		if len(changes) > 0 {
+			result.Version = newerRelease.Version
+			result.Tag = newerRelease.Tag
+			result.Changes = changes
+			break
 		}
+		newer, newerRelease = current, rel
+		result.OldestScanned = rel.Tag
 	}
 
-	return bestFile, bestPatch
+	text := formatter.VariableProvenance(module.Name, result)
+	return SuccessResponse(text)
 }
 
-func scorePatchCandidate(filename string, patch string, targets releaseEntryTargets) int {
-	lowerPath := strings.ToLower(strings.ReplaceAll(filename, "\\", "/"))
-	lowerPatch := strings.ToLower(patch)
-	score := 0
+func snapshotVariable(snapshot *indexer.TagSnapshot, name string) *database.ModuleVariable {
+	for i := range snapshot.Variables {
+		if snapshot.Variables[i].Name == name {
+			return &snapshot.Variables[i]
+		}
+	}
+	return nil
+}
 
-	if strings.HasSuffix(lowerPath, ".tf") {
-		score += 150
+// variableDeclarationChanges describes how a variable declaration differs
+// between an older release and a newer one. A nil older declaration means the
+// variable was added.
+func variableDeclarationChanges(older, newer *database.ModuleVariable) []string {
+	if older == nil {
+		return []string{"added"}
 	}
-	if strings.Contains(lowerPath, "/modules/") {
-		score += 20
+
+	var changes []string
+	if collapseWhitespace(older.Type) != collapseWhitespace(newer.Type) {
+		changes = append(changes, fmt.Sprintf("type changed from `%s` to `%s`", displayOrNone(older.Type), displayOrNone(newer.Type)))
 	}
-	if strings.Contains(lowerPath, "/examples/") {
-		score -= 60
+	if collapseWhitespace(older.DefaultValue) != collapseWhitespace(newer.DefaultValue) || older.Required != newer.Required {
+		changes = append(changes, fmt.Sprintf("default changed from `%s` to `%s`", defaultOrRequired(older), defaultOrRequired(newer)))
 	}
-	if strings.HasSuffix(lowerPath, ".md") || strings.Contains(lowerPath, "changelog") {
-		score -= 180
+	if older.Sensitive != newer.Sensitive {
+		changes = append(changes, fmt.Sprintf("sensitive changed to %t", newer.Sensitive))
 	}
-	if strings.Contains(lowerPath, "/test") {
-		score -= 40
+	if older.Nullable != newer.Nullable {
+		changes = append(changes, fmt.Sprintf("nullable changed to %t", newer.Nullable))
 	}
-
-	for _, token := range targets.filenameTokens {
-		if token != "" && strings.Contains(lowerPath, token) {
-			score += 35
-		}
+	if strings.TrimSpace(older.Description) != strings.TrimSpace(newer.Description) {
+		changes = append(changes, "description changed")
 	}
+	return changes
+}
 
-	for _, token := range targets.contentTokens {
-		if token != "" && strings.Contains(lowerPatch, token) {
-			score += 20
-		}
-	}
+func collapseWhitespace(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
 
-	if targets.fallbackContentToken != "" && strings.Contains(lowerPatch, targets.fallbackContentToken) {
-		score += 10
+func displayOrNone(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "none"
 	}
+	return collapseWhitespace(value)
+}
 
-	score += len(patch) / 400
-	return score
+func defaultOrRequired(v *database.ModuleVariable) string {
+	if v.Required {
+		return "required"
+	}
+	return displayOrNone(v.DefaultValue)
 }
 
-func trimPatchLines(patch string, maxLines int) (string, bool) {
-	if maxLines <= 0 {
-		return patch, false
+func (s *Server) handleVerifyChangelog(ctx context.Context, args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
 	}
-	lines := strings.Split(patch, "\n")
-	truncated := false
-	if len(lines) > maxLines {
-		lines = lines[:maxLines]
-		truncated = true
+
+	params, err := UnmarshalArgs[verifyChangelogArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" || strings.TrimSpace(params.Version) == "" {
+		return ErrorResponse("module_name and version are required")
 	}
-	return strings.Join(lines, "\n"), truncated
-}
 
-func formatReleaseSnippetResponse(moduleName string, release *database.ModuleRelease, entry *database.ModuleReleaseEntry, filename, patch string, truncated bool, maxLines int) string {
-	var b strings.Builder
-	b.WriteString(fmt.Sprintf("Release %s – %s\n", release.Version, entry.Title))
-	b.WriteString(fmt.Sprintf("Module: %s\n", moduleName))
-	b.WriteString(fmt.Sprintf("File: %s\n", filename))
-	b.WriteString("```diff\n")
-	b.WriteString(patch)
-	b.WriteString("\n```")
-	if truncated {
-		b.WriteString(fmt.Sprintf("\n… showing first %d diff lines", maxLines))
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
 	}
-	if release.ComparisonURL.Valid && release.ComparisonURL.String != "" {
-		b.WriteString(fmt.Sprintf("\nCompare: %s", release.ComparisonURL.String))
+
+	release, entries, err := s.lookupModuleRelease(module.ID, params.Version)
+	if err != nil {
+		return ErrorResponse(err.Error())
 	}
-	return b.String()
-}
 
-func extractReleaseBlock(changelog string, version string) (string, string, bool) {
-	esc := regexp.QuoteMeta(version)
-	heading := regexp.MustCompile(`(?m)^##\s*(?:\[` + esc + `\]|v?` + esc + `)\s*(?:\(([^)]+)\))?\s*$`)
-	loc := heading.FindStringSubmatchIndex(changelog)
-	if loc == nil {
-		return "", "", false
+	if !release.PreviousTag.Valid || release.PreviousTag.String == "" {
+		return ErrorResponse("Unable to compute diff for the earliest release (missing previous tag)")
 	}
-	start := loc[0]
-	date := ""
-	if len(loc) >= 4 && loc[2] != -1 && loc[3] != -1 {
-		date = strings.TrimSpace(changelog[loc[2]:loc[3]])
+
+	if s.syncer == nil {
+		return ErrorResponse("Syncer is not initialized; run a sync first")
 	}
-	next := regexp.MustCompile(`(?m)^##\s+`).FindStringIndex(changelog[start+2:])
-	var end int
-	if next == nil {
-		end = len(changelog)
-	} else {
-		end = start + 2 + next[0]
+
+	compare, err := s.syncer.CompareTags(ctx, module.FullName, release.PreviousTag.String, release.Tag)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to fetch GitHub compare diff: %v", err))
 	}
-	block := strings.TrimSpace(changelog[start:end])
-	return block, date, true
+
+	report := verifyChangelogEntries(entries, compare)
+	report.Range = fmt.Sprintf("%s...%s", release.PreviousTag.String, release.Tag)
+
+	moduleName := module.FullName
+	if moduleName == "" {
+		moduleName = module.Name
+	}
+	text := formatter.ChangelogVerification(moduleName, release.Version, report)
+	return SuccessResponse(text)
 }
 
-func parseReleaseEntriesFromBlock(block string) []database.ModuleReleaseEntry {
-	lines := strings.Split(block, "\n")
-	section := ""
-	order := 0
-	var entries []database.ModuleReleaseEntry
-	for _, line := range lines {
-		t := strings.TrimSpace(line)
-		if strings.HasPrefix(t, "## ") {
+// verifyChangelogEntries matches changelog entries to changed files by the
+// words of their identifier and title, in both directions: entries without a
+// touched file and changed files without an entry.
+func verifyChangelogEntries(entries []database.ModuleReleaseEntry, compare *indexer.GitHubCompareResult) formatter.ChangelogVerificationReport {
+	report := formatter.ChangelogVerificationReport{EntryCount: len(entries)}
+	if compare == nil {
+		return report
+	}
+
+	mentioned := make(map[string]bool)
+	for idx := range entries {
+		entry := &entries[idx]
+		tokens := changelogEntryTokens(entry)
+		matched := false
+		for _, file := range compare.Files {
+			if isChangelogNoise(file.Filename) {
+				continue
+			}
+			if fileMatchesTokens(file, tokens) {
+				matched = true
+				mentioned[file.Filename] = true
+			}
+		}
+		if !matched {
+			report.UnmatchedEntries = append(report.UnmatchedEntries, entry.Title)
+		}
+	}
+
+	for _, file := range compare.Files {
+		report.FileCount++
+		if isChangelogNoise(file.Filename) || mentioned[file.Filename] {
 			continue
 		}
-		if s, ok := strings.CutPrefix(t, "### "); ok {
-			section = strings.TrimSpace(s)
+		changed := countPatchChanges(file.Patch)
+		if !strings.HasSuffix(file.Filename, ".tf") || changed < minUnmentionedChangeLines {
 			continue
 		}
-		if strings.HasPrefix(t, "-") || strings.HasPrefix(t, "*") {
-			title := strings.TrimSpace(strings.TrimLeft(t, "-* "))
-			if title == "" {
-				continue
-			}
-			entries = append(entries, database.ModuleReleaseEntry{
-				Section:    ifEmpty(section, "Other"),
-				EntryKey:   fmt.Sprintf("%s-%04d", safeSlug(section), order),
-				Title:      title,
-				OrderIndex: order,
-				Identifier: sql.NullString{String: slugifyToken(title), Valid: title != ""},
-			})
-			order++
+		report.UnmentionedFiles = append(report.UnmentionedFiles, formatter.UnmentionedChange{
+			FilePath: file.Filename,
+			Status:   file.Status,
+			Lines:    changed,
+		})
+	}
+
+	return report
+}
+
+func changelogEntryTokens(entry *database.ModuleReleaseEntry) []string {
+	targets := buildReleaseEntryTargets(entry, "")
+	candidates := append(targets.contentTokens, tokenizeIdentifier(strings.ToLower(entry.Title))...)
+
+	var tokens []string
+	for _, token := range uniqueStrings(candidates) {
+		token = strings.Trim(token, "`'\"()[],")
+		if len(token) < 4 {
+			continue
+		}
+		if _, stop := changelogStopwords[token]; stop {
+			continue
 		}
+		tokens = append(tokens, token)
 	}
-	return entries
+	return tokens
 }
 
-func ifEmpty(val, fallback string) string {
-	if strings.TrimSpace(val) == "" {
-		return fallback
+// fileMatchesTokens reports whether any token occurs as a whole word in the
+// file path or patch, so "name" matches modules/name/main.tf or var.name but
+// not namespace.tf.
+func fileMatchesTokens(file indexer.GitHubCompareFile, tokens []string) bool {
+	lowerPath := strings.ToLower(file.Filename)
+	lowerPatch := strings.ToLower(file.Patch)
+	for _, token := range tokens {
+		if containsWord(lowerPath, token) || containsWord(lowerPatch, token) {
+			return true
+		}
 	}
-	return val
+	return false
 }
 
-func safeSlug(s string) string {
-	s = strings.ToLower(strings.TrimSpace(s))
-	if s == "" {
-		return "section"
+// containsWord reports whether word occurs in text with no letter or digit
+// directly before or after it.
+func containsWord(text, word string) bool {
+	if word == "" {
+		return false
 	}
-	b := strings.Builder{}
-	for _, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
-			b.WriteRune(r)
-		} else {
-			b.WriteByte('-')
+	for offset := 0; ; {
+		idx := strings.Index(text[offset:], word)
+		if idx == -1 {
+			return false
+		}
+		start := offset + idx
+		end := start + len(word)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
 		}
+		offset = start + 1
 	}
-	return strings.Trim(b.String(), "-")
 }
 
-func uniqueStrings(values []string) []string {
-	seen := make(map[string]struct{})
-	var out []string
-	for _, v := range values {
-		v = strings.TrimSpace(v)
-		if v == "" {
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func isChangelogNoise(filename string) bool {
+	lower := strings.ToLower(filename)
+	return strings.HasSuffix(lower, ".md") || strings.HasPrefix(lower, ".github/") || strings.Contains(lower, "changelog")
+}
+
+func countPatchChanges(patch string) int {
+	count := 0
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
 			continue
 		}
-		if _, ok := seen[v]; ok {
-			continue
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			count++
 		}
-		seen[v] = struct{}{}
-		out = append(out, v)
 	}
-	return out
+	return count
 }
 
-func tokenizeIdentifier(value string) []string {
-	value = strings.ReplaceAll(value, "_", "-")
-	value = strings.ReplaceAll(value, ".", "-")
-	parts := strings.FieldsFunc(value, func(r rune) bool {
-		return r == '-' || r == ' ' || r == ':'
-	})
-	result := make([]string, 0, len(parts))
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part != "" {
-			result = append(result, part)
+func versionTag(version string) string {
+	tag := strings.TrimSpace(version)
+	if !strings.HasPrefix(strings.ToLower(tag), "v") {
+		tag = "v" + tag
+	}
+	return tag
+}
+
+func findCompareFile(compare *indexer.GitHubCompareResult, filePath string) *indexer.GitHubCompareFile {
+	if compare == nil {
+		return nil
+	}
+	for i := range compare.Files {
+		file := &compare.Files[i]
+		if file.Filename == filePath || file.PreviousFilename == filePath {
+			return file
 		}
 	}
-	return result
+	return nil
 }
 
-func slugifyToken(value string) string {
-	value = strings.ToLower(strings.TrimSpace(value))
-	if value == "" {
+// parseLatestStrategy validates a latest_strategy argument, defaulting to
+// semver.
+func parseLatestStrategy(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", latestBySemver:
+		return latestBySemver, nil
+	case latestByDate:
+		return latestByDate, nil
+	}
+	return "", fmt.Errorf("invalid latest_strategy %q (expected %q or %q)", value, latestBySemver, latestByDate)
+}
+
+func latestStrategyNote(strategy string) string {
+	if strategy == latestByDate {
+		return "Latest release resolved by most recent release date (latest_strategy=date)."
+	}
+	return "Latest release resolved by highest semantic version (latest_strategy=semver)."
+}
+
+// resolveLatestRelease is the single place release tools turn "latest" into a
+// concrete release, so backfilled or out-of-order releases cannot change the
+// answer. It returns sql.ErrNoRows when the module has no releases.
+func (s *Server) resolveLatestRelease(moduleID int64, strategy string) (*database.ModuleRelease, error) {
+	releases, err := s.db.GetModuleReleases(moduleID)
+	if err != nil {
+		return nil, err
+	}
+	latest := latestRelease(releases, strategy)
+	if latest == nil {
+		return nil, sql.ErrNoRows
+	}
+	return latest, nil
+}
+
+func latestRelease(releases []database.ModuleRelease, strategy string) *database.ModuleRelease {
+	var latest *database.ModuleRelease
+	for i := range releases {
+		rel := &releases[i]
+		if latest == nil || newerRelease(rel, latest, strategy) {
+			latest = rel
+		}
+	}
+	return latest
+}
+
+// newerRelease reports whether a is later than b. Each strategy falls back to
+// the other to break ties.
+func newerRelease(a, b *database.ModuleRelease, strategy string) bool {
+	byVersion := util.CompareVersions(a.Version, b.Version)
+	byDate := strings.Compare(a.ReleaseDate.String, b.ReleaseDate.String)
+	if strategy == latestByDate {
+		if byDate != 0 {
+			return byDate > 0
+		}
+		return byVersion > 0
+	}
+	if byVersion != 0 {
+		return byVersion > 0
+	}
+	return byDate > 0
+}
+
+func (s *Server) lookupModuleRelease(moduleID int64, versionInput string) (*database.ModuleRelease, []database.ModuleReleaseEntry, error) {
+	version := strings.TrimSpace(versionInput)
+	if version == "" {
+		return nil, nil, fmt.Errorf("version is required")
+	}
+
+	relVersion := strings.TrimPrefix(version, "v")
+	release, entries, err := s.db.GetModuleReleaseWithEntriesByVersion(moduleID, relVersion)
+	if err == nil {
+		return release, entries, nil
+	}
+	tag := version
+	if !strings.HasPrefix(strings.ToLower(tag), "v") {
+		tag = "v" + tag
+	}
+	release, entries, err = s.db.GetModuleReleaseWithEntriesByTag(moduleID, tag)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, fmt.Errorf("no release metadata found for version %s", version)
+		}
+		return nil, nil, fmt.Errorf("failed to load release metadata: %w", err)
+	}
+	return release, entries, nil
+}
+
+func (s *Server) getModuleChangelog(module *database.Module) (*database.ModuleFile, error) {
+	candidates := []string{"CHANGELOG.md", "changelog.md", "docs/CHANGELOG.md", "docs/changelog.md"}
+	for _, candidate := range candidates {
+		file, err := s.db.GetFile(module.Name, candidate)
+		if err == nil {
+			return file, nil
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		return nil, err
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *Server) releaseSummaryIfUpdated(updated []string) string {
+	if len(updated) != 1 {
 		return ""
 	}
-	b := strings.Builder{}
-	for _, r := range value {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
-			b.WriteRune(r)
-		} else {
-			b.WriteByte('-')
+	moduleName := updated[0]
+	module, err := s.db.GetModule(moduleName)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Warning: unable to load module metadata for release summary: %v", err)
 		}
+		return ""
 	}
-	return strings.Trim(b.String(), "-")
+	release, err := s.resolveLatestRelease(module.ID, latestBySemver)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Warning: failed to load latest release summary: %v", err)
+		}
+		return ""
+	}
+	entries, err := s.db.GetModuleReleaseEntries(release.ID)
+	if err != nil {
+		log.Printf("Warning: failed to load latest release summary: %v", err)
+		return ""
+	}
+	name := module.FullName
+	if name == "" {
+		name = module.Name
+	}
+	return formatter.ReleaseSummary(name, release, entries)
+}
+
+// ensureReleaseContributors fills in a release's contributors from the
+// commits between its previous tag and its tag, storing them so the compare
+// call is made once per release.
+func (s *Server) ensureReleaseContributors(ctx context.Context, repoFullName string, release *database.ModuleRelease) {
+	if release.Contributors.Valid || s.syncer == nil || repoFullName == "" {
+		return
+	}
+	if !release.PreviousTag.Valid || release.PreviousTag.String == "" {
+		return
+	}
+
+	compare, err := s.syncer.CompareTags(ctx, repoFullName, release.PreviousTag.String, release.Tag)
+	if err != nil {
+		log.Printf("Warning: failed to fetch contributors for %s %s: %v", repoFullName, release.Tag, err)
+		return
+	}
+
+	contributors := compare.Contributors()
+	if err := s.db.SetReleaseContributors(release.ID, contributors); err != nil {
+		log.Printf("Warning: failed to store contributors for %s %s: %v", repoFullName, release.Tag, err)
+	}
+	release.Contributors = sql.NullString{String: strings.Join(contributors, ","), Valid: true}
+}
+
+func selectReleaseEntry(entries []database.ModuleReleaseEntry, query string, fallback string) *database.ModuleReleaseEntry {
+	normalized := strings.ToLower(strings.TrimSpace(query))
+	slugged := slugifyToken(normalized)
+	if normalized == "" {
+		return nil
+	}
+
+	for idx := range entries {
+		entry := &entries[idx]
+		if entry.Identifier.Valid {
+			if strings.EqualFold(entry.Identifier.String, normalized) || slugifyToken(entry.Identifier.String) == slugged {
+				return entry
+			}
+		}
+	}
+
+	if fallback != "" {
+		fallbackLower := strings.ToLower(fallback)
+		for idx := range entries {
+			entry := &entries[idx]
+			if strings.Contains(strings.ToLower(entry.Title), fallbackLower) {
+				return entry
+			}
+		}
+	}
+
+	for idx := range entries {
+		entry := &entries[idx]
+		if strings.Contains(strings.ToLower(entry.Title), normalized) {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+type releaseEntryTargets struct {
+	filenameTokens       []string
+	contentTokens        []string
+	fallbackContentToken string
+}
+
+func buildReleaseEntryTargets(entry *database.ModuleReleaseEntry, query string) releaseEntryTargets {
+	targets := releaseEntryTargets{}
+
+	if entry.Identifier.Valid {
+		id := strings.ToLower(entry.Identifier.String)
+		targets.filenameTokens = append(targets.filenameTokens, id)
+		targets.contentTokens = append(targets.contentTokens, id)
+		for _, part := range tokenizeIdentifier(id) {
+			targets.filenameTokens = append(targets.filenameTokens, part)
+			targets.contentTokens = append(targets.contentTokens, part)
+		}
+	}
+
+	if query != "" {
+		for _, token := range tokenizeIdentifier(strings.ToLower(query)) {
+			targets.filenameTokens = append(targets.filenameTokens, token)
+			targets.contentTokens = append(targets.contentTokens, token)
+		}
+		targets.fallbackContentToken = strings.ToLower(query)
+	} else if entry.Title != "" {
+		targets.fallbackContentToken = strings.ToLower(entry.Title)
+	}
+
+	targets.filenameTokens = uniqueStrings(targets.filenameTokens)
+	targets.contentTokens = uniqueStrings(targets.contentTokens)
+	return targets
+}
+
+func locatePatchForEntry(compare *indexer.GitHubCompareResult, entry *database.ModuleReleaseEntry, query string) (string, string) {
+	if compare == nil {
+		return "", ""
+	}
+
+	targets := buildReleaseEntryTargets(entry, query)
+	bestScore := -1 << 30
+	bestFile := ""
+	bestPatch := ""
+
+	for _, file := range compare.Files {
+		if file.Patch == "" {
+			continue
+		}
+		score := scorePatchCandidate(file.Filename, file.Patch, targets)
+		if score > bestScore {
+			bestScore = score
+			bestFile = file.Filename
+			bestPatch = file.Patch
+		}
+	}
+
+	return bestFile, bestPatch
+}
+
+func scorePatchCandidate(filename string, patch string, targets releaseEntryTargets) int {
+	lowerPath := strings.ToLower(strings.ReplaceAll(filename, "\\", "/"))
+	lowerPatch := strings.ToLower(patch)
+	score := 0
+
+	if strings.HasSuffix(lowerPath, ".tf") {
+		score += 150
+	}
+	if strings.Contains(lowerPath, "/modules/") {
+		score += 20
+	}
+	if strings.Contains(lowerPath, "/examples/") {
+		score -= 60
+	}
+	if strings.HasSuffix(lowerPath, ".md") || strings.Contains(lowerPath, "changelog") {
+		score -= 180
+	}
+	if strings.Contains(lowerPath, "/test") {
+		score -= 40
+	}
+
+	for _, token := range targets.filenameTokens {
+		if token != "" && strings.Contains(lowerPath, token) {
+			score += 35
+		}
+	}
+
+	for _, token := range targets.contentTokens {
+		if token != "" && strings.Contains(lowerPatch, token) {
+			score += 20
+		}
+	}
+
+	if targets.fallbackContentToken != "" && strings.Contains(lowerPatch, targets.fallbackContentToken) {
+		score += 10
+	}
+
+	score += len(patch) / 400
+	return score
+}
+
+func trimPatchLines(patch string, maxLines int) (string, bool) {
+	if maxLines <= 0 {
+		return patch, false
+	}
+	lines := strings.Split(patch, "\n")
+	truncated := false
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		truncated = true
+	}
+	return strings.Join(lines, "\n"), truncated
+}
+
+func formatReleaseSnippetResponse(moduleName string, release *database.ModuleRelease, entry *database.ModuleReleaseEntry, filename, patch string, truncated bool, maxLines int) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Release %s – %s\n", release.Version, entry.Title))
+	b.WriteString(fmt.Sprintf("Module: %s\n", moduleName))
+	b.WriteString(fmt.Sprintf("File: %s\n", filename))
+	b.WriteString("```diff\n")
+	b.WriteString(patch)
+	b.WriteString("\n```")
+	if truncated {
+		b.WriteString(fmt.Sprintf("\n… showing first %d diff lines", maxLines))
+	}
+	if release.ComparisonURL.Valid && release.ComparisonURL.String != "" {
+		b.WriteString(fmt.Sprintf("\nCompare: %s", release.ComparisonURL.String))
+	}
+	return b.String()
+}
+
+func extractReleaseBlock(changelog string, version string) (string, string, bool) {
+	// Changelogs indexed before line endings were normalized may still
+	// carry CRLF.
+	changelog = strings.ReplaceAll(changelog, "\r\n", "\n")
+	esc := regexp.QuoteMeta(version)
+	heading := regexp.MustCompile(`(?m)^##\s*(?:\[` + esc + `\]|v?` + esc + `)[ \t]*(?:\(([^)]+)\))?[ \t]*\r?$`)
+	loc := heading.FindStringSubmatchIndex(changelog)
+	if loc == nil {
+		return "", "", false
+	}
+	start := loc[0]
+	date := ""
+	if len(loc) >= 4 && loc[2] != -1 && loc[3] != -1 {
+		date = strings.TrimSpace(changelog[loc[2]:loc[3]])
+	}
+	next := regexp.MustCompile(`(?m)^##\s+`).FindStringIndex(changelog[start+2:])
+	var end int
+	if next == nil {
+		end = len(changelog)
+	} else {
+		end = start + 2 + next[0]
+	}
+	block := strings.TrimSpace(changelog[start:end])
+	return block, date, true
+}
+
+func parseReleaseEntriesFromBlock(block string) []database.ModuleReleaseEntry {
+	lines := strings.Split(block, "\n")
+	section := ""
+	order := 0
+	var entries []database.ModuleReleaseEntry
+	for _, line := range lines {
+		t := strings.TrimSpace(line)
+		if strings.HasPrefix(t, "## ") {
+			continue
+		}
+		if s, ok := strings.CutPrefix(t, "### "); ok {
+			section = strings.TrimSpace(s)
+			continue
+		}
+		if strings.HasPrefix(t, "-") || strings.HasPrefix(t, "*") {
+			title := strings.TrimSpace(strings.TrimLeft(t, "-* "))
+			if title == "" {
+				continue
+			}
+			entries = append(entries, database.ModuleReleaseEntry{
+				Section:    ifEmpty(section, "Other"),
+				EntryKey:   fmt.Sprintf("%s-%04d", safeSlug(section), order),
+				Title:      title,
+				OrderIndex: order,
+				Identifier: sql.NullString{String: slugifyToken(title), Valid: title != ""},
+			})
+			order++
+		}
+	}
+	return entries
+}
+
+func ifEmpty(val, fallback string) string {
+	if strings.TrimSpace(val) == "" {
+		return fallback
+	}
+	return val
+}
+
+func safeSlug(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return "section"
+	}
+	b := strings.Builder{}
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{})
+	var out []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+func tokenizeIdentifier(value string) []string {
+	value = strings.ReplaceAll(value, "_", "-")
+	value = strings.ReplaceAll(value, ".", "-")
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == '-' || r == ' ' || r == ':'
+	})
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+func slugifyToken(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return ""
+	}
+	b := strings.Builder{}
+	for _, r := range value {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+func (s *Server) handleCompareModuleVersions(ctx context.Context, args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[fileDiffArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+	if strings.TrimSpace(params.FromVersion) == "" || strings.TrimSpace(params.ToVersion) == "" {
+		return ErrorResponse("from_version and to_version are required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	if s.syncer == nil {
+		return ErrorResponse("Syncer is not initialized; run a sync first")
+	}
+
+	fromTag := versionTag(params.FromVersion)
+	toTag := versionTag(params.ToVersion)
+	compare, err := s.syncer.CompareTags(ctx, module.FullName, fromTag, toTag)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to fetch GitHub compare diff: %v", err))
+	}
+
+	rootPrefix := moduleRootPrefix(module.Name)
+	comparison := formatter.VersionComparison{FromTag: fromTag, ToTag: toTag}
+	for _, file := range compare.Files {
+		if isOwnTerraformFile(file.Filename, rootPrefix) || isOwnTerraformFile(file.PreviousFilename, rootPrefix) {
+			comparison.ChangedFiles = append(comparison.ChangedFiles, file.Filename)
+		}
+	}
+
+	// Declarations can only change when a .tf file changed, so skip the
+	// archive downloads otherwise.
+	if len(comparison.ChangedFiles) > 0 {
+		older, err := s.syncer.SnapshotAtTag(ctx, module.FullName, fromTag, rootPrefix)
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Failed to load %s: %v", fromTag, err))
+		}
+		newer, err := s.syncer.SnapshotAtTag(ctx, module.FullName, toTag, rootPrefix)
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Failed to load %s: %v", toTag, err))
+		}
+		diffSnapshots(&comparison, older, newer)
+	}
+
+	moduleName := module.FullName
+	if moduleName == "" {
+		moduleName = module.Name
+	}
+	return SuccessResponse(formatter.CompareVersions(moduleName, comparison))
+}
+
+func isOwnTerraformFile(filePath, rootPrefix string) bool {
+	if !strings.HasSuffix(filePath, ".tf") || !strings.HasPrefix(filePath, rootPrefix) {
+		return false
+	}
+	return !strings.Contains(strings.TrimPrefix(filePath, rootPrefix), "/")
+}
+
+// diffSnapshots fills in the declarations added, removed or modified between
+// two tags of the same module.
+func diffSnapshots(c *formatter.VersionComparison, older, newer *indexer.TagSnapshot) {
+	for i := range newer.Variables {
+		v := &newer.Variables[i]
+		previous := snapshotVariable(older, v.Name)
+		if previous == nil {
+			c.Variables.Added = append(c.Variables.Added, v.Name)
+			continue
+		}
+		if changes := variableDeclarationChanges(previous, v); len(changes) > 0 {
+			c.Variables.Changed = append(c.Variables.Changed, fmt.Sprintf("%s: %s", v.Name, strings.Join(changes, "; ")))
+		}
+	}
+	for i := range older.Variables {
+		if snapshotVariable(newer, older.Variables[i].Name) == nil {
+			c.Variables.Removed = append(c.Variables.Removed, older.Variables[i].Name)
+		}
+	}
+
+	olderOutputs := make(map[string]database.ModuleOutput, len(older.Outputs))
+	for _, o := range older.Outputs {
+		olderOutputs[o.Name] = o
+	}
+	newerOutputs := make(map[string]struct{}, len(newer.Outputs))
+	for _, o := range newer.Outputs {
+		newerOutputs[o.Name] = struct{}{}
+		previous, ok := olderOutputs[o.Name]
+		switch {
+		case !ok:
+			c.Outputs.Added = append(c.Outputs.Added, o.Name)
+		case collapseWhitespace(previous.Value) != collapseWhitespace(o.Value):
+			c.Outputs.Changed = append(c.Outputs.Changed, fmt.Sprintf("%s: value changed", o.Name))
+		case previous.Sensitive != o.Sensitive:
+			c.Outputs.Changed = append(c.Outputs.Changed, fmt.Sprintf("%s: sensitive changed from %t to %t", o.Name, previous.Sensitive, o.Sensitive))
+		}
+	}
+	for _, o := range older.Outputs {
+		if _, ok := newerOutputs[o.Name]; !ok {
+			c.Outputs.Removed = append(c.Outputs.Removed, o.Name)
+		}
+	}
+
+	c.Resources.Added, c.Resources.Removed = diffResourceAddresses(older.Resources, newer.Resources)
+	c.DataSources.Added, c.DataSources.Removed = diffDataSourceAddresses(older.DataSources, newer.DataSources)
+}
+
+func diffResourceAddresses(older, newer []database.ModuleResource) ([]string, []string) {
+	before := make([]string, 0, len(older))
+	for _, r := range older {
+		before = append(before, r.ResourceType+"."+r.ResourceName)
+	}
+	after := make([]string, 0, len(newer))
+	for _, r := range newer {
+		after = append(after, r.ResourceType+"."+r.ResourceName)
+	}
+	return diffStringSets(before, after)
+}
+
+func diffDataSourceAddresses(older, newer []database.ModuleDataSource) ([]string, []string) {
+	before := make([]string, 0, len(older))
+	for _, d := range older {
+		before = append(before, "data."+d.DataType+"."+d.DataName)
+	}
+	after := make([]string, 0, len(newer))
+	for _, d := range newer {
+		after = append(after, "data."+d.DataType+"."+d.DataName)
+	}
+	return diffStringSets(before, after)
+}
+
+// diffStringSets returns the values only in after (added) and only in before
+// (removed), each in their original order.
+func diffStringSets(before, after []string) ([]string, []string) {
+	beforeSet := make(map[string]struct{}, len(before))
+	for _, v := range before {
+		beforeSet[v] = struct{}{}
+	}
+	afterSet := make(map[string]struct{}, len(after))
+	for _, v := range after {
+		afterSet[v] = struct{}{}
+	}
+
+	var added, removed []string
+	for _, v := range after {
+		if _, ok := beforeSet[v]; !ok {
+			added = append(added, v)
+		}
+	}
+	for _, v := range before {
+		if _, ok := afterSet[v]; !ok {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
+
+func (s *Server) handleCompareResources(ctx context.Context, args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[fileDiffArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+	if strings.TrimSpace(params.FromVersion) == "" || strings.TrimSpace(params.ToVersion) == "" {
+		return ErrorResponse("from_version and to_version are required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	if s.syncer == nil {
+		return ErrorResponse("Syncer is not initialized; run a sync first")
+	}
+
+	fromTag := versionTag(params.FromVersion)
+	toTag := versionTag(params.ToVersion)
+	rootPrefix := moduleRootPrefix(module.Name)
+	older, err := s.syncer.SnapshotAtTag(ctx, module.FullName, fromTag, rootPrefix)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load %s: %v", fromTag, err))
+	}
+	newer, err := s.syncer.SnapshotAtTag(ctx, module.FullName, toTag, rootPrefix)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load %s: %v", toTag, err))
+	}
+
+	comparison := compareResourceSets(older.Resources, newer.Resources)
+	comparison.FromTag, comparison.ToTag = fromTag, toTag
+
+	moduleName := module.FullName
+	if moduleName == "" {
+		moduleName = module.Name
+	}
+	return SuccessResponse(formatter.ResourceChanges(moduleName, comparison))
+}
+
+// compareResourceSets diffs resource addresses across every file of two
+// tags. A resource that disappears and reappears under the same local name,
+// provider and source file with a different type is reported as a type change
+// rather than a removal plus an addition; anything else is a plain add or
+// remove.
+func compareResourceSets(older, newer []database.ModuleResource) formatter.ResourceComparison {
+	address := func(r database.ModuleResource) string { return r.ResourceType + "." + r.ResourceName }
+	ref := func(r database.ModuleResource) formatter.ResourceRef {
+		return formatter.ResourceRef{Address: address(r), File: r.SourceFile}
+	}
+
+	before := make(map[string]struct{}, len(older))
+	for _, r := range older {
+		before[address(r)] = struct{}{}
+	}
+	after := make(map[string]struct{}, len(newer))
+	for _, r := range newer {
+		after[address(r)] = struct{}{}
+	}
+
+	var removed, added []database.ModuleResource
+	for _, r := range older {
+		if _, ok := after[address(r)]; !ok {
+			removed = append(removed, r)
+		}
+	}
+	for _, r := range newer {
+		if _, ok := before[address(r)]; !ok {
+			added = append(added, r)
+		}
+	}
+
+	var c formatter.ResourceComparison
+	matched := make(map[int]bool)
+	for _, r := range removed {
+		replacement := -1
+		for i, a := range added {
+			if !matched[i] && a.ResourceName == r.ResourceName && a.Provider == r.Provider && a.SourceFile == r.SourceFile {
+				replacement = i
+				break
+			}
+		}
+		if replacement < 0 {
+			c.Removed = append(c.Removed, ref(r))
+			continue
+		}
+		matched[replacement] = true
+		c.TypeChanged = append(c.TypeChanged, formatter.ResourceTypeChange{From: ref(r), To: ref(added[replacement])})
+	}
+	for i, a := range added {
+		if !matched[i] {
+			c.Added = append(c.Added, ref(a))
+		}
+	}
+	c.Unchanged = len(newer) - len(added)
+	return c
+}
+
+type listModuleTagsArgs struct {
+	ModuleName string `json:"module_name"`
+	Limit      int    `json:"limit"`
+}
+
+// maxModuleTags caps list_module_tags so repositories with thousands of tags
+// cannot trigger dozens of paginated API calls.
+const maxModuleTags = 1000
+
+func (s *Server) handleListModuleTags(ctx context.Context, args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[listModuleTagsArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 300
+	}
+	limit = min(limit, maxModuleTags)
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	if s.syncer == nil {
+		return ErrorResponse("Syncer is not initialized; run a sync first")
+	}
+
+	tags, truncated, err := s.syncer.ListTags(ctx, module.FullName, limit)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to fetch tags: %v", err))
+	}
+
+	releases, err := s.db.GetModuleReleases(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load releases: %v", err))
+	}
+	documented := make(map[string]struct{}, len(releases))
+	for _, r := range releases {
+		documented[strings.ToLower(r.Tag)] = struct{}{}
+	}
+
+	rows := make([]formatter.ModuleTag, 0, len(tags))
+	for _, tag := range tags {
+		_, ok := documented[strings.ToLower(tag.Name)]
+		rows = append(rows, formatter.ModuleTag{Name: tag.Name, CommitSHA: tag.Commit.SHA, InChangelog: ok})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if c := util.CompareVersions(rows[i].Name, rows[j].Name); c != 0 {
+			return c > 0
+		}
+		return rows[i].Name > rows[j].Name
+	})
+
+	name := module.FullName
+	if name == "" {
+		name = module.Name
+	}
+	return SuccessResponse(formatter.ModuleTags(name, rows, truncated))
+}
+
+func (s *Server) handleListModuleReleases(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	releases, err := s.db.GetModuleReleases(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load releases: %v", err))
+	}
+	counts, err := s.db.CountReleaseEntries(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load release entries: %v", err))
+	}
+
+	rows := make([]formatter.ReleaseListing, 0, len(releases))
+	for _, r := range releases {
+		rows = append(rows, formatter.ReleaseListing{
+			Version:    r.Version,
+			Tag:        r.Tag,
+			Date:       r.ReleaseDate.String,
+			EntryCount: counts[r.ID],
+		})
+	}
+
+	return SuccessResponse(formatter.ModuleReleases(module.Name, rows))
+}
+
+type changelogRangeArgs struct {
+	ModuleName  string `json:"module_name"`
+	FromVersion string `json:"from_version"`
+	ToVersion   string `json:"to_version"`
+}
+
+func (s *Server) handleGetChangelogRange(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[changelogRangeArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+	from, to := strings.TrimSpace(params.FromVersion), strings.TrimSpace(params.ToVersion)
+	if from == "" || to == "" {
+		return ErrorResponse("from_version and to_version are required")
+	}
+	if util.CompareVersions(from, to) > 0 {
+		from, to = to, from
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	releases, err := s.db.GetModuleReleases(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load releases: %v", err))
+	}
+
+	var notes []formatter.ReleaseNotes
+	for _, rel := range releases {
+		if util.CompareVersions(rel.Version, from) < 0 || util.CompareVersions(rel.Version, to) > 0 {
+			continue
+		}
+		entries, err := s.db.GetModuleReleaseEntries(rel.ID)
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Failed to load release entries: %v", err))
+		}
+		notes = append(notes, formatter.ReleaseNotes{Release: rel, Entries: entries})
+	}
+	if len(notes) == 0 {
+		return ErrorResponse(fmt.Sprintf("No releases of %s found between %s and %s", module.Name, from, to))
+	}
+
+	name := module.FullName
+	if name == "" {
+		name = module.Name
+	}
+	return SuccessResponse(formatter.ChangelogRange(name, from, to, rangeCompareURL(module, notes), notes))
+}
+
+// rangeCompareURL links the GitHub diff covering every release in notes, from
+// the release before the oldest one up to the newest one.
+func rangeCompareURL(module *database.Module, notes []formatter.ReleaseNotes) string {
+	newest, oldest := notes[0].Release, notes[len(notes)-1].Release
+	if module.RepoURL == "" || !oldest.PreviousTag.Valid || newest.Tag == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/compare/%s...%s", strings.TrimSuffix(module.RepoURL, "/"), oldest.PreviousTag.String, newest.Tag)
+}
+
+var (
+	readmeHeading          = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*$`)
+	migrationHeadingMarker = regexp.MustCompile(`(?i)\b(migrat\w*|upgrad\w*)\b`)
+	breakingEntryMarker    = regexp.MustCompile(`(?i)\bbreaking\b`)
+	htmlCommentLine        = regexp.MustCompile(`^<!--.*-->$`)
+)
+
+func (s *Server) handleGetMigrationNotes(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	files, err := s.db.GetModuleFiles(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Error getting files: %v", err))
+	}
+
+	rootPrefix := moduleRootPrefix(module.Name)
+	readme := module.ReadmeContent
+	if strings.TrimSpace(readme) == "" {
+		for _, f := range files {
+			if strings.EqualFold(f.FilePath, rootPrefix+"README.md") {
+				readme = f.Content
+				break
+			}
+		}
+	}
+
+	releases, err := s.db.GetModuleReleases(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load releases: %v", err))
+	}
+
+	var breaking []formatter.BreakingChange
+	for _, release := range releases {
+		entries, err := s.db.GetModuleReleaseEntries(release.ID)
+		if err != nil {
+			return ErrorResponse(fmt.Sprintf("Failed to load release entries: %v", err))
+		}
+		for _, entry := range entries {
+			if !isBreakingEntry(entry) {
+				continue
+			}
+			breaking = append(breaking, formatter.BreakingChange{
+				Version: release.Version,
+				Date:    release.ReleaseDate.String,
+				Title:   entry.Title,
+			})
+		}
+	}
+
+	migrations, err := s.db.GetModuleStateMigrations(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load state migrations: %v", err))
+	}
+
+	notes := formatter.MigrationNotesReport{
+		StateBlocks:     migrations,
+		BreakingChanges: breaking,
+		ReadmeSections:  migrationReadmeSections(readme),
+		ReleaseCount:    len(releases),
+	}
+	return SuccessResponse(formatter.MigrationNotes(module.Name, notes))
+}
+
+func (s *Server) handleGetModuleStateMigrations(args any) map[string]any {
+	if err := s.ensureDB(); err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to initialize database: %v", err))
+	}
+
+	params, err := UnmarshalArgs[moduleNameArgs](args)
+	if err != nil || strings.TrimSpace(params.ModuleName) == "" {
+		return ErrorResponse("module_name is required")
+	}
+
+	module, err := s.resolveModule(params.ModuleName)
+	if err != nil {
+		return moduleNotFound(params.ModuleName, err)
+	}
+
+	migrations, err := s.db.GetModuleStateMigrations(module.ID)
+	if err != nil {
+		return ErrorResponse(fmt.Sprintf("Failed to load state migrations: %v", err))
+	}
+
+	return SuccessResponse(formatter.StateMigrations(module.Name, migrations))
+}
+
+func isBreakingEntry(entry database.ModuleReleaseEntry) bool {
+	if entry.ChangeType.String == "breaking_change" {
+		return true
+	}
+	return breakingEntryMarker.MatchString(entry.Section) || breakingEntryMarker.MatchString(entry.Title)
+}
+
+// migrationReadmeSections returns README sections whose heading mentions a
+// migration or upgrade.
+func migrationReadmeSections(readme string) []formatter.ReadmeSection {
+	return readmeSections(readme, migrationHeadingMarker.MatchString)
+}
+
+// readmeSections returns the README sections whose heading satisfies match,
+// each running until the next heading of the same or a higher level. Nested
+// headings stay part of the enclosing section.
+func readmeSections(readme string, match func(heading string) bool) []formatter.ReadmeSection {
+	var (
+		sections []formatter.ReadmeSection
+		current  *formatter.ReadmeSection
+		level    int
+		body     []string
+		inFence  bool
+	)
+	flush := func() {
+		if current != nil {
+			current.Content = strings.TrimSpace(strings.Join(body, "\n"))
+			sections = append(sections, *current)
+		}
+		current, body = nil, nil
+	}
+
+	for _, line := range strings.Split(readme, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if !inFence {
+			if m := readmeHeading.FindStringSubmatch(trimmed); m != nil {
+				headingLevel := len(m[1])
+				if current != nil && headingLevel <= level {
+					flush()
+				}
+				if current == nil && match(m[2]) {
+					current = &formatter.ReadmeSection{Heading: m[2]}
+					level = headingLevel
+					continue
+				}
+			}
+		}
+		if current != nil && !htmlCommentLine.MatchString(trimmed) {
+			body = append(body, strings.TrimRight(line, " \t\r"))
+		}
+	}
+	flush()
+	return sections
 }
@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/database"
+	"github.com/cloudnationhq/az-cn-go-wammcp/internal/indexer"
+)
+
+func TestFileMatchesTokens(t *testing.T) {
+	tests := []struct {
+		filename string
+		patch    string
+		tokens   []string
+		want     bool
+	}{
+		{"modules/name/main.tf", "", []string{"name"}, true},
+		{"namespace.tf", "", []string{"name"}, false},
+		{"main.tf", "+  name = var.name", []string{"name"}, true},
+		{"main.tf", "+  namespace = var.namespace", []string{"name"}, false},
+		{"main.tf", "+resource \"azurerm_subnet\" \"this\" {", []string{"subnet"}, true},
+		{"main.tf", "+  subnets = {}", []string{"subnet"}, false},
+		{"private_endpoint.tf", "", []string{"private_endpoint"}, true},
+	}
+
+	for _, tt := range tests {
+		file := indexer.GitHubCompareFile{Filename: tt.filename, Patch: tt.patch}
+		if got := fileMatchesTokens(file, tt.tokens); got != tt.want {
+			t.Errorf("fileMatchesTokens(%q, %q, %v) = %v, want %v", tt.filename, tt.patch, tt.tokens, got, tt.want)
+		}
+	}
+}
+
+func TestCompareResourceSets(t *testing.T) {
+	resource := func(provider, resourceType, name, file string) database.ModuleResource {
+		return database.ModuleResource{Provider: provider, ResourceType: resourceType, ResourceName: name, SourceFile: file}
+	}
+	older := []database.ModuleResource{
+		resource("azurerm", "azurerm_linux_virtual_machine", "vm", "main.tf"),
+		resource("azurerm", "azurerm_storage_account", "this", "main.tf"),
+		resource("azurerm", "azurerm_key_vault", "kv", "main.tf"),
+		resource("azurerm", "azurerm_subnet", "this", "network.tf"),
+	}
+	newer := []database.ModuleResource{
+		resource("azurerm", "azurerm_windows_virtual_machine", "vm", "main.tf"),
+		resource("azurerm", "azurerm_storage_account", "this", "main.tf"),
+		resource("random", "random_password", "kv", "main.tf"),
+		resource("azurerm", "azurerm_virtual_network", "this", "modules/network/main.tf"),
+	}
+
+	c := compareResourceSets(older, newer)
+
+	if len(c.TypeChanged) != 1 || c.TypeChanged[0].From.Address != "azurerm_linux_virtual_machine.vm" {
+		t.Errorf("TypeChanged = %+v, want only the vm type change", c.TypeChanged)
+	}
+	if len(c.Removed) != 2 || len(c.Added) != 2 {
+		t.Errorf("Removed = %+v, Added = %+v, want two of each", c.Removed, c.Added)
+	}
+	if c.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want 1", c.Unchanged)
+	}
+}